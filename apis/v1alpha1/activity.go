@@ -23,6 +23,8 @@ import (
 // ActivitySpec defines the desired state of Activity.
 type ActivitySpec struct {
 
+	// Settings to configure server-side encryption.
+	EncryptionConfiguration *EncryptionConfiguration `json:"encryptionConfiguration,omitempty"`
 	// The name of the activity to create. This name must be unique for your Amazon
 	// Web Services account and region for 90 days. For more information, see Limits
 	// Related to State Machine Executions (https://docs.aws.amazon.com/step-functions/latest/dg/limits.html#service-limits-state-machine-executions)
@@ -0,0 +1,100 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v1alpha1
+
+import (
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CronExecutionSpec defines the desired state of CronExecution.
+type CronExecutionSpec struct {
+
+	// Specifies how to treat concurrent executions: Allow starts executions
+	// concurrently, Forbid skips a scheduled execution while the previous one
+	// is still running, and Replace stops the running execution before
+	// starting the new one. Defaults to Allow.
+	// +kubebuilder:validation:Enum=Allow;Forbid;Replace
+	ConcurrencyPolicy *string `json:"concurrencyPolicy,omitempty"`
+	// The Execution created on each scheduled time.
+	// +kubebuilder:validation:Required
+	ExecutionTemplate *ExecutionTemplate `json:"executionTemplate"`
+	// The number of failed, timed out or aborted Executions to keep. Defaults
+	// to 1.
+	// +kubebuilder:validation:Minimum=0
+	FailedExecutionsHistoryLimit *int64 `json:"failedExecutionsHistoryLimit,omitempty"`
+	// The schedule in cron format, see https://en.wikipedia.org/wiki/Cron.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Schedule *string `json:"schedule"`
+	// The deadline in seconds for starting an execution that missed its
+	// scheduled time for any reason, e.g. the controller being down. Missed
+	// executions past the deadline are skipped.
+	// +kubebuilder:validation:Minimum=0
+	StartingDeadlineSeconds *int64 `json:"startingDeadlineSeconds,omitempty"`
+	// The number of succeeded Executions to keep. Defaults to 3.
+	// +kubebuilder:validation:Minimum=0
+	SuccessfulExecutionsHistoryLimit *int64 `json:"successfulExecutionsHistoryLimit,omitempty"`
+	// Suspends subsequent executions when true. Running executions are not
+	// affected. Defaults to false.
+	Suspend *bool `json:"suspend,omitempty"`
+	// The IANA name of the time zone the schedule is interpreted in, e.g.
+	// Europe/Paris. Defaults to UTC.
+	TimeZone *string `json:"timeZone,omitempty"`
+}
+
+// CronExecutionStatus defines the observed state of CronExecution
+type CronExecutionStatus struct {
+	// The names of the Executions that are still running.
+	// +kubebuilder:validation:Optional
+	Active []*string `json:"active,omitempty"`
+	// Conditions describe why executions are not started, i.e. an invalid
+	// schedule or a state machine reference that cannot be resolved.
+	// +kubebuilder:validation:Optional
+	Conditions []*ackv1alpha1.Condition `json:"conditions"`
+	// The last time an Execution was scheduled.
+	// +kubebuilder:validation:Optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// The last time an Execution succeeded.
+	// +kubebuilder:validation:Optional
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+	// The next time an Execution is scheduled, unless the CronExecution is
+	// suspended.
+	// +kubebuilder:validation:Optional
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+}
+
+// CronExecution is the Schema for the CronExecutions API. A CronExecution
+// starts Executions of a state machine on a cron schedule, like a CronJob
+// creates Jobs.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+type CronExecution struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              CronExecutionSpec   `json:"spec,omitempty"`
+	Status            CronExecutionStatus `json:"status,omitempty"`
+}
+
+// CronExecutionList contains a list of CronExecution
+// +kubebuilder:object:root=true
+type CronExecutionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CronExecution `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CronExecution{}, &CronExecutionList{})
+}
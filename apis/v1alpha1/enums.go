@@ -37,15 +37,15 @@ const (
 	ExecutionRedriveStatus_REDRIVABLE_BY_MAP_RUN ExecutionRedriveStatus = "REDRIVABLE_BY_MAP_RUN"
 )
 
-type ExecutionStatus string
+type ExecutionStatus_SDK string
 
 const (
-	ExecutionStatus_ABORTED         ExecutionStatus = "ABORTED"
-	ExecutionStatus_FAILED          ExecutionStatus = "FAILED"
-	ExecutionStatus_PENDING_REDRIVE ExecutionStatus = "PENDING_REDRIVE"
-	ExecutionStatus_RUNNING         ExecutionStatus = "RUNNING"
-	ExecutionStatus_SUCCEEDED       ExecutionStatus = "SUCCEEDED"
-	ExecutionStatus_TIMED_OUT       ExecutionStatus = "TIMED_OUT"
+	ExecutionStatus_SDK_ABORTED         ExecutionStatus_SDK = "ABORTED"
+	ExecutionStatus_SDK_FAILED          ExecutionStatus_SDK = "FAILED"
+	ExecutionStatus_SDK_PENDING_REDRIVE ExecutionStatus_SDK = "PENDING_REDRIVE"
+	ExecutionStatus_SDK_RUNNING         ExecutionStatus_SDK = "RUNNING"
+	ExecutionStatus_SDK_SUCCEEDED       ExecutionStatus_SDK = "SUCCEEDED"
+	ExecutionStatus_SDK_TIMED_OUT       ExecutionStatus_SDK = "TIMED_OUT"
 )
 
 type HistoryEventType string
@@ -18,15 +18,51 @@ package v1alpha1
 import (
 	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // StateMachineSpec defines the desired state of StateMachine.
+// +kubebuilder:validation:XValidation:rule="[has(self.definition), has(self.definitionFrom), has(self.definitionObject)].filter(x, x).size() == 1",message="exactly one of definition, definitionFrom or definitionObject must be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.logGroupGeneration) || !has(self.loggingConfiguration) || !has(self.loggingConfiguration.destinations) || size(self.loggingConfiguration.destinations) == 0",message="logGroupGeneration cannot be set with loggingConfiguration.destinations"
+// +kubebuilder:validation:XValidation:rule="!has(self.executionRoleGeneration) || (!has(self.roleARN) && !has(self.roleRef) && !has(self.roleServiceAccountRef))",message="executionRoleGeneration cannot be set with roleARN, roleRef or roleServiceAccountRef"
+// +kubebuilder:validation:XValidation:rule="[has(self.roleARN), has(self.roleRef), has(self.roleServiceAccountRef)].filter(x, x).size() <= 1",message="only one of roleARN, roleRef or roleServiceAccountRef can be set"
+// +kubebuilder:validation:XValidation:rule="has(self.roleARN) || has(self.roleRef) || has(self.roleServiceAccountRef) || has(self.executionRoleGeneration)",message="one of roleARN, roleRef, roleServiceAccountRef or executionRoleGeneration must be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.resourceRefs) || !has(self.definitionSubstitutions) || self.resourceRefs.all(r, !(r.placeholder in self.definitionSubstitutions))",message="a placeholder cannot be set in both resourceRefs and definitionSubstitutions"
 type StateMachineSpec struct {
 
 	// The Amazon States Language definition of the state machine. See Amazon States
 	// Language (https://docs.aws.amazon.com/step-functions/latest/dg/concepts-amazon-states-language.html).
-	// +kubebuilder:validation:Required
-	Definition *string `json:"definition"`
+	Definition *string `json:"definition,omitempty"`
+	// Reads the definition of the state machine from a ConfigMap or Secret key.
+	// Mutually exclusive with Definition and DefinitionObject.
+	DefinitionFrom *DefinitionSource `json:"definitionFrom,omitempty"`
+	// The Amazon States Language definition of the state machine as a native
+	// YAML or JSON object. Mutually exclusive with Definition and DefinitionFrom.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	DefinitionObject *runtime.RawExtension `json:"definitionObject,omitempty"`
+	// Values substituted for the ${Key} placeholders of the definition before
+	// it is sent to Step Functions, as with the DefinitionSubstitutions of
+	// AWS::StepFunctions::StateMachine. Values are JSON escaped inside strings
+	// of the definition, and must be a JSON number, string, boolean or null
+	// elsewhere. A placeholder without a substitution blocks the creation or
+	// update of the state machine.
+	DefinitionSubstitutions map[string]*DefinitionSubstitution `json:"definitionSubstitutions,omitempty"`
+	// Configures which diagnostics of the pre-flight definition validation
+	// block the creation or update of the state machine.
+	DefinitionValidation *DefinitionValidationPolicy `json:"definitionValidation,omitempty"`
+	// Settings to configure server-side encryption.
+	EncryptionConfiguration *EncryptionConfiguration `json:"encryptionConfiguration,omitempty"`
+	// Generates a least-privilege execution role from the Task states of the
+	// definition instead of using RoleARN or RoleRef. The generated Role and
+	// Policy are updated with the definition and deleted with the state
+	// machine. Requires the iam-controller.
+	ExecutionRoleGeneration *ExecutionRoleGeneration `json:"executionRoleGeneration,omitempty"`
+	// Creates a CloudWatch Logs log group for the state machine and uses it as
+	// the logging destination when LoggingConfiguration.Level is not OFF, or
+	// the state machine is EXPRESS, and LoggingConfiguration has no
+	// destination. The log group is deleted with the state machine. Requires
+	// the cloudwatchlogs-controller.
+	LogGroupGeneration *LogGroupGeneration `json:"logGroupGeneration,omitempty"`
 	// Defines what execution history events are logged and where they are logged.
 	//
 	// By default, the level is set to OFF. For more information see Log Levels
@@ -42,9 +78,23 @@ type StateMachineSpec struct {
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable once set"
 	// +kubebuilder:validation:Required
 	Name *string `json:"name"`
+	// Set to true to publish a new version of the state machine when it is
+	// created and every time its definition or configuration is updated. The
+	// default is false.
+	Publish *bool `json:"publish,omitempty"`
+	// References to ACK resources whose ARNs replace placeholders of the
+	// definition. The state machine is not synced until every referenced
+	// resource is synced.
+	// +kubebuilder:validation:MaxItems=100
+	// +kubebuilder:validation:XValidation:rule="self.all(x, self.exists_one(y, y.placeholder == x.placeholder))",message="resourceRefs placeholders must be unique"
+	ResourceRefs []*DefinitionResourceReference `json:"resourceRefs,omitempty"`
 	// The Amazon Resource Name (ARN) of the IAM role to use for this state machine.
 	RoleARN *string                                  `json:"roleARN,omitempty"`
 	RoleRef *ackv1alpha1.AWSResourceReferenceWrapper `json:"roleRef,omitempty"`
+	// Uses the IAM role of a ServiceAccount, set with the eks.amazonaws.com/role-arn
+	// annotation, as the execution role. The role must also trust
+	// states.amazonaws.com.
+	RoleServiceAccountRef *ServiceAccountReference `json:"roleServiceAccountRef,omitempty"`
 	// Tags to be added when creating a state machine.
 	//
 	// An array of key-value pairs. For more information, see Using Cost Allocation
@@ -61,6 +111,14 @@ type StateMachineSpec struct {
 	// is STANDARD. You cannot update the type of a state machine once it has been
 	// created.
 	Type *string `json:"type_,omitempty"`
+	// Sets description about the state machine version. You can only set the description
+	// if the publish parameter is set to true. Otherwise, if you set versionDescription,
+	// but publish to false, this API action throws ValidationException.
+	VersionDescription *string `json:"versionDescription,omitempty"`
+	// Deletes older published versions of the state machine each time the
+	// resource is reconciled, including on resync. A version is deleted once
+	// it is neither one of the last KeepLast versions nor younger than MaxAge.
+	VersionRetention *VersionRetentionPolicy `json:"versionRetention,omitempty"`
 }
 
 // StateMachineStatus defines the observed state of StateMachine
@@ -79,6 +137,34 @@ type StateMachineStatus struct {
 	// The date the state machine is created.
 	// +kubebuilder:validation:Optional
 	CreationDate *metav1.Time `json:"creationDate,omitempty"`
+	// The diagnostics found by the offline definition linter and by
+	// ValidateStateMachineDefinition for the last validated definition.
+	// +kubebuilder:validation:Optional
+	DefinitionDiagnostics []*ValidateStateMachineDefinitionDiagnostic `json:"definitionDiagnostics,omitempty"`
+	// The values of Spec.DefinitionSubstitutions and the ARNs of Spec.ResourceRefs
+	// used to render the definition, by placeholder.
+	// +kubebuilder:validation:Optional
+	DefinitionSubstitutionValues map[string]*string `json:"definitionSubstitutionValues,omitempty"`
+	// The Amazon Resource Name (ARN) of the most recently published version of
+	// the state machine.
+	// +kubebuilder:validation:Optional
+	LatestVersionARN *string `json:"latestVersionARN,omitempty"`
+	// The last value of the sfn.services.k8s.aws/redrive-failed-executions-since
+	// annotation the failed executions of the state machine were redriven for.
+	// +kubebuilder:validation:Optional
+	ObservedRedriveSince *string `json:"observedRedriveSince,omitempty"`
+	// The Amazon Resource Name (ARN) of the execution role generated from
+	// Spec.ExecutionRoleGeneration, or read from the ServiceAccount of
+	// Spec.RoleServiceAccountRef.
+	// +kubebuilder:validation:Optional
+	ResolvedRoleARN *string `json:"resolvedRoleARN,omitempty"`
+	// The revision identifier for the state machine.
+	//
+	// Use the revisionId parameter to compare between versions of a state machine
+	// configuration used for executions without performing a diff of the properties,
+	// such as definition and roleArn.
+	// +kubebuilder:validation:Optional
+	RevisionID *string `json:"revisionID,omitempty"`
 }
 
 // StateMachine is the Schema for the StateMachines API
@@ -32,13 +32,27 @@ type StateMachineAliasSpec struct {
 	//
 	// Regex Pattern: `^(?=.*[a-zA-Z_\-\.])[a-zA-Z0-9_\-\.]+$`
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable once set"
+	// +kubebuilder:validation:XValidation:rule="!self.matches('^[0-9]+$')",message="alias name must not be an integer"
+	// +kubebuilder:validation:MaxLength=80
 	// +kubebuilder:validation:Required
 	Name *string `json:"name"`
+	// Shifts traffic progressively when RoutingConfiguration is changed to a
+	// single version with a weight of 100, instead of in a single update.
+	Rollout *RolloutStrategy `json:"rollout,omitempty"`
 	// The routing configuration of a state machine alias. The routing configuration
 	// shifts execution traffic between two state machine versions. routingConfiguration
 	// contains an array of RoutingConfig objects that specify up to two state machine
 	// versions. Step Functions then randomly choses which version to run an execution
 	// with based on the weight assigned to each RoutingConfig.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=2
+	// +kubebuilder:validation:XValidation:rule="self.size() != 1 || !has(self[0].weight) || self[0].weight == 100",message="the weight of a single routing configuration entry must be 100"
+	// +kubebuilder:validation:XValidation:rule="self.size() != 2 || !has(self[0].weight) || !has(self[1].weight) || self[0].weight + self[1].weight == 100",message="routing configuration weights must sum to 100"
+	// +kubebuilder:validation:XValidation:rule="self.size() != 2 || !has(self[0].stateMachineVersionARN) || !has(self[1].stateMachineVersionARN) || self[0].stateMachineVersionARN != self[1].stateMachineVersionARN",message="routing configuration entries must route to different versions"
+	// +kubebuilder:validation:XValidation:rule="self.size() != 2 || !has(self[0].stateMachineVersionRef) || !has(self[1].stateMachineVersionRef) || self[0].stateMachineVersionRef != self[1].stateMachineVersionRef",message="routing configuration entries must route to different versions"
+	// +kubebuilder:validation:XValidation:rule="self.size() != 2 || !has(self[0].versionNumber) || !has(self[1].versionNumber) || self[0].versionNumber != self[1].versionNumber",message="routing configuration entries must route to different versions"
+	// +kubebuilder:validation:XValidation:rule="self.size() != 2 || !has(self[0].stateMachineRef) || !has(self[1].stateMachineRef) || self[0].stateMachineRef == self[1].stateMachineRef",message="routing configuration entries must route to versions of the same state machine"
+	// +kubebuilder:validation:XValidation:rule="self.size() != 2 || !has(self[0].stateMachineVersionARN) || !has(self[1].stateMachineVersionARN) || self[0].stateMachineVersionARN.substring(0, self[0].stateMachineVersionARN.lastIndexOf(':')) == self[1].stateMachineVersionARN.substring(0, self[1].stateMachineVersionARN.lastIndexOf(':'))",message="routing configuration entries must route to versions of the same state machine"
 	// +kubebuilder:validation:Required
 	RoutingConfiguration []*RoutingConfigurationListItem `json:"routingConfiguration"`
 }
@@ -59,6 +73,9 @@ type StateMachineAliasStatus struct {
 	// The date the state machine alias was created.
 	// +kubebuilder:validation:Optional
 	CreationDate *metav1.Time `json:"creationDate,omitempty"`
+	// The progress of the current or last traffic shifting rollout.
+	// +kubebuilder:validation:Optional
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
 }
 
 // StateMachineAlias is the Schema for the StateMachineAliases API
@@ -40,8 +40,89 @@ type ActivityScheduledEventDetails struct {
 	Resource *string `json:"resource,omitempty"`
 }
 
+// An object that describes workflow billing details.
+type BillingDetails struct {
+	BilledDurationInMilliseconds *int64 `json:"billedDurationInMilliseconds,omitempty"`
+	BilledMemoryUsedInMB         *int64 `json:"billedMemoryUsedInMB,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:rule="!(has(self.logGroupARN) && has(self.logGroupRef))",message="only one of logGroupARN or logGroupRef can be set"
 type CloudWatchLogsLogGroup struct {
-	LogGroupARN *string `json:"logGroupARN,omitempty"`
+	LogGroupARN *string                                  `json:"logGroupARN,omitempty"`
+	LogGroupRef *ackv1alpha1.AWSResourceReferenceWrapper `json:"logGroupRef,omitempty"`
+}
+
+// Selects a field of a ConfigMap, Activity or StateMachine in the namespace
+// of the state machine. The state machine is updated whenever the object
+// changes.
+// +kubebuilder:validation:XValidation:rule="(self.apiVersion == 'v1' && self.kind == 'ConfigMap') || (self.apiVersion == 'sfn.services.k8s.aws/v1alpha1' && self.kind in ['Activity', 'StateMachine'])",message="fieldRef must select a v1 ConfigMap, or an sfn.services.k8s.aws/v1alpha1 Activity or StateMachine"
+type DefinitionFieldSelector struct {
+	// +kubebuilder:validation:Required
+	APIVersion *string `json:"apiVersion"`
+	// Path of the field, for example "status.ackResourceMetadata.arn". The
+	// field must hold a string, number or boolean.
+	// +kubebuilder:validation:Required
+	FieldPath *string `json:"fieldPath"`
+	// +kubebuilder:validation:Required
+	Kind *string `json:"kind"`
+	// +kubebuilder:validation:Required
+	Name *string `json:"name"`
+}
+
+// Selects a key of a ConfigMap or Secret in the namespace of the state
+// machine.
+type DefinitionKeySelector struct {
+	// +kubebuilder:validation:Required
+	Key *string `json:"key"`
+	// +kubebuilder:validation:Required
+	Name *string `json:"name"`
+}
+
+// Reads the Amazon States Language definition of the state machine from a
+// ConfigMap or a Secret. The state machine is updated whenever the
+// referenced data changes.
+// +kubebuilder:validation:XValidation:rule="has(self.configMapKeyRef) != has(self.secretKeyRef)",message="exactly one of configMapKeyRef or secretKeyRef must be set"
+type DefinitionSource struct {
+	ConfigMapKeyRef *DefinitionKeySelector `json:"configMapKeyRef,omitempty"`
+	SecretKeyRef    *DefinitionKeySelector `json:"secretKeyRef,omitempty"`
+}
+
+// A reference to an ACK resource whose ARN, read from
+// status.ackResourceMetadata.arn, replaces the ${Placeholder} placeholder of
+// the definition.
+type DefinitionResourceReference struct {
+	// +kubebuilder:validation:Required
+	From *ackv1alpha1.AWSResourceReference `json:"from"`
+	// The kind of the referenced resource: a lambda-controller Function, an
+	// sqs-controller Queue, an sns-controller Topic, a dynamodb-controller
+	// Table, or an Activity or StateMachine of this controller.
+	// +kubebuilder:validation:Enum=Activity;Function;Queue;StateMachine;Table;Topic
+	// +kubebuilder:validation:Required
+	Kind *string `json:"kind"`
+	// +kubebuilder:validation:Required
+	Placeholder *string `json:"placeholder"`
+}
+
+// The value substituted for a ${Key} placeholder of the definition, either a
+// literal value, a ConfigMap key or a field of another Kubernetes object.
+// +kubebuilder:validation:XValidation:rule="[has(self.configMapKeyRef), has(self.fieldRef), has(self.value)].filter(x, x).size() == 1",message="exactly one of configMapKeyRef, fieldRef or value must be set"
+type DefinitionSubstitution struct {
+	ConfigMapKeyRef *DefinitionKeySelector   `json:"configMapKeyRef,omitempty"`
+	FieldRef        *DefinitionFieldSelector `json:"fieldRef,omitempty"`
+	Value           *string                  `json:"value,omitempty"`
+}
+
+// Configures how the diagnostics returned by ValidateStateMachineDefinition
+// are handled before the state machine is created or updated.
+type DefinitionValidationPolicy struct {
+	// Skips the offline ASL linter, for definitions it reports false positives
+	// for. The definition is then only validated by
+	// ValidateStateMachineDefinition.
+	DisableLinter *bool `json:"disableLinter,omitempty"`
+	// The lowest severity of the diagnostics that block the creation or update
+	// of the state machine, either ERROR or WARNING. Defaults to ERROR.
+	// +kubebuilder:validation:Enum=ERROR;WARNING
+	SeverityThreshold *string `json:"severityThreshold,omitempty"`
 }
 
 // Settings to configure server-side encryption.
@@ -60,9 +141,24 @@ type CloudWatchLogsLogGroup struct {
 //
 // For more information on KMS, see What is Key Management Service? (https://docs.aws.amazon.com/kms/latest/developerguide/overview.html)
 type EncryptionConfiguration struct {
-	KMSDataKeyReusePeriodSeconds *int64  `json:"kmsDataKeyReusePeriodSeconds,omitempty"`
-	KMSKeyID                     *string `json:"kmsKeyID,omitempty"`
-	Type                         *string `json:"type_,omitempty"`
+	KMSDataKeyReusePeriodSeconds *int64                                   `json:"kmsDataKeyReusePeriodSeconds,omitempty"`
+	KMSKeyID                     *string                                  `json:"kmsKeyID,omitempty"`
+	KMSKeyRef                    *ackv1alpha1.AWSResourceReferenceWrapper `json:"kmsKeyRef,omitempty"`
+	Type                         *string                                  `json:"type_,omitempty"`
+}
+
+// Contains details about an execution failure event.
+type ExecutionFailedEventDetails struct {
+	Cause *string `json:"cause,omitempty"`
+	Error *string `json:"error,omitempty"`
+}
+
+// Reads the input of an execution from a ConfigMap or a Secret. The input is
+// read once, when the execution is started.
+// +kubebuilder:validation:XValidation:rule="has(self.configMapKeyRef) != has(self.secretKeyRef)",message="exactly one of configMapKeyRef or secretKeyRef must be set"
+type ExecutionInputSource struct {
+	ConfigMapKeyRef *DefinitionKeySelector `json:"configMapKeyRef,omitempty"`
+	SecretKeyRef    *DefinitionKeySelector `json:"secretKeyRef,omitempty"`
 }
 
 // Contains details about an execution.
@@ -78,6 +174,19 @@ type ExecutionListItem struct {
 	StopDate               *metav1.Time `json:"stopDate,omitempty"`
 }
 
+// Configures the execution role the controller generates for the state
+// machine from the Task states of its definition. The role is an iam-controller
+// Role trusted by states.amazonaws.com, with an iam-controller Policy that
+// allows only the integrations and resources used by the definition.
+type ExecutionRoleGeneration struct {
+	// The ARNs of managed policies also attached to the generated role, for
+	// permissions that cannot be derived from the definition.
+	AdditionalPolicies []*string `json:"additionalPolicies,omitempty"`
+	// The ARN of the managed policy used as the permissions boundary of the
+	// generated role.
+	PermissionsBoundary *string `json:"permissionsBoundary,omitempty"`
+}
+
 // Contains details about the start of the execution.
 type ExecutionStartedEventDetails struct {
 	RoleARN                *string `json:"roleARN,omitempty"`
@@ -85,9 +194,45 @@ type ExecutionStartedEventDetails struct {
 	StateMachineVersionARN *string `json:"stateMachineVersionARN,omitempty"`
 }
 
+// Describes the Executions created by a CronExecution.
+type ExecutionTemplate struct {
+	// Annotations added to the created Executions.
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Labels added to the created Executions.
+	Labels map[string]string `json:"labels,omitempty"`
+	// +kubebuilder:validation:Required
+	Spec *ExecutionTemplateSpec `json:"spec"`
+}
+
+// The spec of the Executions created by a CronExecution. Executions are
+// named after the UID of the created Execution, so that every scheduled
+// execution has a unique name.
+// +kubebuilder:validation:XValidation:rule="[has(self.stateMachineARN), has(self.stateMachineRef), has(self.stateMachineAliasRef), has(self.stateMachineVersionRef)].filter(x, x).size() == 1",message="exactly one of stateMachineARN, stateMachineRef, stateMachineAliasRef or stateMachineVersionRef must be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.input) || !has(self.inputFrom)",message="input cannot be set with inputFrom"
+type ExecutionTemplateSpec struct {
+	Input     *string               `json:"input,omitempty"`
+	InputFrom *ExecutionInputSource `json:"inputFrom,omitempty"`
+	// +kubebuilder:validation:Enum=Async;Sync
+	Mode                   *string                                  `json:"mode,omitempty"`
+	StateMachineARN        *string                                  `json:"stateMachineARN,omitempty"`
+	StateMachineAliasRef   *ackv1alpha1.AWSResourceReferenceWrapper `json:"stateMachineAliasRef,omitempty"`
+	StateMachineRef        *ackv1alpha1.AWSResourceReferenceWrapper `json:"stateMachineRef,omitempty"`
+	StateMachineVersionRef *ackv1alpha1.AWSResourceReferenceWrapper `json:"stateMachineVersionRef,omitempty"`
+	TraceHeader            *string                                  `json:"traceHeader,omitempty"`
+}
+
 // Contains details about the events of an execution.
 type HistoryEvent struct {
-	Timestamp *metav1.Time `json:"timestamp,omitempty"`
+	ExecutionFailedEventDetails *ExecutionFailedEventDetails `json:"executionFailedEventDetails,omitempty"`
+	ID                          *int64                       `json:"id,omitempty"`
+	MapRunStartedEventDetails   *MapRunStartedEventDetails   `json:"mapRunStartedEventDetails,omitempty"`
+	PreviousEventID             *int64                       `json:"previousEventID,omitempty"`
+	StateEnteredEventDetails    *StateEnteredEventDetails    `json:"stateEnteredEventDetails,omitempty"`
+	StateExitedEventDetails     *StateExitedEventDetails     `json:"stateExitedEventDetails,omitempty"`
+	TaskFailedEventDetails      *TaskFailedEventDetails      `json:"taskFailedEventDetails,omitempty"`
+	TaskTimedOutEventDetails    *TaskTimedOutEventDetails    `json:"taskTimedOutEventDetails,omitempty"`
+	Timestamp                   *metav1.Time                 `json:"timestamp,omitempty"`
+	Type                        *string                      `json:"type_,omitempty"`
 }
 
 // Contains details about a Lambda function scheduled during an execution.
@@ -99,6 +244,17 @@ type LogDestination struct {
 	CloudWatchLogsLogGroup *CloudWatchLogsLogGroup `json:"cloudWatchLogsLogGroup,omitempty"`
 }
 
+// Configures the CloudWatch Logs log group the controller creates for the
+// state machine when logging is enabled without a destination. The log group
+// is a cloudwatchlogs-controller LogGroup named
+// /aws/vendedlogs/states/<state machine name>.
+type LogGroupGeneration struct {
+	// The number of days log events are kept in the log group. By default,
+	// log events never expire.
+	// +kubebuilder:validation:Enum=1;3;5;7;14;30;60;90;120;150;180;365;400;545;731;1096;1827;2192;2557;2922;3288;3653
+	RetentionDays *int64 `json:"retentionDays,omitempty"`
+}
+
 // The LoggingConfiguration data type is used to set CloudWatch Logs options.
 type LoggingConfiguration struct {
 	Destinations         []*LogDestination `json:"destinations,omitempty"`
@@ -131,13 +287,86 @@ type MapRunStartedEventDetails struct {
 	MapRunARN *string `json:"mapRunARN,omitempty"`
 }
 
+// Configures when a state machine alias rollout is rolled back, based on the
+// executions of the new version started through the alias since the rollout
+// started.
+type RolloutRollbackPolicy struct {
+	// The percentage of failed, timed out and aborted executions above which
+	// the rollout is rolled back.
+	FailureThresholdPercentage *int64 `json:"failureThresholdPercentage,omitempty"`
+	// The minimum number of completed executions of the new version before
+	// the failure rate is evaluated. Defaults to 10.
+	MinimumExecutions *int64 `json:"minimumExecutions,omitempty"`
+}
+
+// Contains details about the progress of a state machine alias rollout.
+type RolloutStatus struct {
+	// The number of traffic shifting steps applied so far.
+	CurrentStep *int64 `json:"currentStep,omitempty"`
+	// The time at which the next traffic shifting step is applied. Not set once
+	// the rollout is complete.
+	NextTransitionTime *metav1.Time `json:"nextTransitionTime,omitempty"`
+	// Whether the rollout was rolled back because the failure rate of the new
+	// version exceeded the rollback threshold.
+	RolledBack *bool `json:"rolledBack,omitempty"`
+	// The version that served the traffic before the rollout started.
+	StableVersionARN *string `json:"stableVersionARN,omitempty"`
+	// The time at which the rollout started.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// The version traffic is being shifted to.
+	TargetVersionARN *string `json:"targetVersionARN,omitempty"`
+	// The percentage of traffic currently routed to TargetVersionARN.
+	TargetWeight *int64 `json:"targetWeight,omitempty"`
+}
+
+// Configures how execution traffic is shifted to a new state machine version
+// when the routing configuration of an alias is changed to route all of its
+// traffic to that version.
+type RolloutStrategy struct {
+	// The time to wait between two traffic shifting steps, for example "10m".
+	Interval *string `json:"interval,omitempty"`
+	// Restores the previous routing configuration when too many executions
+	// of the new version fail during the rollout.
+	Rollback *RolloutRollbackPolicy `json:"rollback,omitempty"`
+	// The percentage of traffic shifted to the new version at each step.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	StepPercentage *int64 `json:"stepPercentage,omitempty"`
+	// The rollout type. Canary shifts StepPercentage of the traffic to the
+	// new version and the remaining traffic after Interval. Linear shifts
+	// StepPercentage more traffic to the new version every Interval.
+	// +kubebuilder:validation:Enum=Canary;Linear
+	Type *string `json:"type,omitempty"`
+}
+
 // Contains details about the routing configuration of a state machine alias.
 // In a routing configuration, you define an array of objects that specify up
 // to two state machine versions. You also specify the percentage of traffic
 // to be routed to each version.
+// +kubebuilder:validation:XValidation:rule="[has(self.stateMachineVersionARN), has(self.stateMachineVersionRef), has(self.stateMachineRef)].filter(x, x).size() == 1",message="exactly one of stateMachineVersionARN, stateMachineVersionRef or stateMachineRef must be set"
+// +kubebuilder:validation:XValidation:rule="has(self.stateMachineRef) == has(self.versionNumber)",message="stateMachineRef and versionNumber must be set together"
 type RoutingConfigurationListItem struct {
-	StateMachineVersionARN *string `json:"stateMachineVersionARN,omitempty"`
-	Weight                 *int64  `json:"weight,omitempty"`
+	// The StateMachine to route traffic to, used together with VersionNumber
+	// as an alternative to StateMachineVersionARN or StateMachineVersionRef.
+	StateMachineRef *ackv1alpha1.AWSResourceReferenceWrapper `json:"stateMachineRef,omitempty"`
+	// +kubebuilder:validation:MaxLength=256
+	StateMachineVersionARN *string                                  `json:"stateMachineVersionARN,omitempty"`
+	StateMachineVersionRef *ackv1alpha1.AWSResourceReferenceWrapper `json:"stateMachineVersionRef,omitempty"`
+	// The version number of the StateMachine referenced by StateMachineRef.
+	// +kubebuilder:validation:Minimum=1
+	VersionNumber *int64 `json:"versionNumber,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Required
+	Weight *int64 `json:"weight"`
+}
+
+// Selects a ServiceAccount in the namespace of the state machine whose IAM
+// role for service accounts (IRSA), read from its eks.amazonaws.com/role-arn
+// annotation, is used as the execution role of the state machine.
+type ServiceAccountReference struct {
+	// +kubebuilder:validation:Required
+	Name *string `json:"name"`
 }
 
 // Contains details about a state entered during an execution.
@@ -192,6 +421,8 @@ type TaskCredentials struct {
 
 // Contains details about a task failure event.
 type TaskFailedEventDetails struct {
+	Cause        *string `json:"cause,omitempty"`
+	Error        *string `json:"error,omitempty"`
 	Resource     *string `json:"resource,omitempty"`
 	ResourceType *string `json:"resourceType,omitempty"`
 }
@@ -235,6 +466,8 @@ type TaskSucceededEventDetails struct {
 
 // Contains details about a resource timeout that occurred during an execution.
 type TaskTimedOutEventDetails struct {
+	Cause        *string `json:"cause,omitempty"`
+	Error        *string `json:"error,omitempty"`
 	Resource     *string `json:"resource,omitempty"`
 	ResourceType *string `json:"resourceType,omitempty"`
 }
@@ -244,3 +477,23 @@ type TaskTimedOutEventDetails struct {
 type TracingConfiguration struct {
 	Enabled *bool `json:"enabled,omitempty"`
 }
+
+// Describes an error found during validation. Validation errors found in the
+// definition return in the response as diagnostic elements, rather than raise
+// an exception.
+type ValidateStateMachineDefinitionDiagnostic struct {
+	Code     *string `json:"code,omitempty"`
+	Location *string `json:"location,omitempty"`
+	Message  *string `json:"message,omitempty"`
+	Severity *string `json:"severity,omitempty"`
+}
+
+// Configures how many published versions of a state machine are kept. Versions
+// that are referenced by a state machine alias or managed by a
+// StateMachineVersion resource are never deleted.
+type VersionRetentionPolicy struct {
+	// The number of most recent versions to keep.
+	KeepLast *int64 `json:"keepLast,omitempty"`
+	// Versions younger than this duration are kept, for example "720h".
+	MaxAge *string `json:"maxAge,omitempty"`
+}
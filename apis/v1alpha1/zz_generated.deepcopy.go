@@ -21,7 +21,7 @@ package v1alpha1
 
 import (
 	corev1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
-	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -135,36 +135,786 @@ func (in *ActivityScheduledEventDetails) DeepCopy() *ActivityScheduledEventDetai
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ActivitySpec) DeepCopyInto(out *ActivitySpec) {
 	*out = *in
+	if in.EncryptionConfiguration != nil {
+		in, out := &in.EncryptionConfiguration, &out.EncryptionConfiguration
+		*out = new(EncryptionConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]*Tag, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Tag)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActivitySpec.
+func (in *ActivitySpec) DeepCopy() *ActivitySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ActivitySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActivityStatus) DeepCopyInto(out *ActivityStatus) {
+	*out = *in
+	if in.ACKResourceMetadata != nil {
+		in, out := &in.ACKResourceMetadata, &out.ACKResourceMetadata
+		*out = new(corev1alpha1.ResourceMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]*corev1alpha1.Condition, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(corev1alpha1.Condition)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.CreationDate != nil {
+		in, out := &in.CreationDate, &out.CreationDate
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActivityStatus.
+func (in *ActivityStatus) DeepCopy() *ActivityStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ActivityStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BillingDetails) DeepCopyInto(out *BillingDetails) {
+	*out = *in
+	if in.BilledDurationInMilliseconds != nil {
+		in, out := &in.BilledDurationInMilliseconds, &out.BilledDurationInMilliseconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.BilledMemoryUsedInMB != nil {
+		in, out := &in.BilledMemoryUsedInMB, &out.BilledMemoryUsedInMB
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BillingDetails.
+func (in *BillingDetails) DeepCopy() *BillingDetails {
+	if in == nil {
+		return nil
+	}
+	out := new(BillingDetails)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudWatchLogsLogGroup) DeepCopyInto(out *CloudWatchLogsLogGroup) {
+	*out = *in
+	if in.LogGroupARN != nil {
+		in, out := &in.LogGroupARN, &out.LogGroupARN
+		*out = new(string)
+		**out = **in
+	}
+	if in.LogGroupRef != nil {
+		in, out := &in.LogGroupRef, &out.LogGroupRef
+		*out = new(corev1alpha1.AWSResourceReferenceWrapper)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudWatchLogsLogGroup.
+func (in *CloudWatchLogsLogGroup) DeepCopy() *CloudWatchLogsLogGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudWatchLogsLogGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CronExecution) DeepCopyInto(out *CronExecution) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CronExecution.
+func (in *CronExecution) DeepCopy() *CronExecution {
+	if in == nil {
+		return nil
+	}
+	out := new(CronExecution)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CronExecution) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CronExecutionList) DeepCopyInto(out *CronExecutionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CronExecution, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CronExecutionList.
+func (in *CronExecutionList) DeepCopy() *CronExecutionList {
+	if in == nil {
+		return nil
+	}
+	out := new(CronExecutionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CronExecutionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CronExecutionSpec) DeepCopyInto(out *CronExecutionSpec) {
+	*out = *in
+	if in.ConcurrencyPolicy != nil {
+		in, out := &in.ConcurrencyPolicy, &out.ConcurrencyPolicy
+		*out = new(string)
+		**out = **in
+	}
+	if in.ExecutionTemplate != nil {
+		in, out := &in.ExecutionTemplate, &out.ExecutionTemplate
+		*out = new(ExecutionTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FailedExecutionsHistoryLimit != nil {
+		in, out := &in.FailedExecutionsHistoryLimit, &out.FailedExecutionsHistoryLimit
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(string)
+		**out = **in
+	}
+	if in.StartingDeadlineSeconds != nil {
+		in, out := &in.StartingDeadlineSeconds, &out.StartingDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SuccessfulExecutionsHistoryLimit != nil {
+		in, out := &in.SuccessfulExecutionsHistoryLimit, &out.SuccessfulExecutionsHistoryLimit
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Suspend != nil {
+		in, out := &in.Suspend, &out.Suspend
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TimeZone != nil {
+		in, out := &in.TimeZone, &out.TimeZone
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CronExecutionSpec.
+func (in *CronExecutionSpec) DeepCopy() *CronExecutionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CronExecutionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CronExecutionStatus) DeepCopyInto(out *CronExecutionStatus) {
+	*out = *in
+	if in.Active != nil {
+		in, out := &in.Active, &out.Active
+		*out = make([]*string, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(string)
+				**out = **in
+			}
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]*corev1alpha1.Condition, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(corev1alpha1.Condition)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSuccessfulTime != nil {
+		in, out := &in.LastSuccessfulTime, &out.LastSuccessfulTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextScheduleTime != nil {
+		in, out := &in.NextScheduleTime, &out.NextScheduleTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CronExecutionStatus.
+func (in *CronExecutionStatus) DeepCopy() *CronExecutionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CronExecutionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefinitionFieldSelector) DeepCopyInto(out *DefinitionFieldSelector) {
+	*out = *in
+	if in.APIVersion != nil {
+		in, out := &in.APIVersion, &out.APIVersion
+		*out = new(string)
+		**out = **in
+	}
+	if in.FieldPath != nil {
+		in, out := &in.FieldPath, &out.FieldPath
+		*out = new(string)
+		**out = **in
+	}
+	if in.Kind != nil {
+		in, out := &in.Kind, &out.Kind
+		*out = new(string)
+		**out = **in
+	}
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefinitionFieldSelector.
+func (in *DefinitionFieldSelector) DeepCopy() *DefinitionFieldSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(DefinitionFieldSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefinitionKeySelector) DeepCopyInto(out *DefinitionKeySelector) {
+	*out = *in
+	if in.Key != nil {
+		in, out := &in.Key, &out.Key
+		*out = new(string)
+		**out = **in
+	}
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefinitionKeySelector.
+func (in *DefinitionKeySelector) DeepCopy() *DefinitionKeySelector {
+	if in == nil {
+		return nil
+	}
+	out := new(DefinitionKeySelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefinitionResourceReference) DeepCopyInto(out *DefinitionResourceReference) {
+	*out = *in
+	if in.From != nil {
+		in, out := &in.From, &out.From
+		*out = new(corev1alpha1.AWSResourceReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Kind != nil {
+		in, out := &in.Kind, &out.Kind
+		*out = new(string)
+		**out = **in
+	}
+	if in.Placeholder != nil {
+		in, out := &in.Placeholder, &out.Placeholder
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefinitionResourceReference.
+func (in *DefinitionResourceReference) DeepCopy() *DefinitionResourceReference {
+	if in == nil {
+		return nil
+	}
+	out := new(DefinitionResourceReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefinitionSource) DeepCopyInto(out *DefinitionSource) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(DefinitionKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(DefinitionKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefinitionSource.
+func (in *DefinitionSource) DeepCopy() *DefinitionSource {
+	if in == nil {
+		return nil
+	}
+	out := new(DefinitionSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefinitionSubstitution) DeepCopyInto(out *DefinitionSubstitution) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(DefinitionKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FieldRef != nil {
+		in, out := &in.FieldRef, &out.FieldRef
+		*out = new(DefinitionFieldSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefinitionSubstitution.
+func (in *DefinitionSubstitution) DeepCopy() *DefinitionSubstitution {
+	if in == nil {
+		return nil
+	}
+	out := new(DefinitionSubstitution)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefinitionValidationPolicy) DeepCopyInto(out *DefinitionValidationPolicy) {
+	*out = *in
+	if in.DisableLinter != nil {
+		in, out := &in.DisableLinter, &out.DisableLinter
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SeverityThreshold != nil {
+		in, out := &in.SeverityThreshold, &out.SeverityThreshold
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefinitionValidationPolicy.
+func (in *DefinitionValidationPolicy) DeepCopy() *DefinitionValidationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(DefinitionValidationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EncryptionConfiguration) DeepCopyInto(out *EncryptionConfiguration) {
+	*out = *in
+	if in.KMSDataKeyReusePeriodSeconds != nil {
+		in, out := &in.KMSDataKeyReusePeriodSeconds, &out.KMSDataKeyReusePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.KMSKeyID != nil {
+		in, out := &in.KMSKeyID, &out.KMSKeyID
+		*out = new(string)
+		**out = **in
+	}
+	if in.KMSKeyRef != nil {
+		in, out := &in.KMSKeyRef, &out.KMSKeyRef
+		*out = new(corev1alpha1.AWSResourceReferenceWrapper)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Type != nil {
+		in, out := &in.Type, &out.Type
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EncryptionConfiguration.
+func (in *EncryptionConfiguration) DeepCopy() *EncryptionConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(EncryptionConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Execution) DeepCopyInto(out *Execution) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Execution.
+func (in *Execution) DeepCopy() *Execution {
+	if in == nil {
+		return nil
+	}
+	out := new(Execution)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Execution) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExecutionFailedEventDetails) DeepCopyInto(out *ExecutionFailedEventDetails) {
+	*out = *in
+	if in.Cause != nil {
+		in, out := &in.Cause, &out.Cause
+		*out = new(string)
+		**out = **in
+	}
+	if in.Error != nil {
+		in, out := &in.Error, &out.Error
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecutionFailedEventDetails.
+func (in *ExecutionFailedEventDetails) DeepCopy() *ExecutionFailedEventDetails {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecutionFailedEventDetails)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExecutionInputSource) DeepCopyInto(out *ExecutionInputSource) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(DefinitionKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(DefinitionKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecutionInputSource.
+func (in *ExecutionInputSource) DeepCopy() *ExecutionInputSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecutionInputSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExecutionList) DeepCopyInto(out *ExecutionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Execution, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecutionList.
+func (in *ExecutionList) DeepCopy() *ExecutionList {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecutionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ExecutionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExecutionListItem) DeepCopyInto(out *ExecutionListItem) {
+	*out = *in
+	if in.ExecutionARN != nil {
+		in, out := &in.ExecutionARN, &out.ExecutionARN
+		*out = new(string)
+		**out = **in
+	}
+	if in.MapRunARN != nil {
+		in, out := &in.MapRunARN, &out.MapRunARN
+		*out = new(string)
+		**out = **in
+	}
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.RedriveDate != nil {
+		in, out := &in.RedriveDate, &out.RedriveDate
+		*out = (*in).DeepCopy()
+	}
+	if in.StartDate != nil {
+		in, out := &in.StartDate, &out.StartDate
+		*out = (*in).DeepCopy()
+	}
+	if in.StateMachineAliasARN != nil {
+		in, out := &in.StateMachineAliasARN, &out.StateMachineAliasARN
+		*out = new(string)
+		**out = **in
+	}
+	if in.StateMachineARN != nil {
+		in, out := &in.StateMachineARN, &out.StateMachineARN
+		*out = new(string)
+		**out = **in
+	}
+	if in.StateMachineVersionARN != nil {
+		in, out := &in.StateMachineVersionARN, &out.StateMachineVersionARN
+		*out = new(string)
+		**out = **in
+	}
+	if in.StopDate != nil {
+		in, out := &in.StopDate, &out.StopDate
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecutionListItem.
+func (in *ExecutionListItem) DeepCopy() *ExecutionListItem {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecutionListItem)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExecutionRoleGeneration) DeepCopyInto(out *ExecutionRoleGeneration) {
+	*out = *in
+	if in.AdditionalPolicies != nil {
+		in, out := &in.AdditionalPolicies, &out.AdditionalPolicies
+		*out = make([]*string, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(string)
+				**out = **in
+			}
+		}
+	}
+	if in.PermissionsBoundary != nil {
+		in, out := &in.PermissionsBoundary, &out.PermissionsBoundary
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecutionRoleGeneration.
+func (in *ExecutionRoleGeneration) DeepCopy() *ExecutionRoleGeneration {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecutionRoleGeneration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExecutionSpec) DeepCopyInto(out *ExecutionSpec) {
+	*out = *in
+	if in.Input != nil {
+		in, out := &in.Input, &out.Input
+		*out = new(string)
+		**out = **in
+	}
+	if in.InputFrom != nil {
+		in, out := &in.InputFrom, &out.InputFrom
+		*out = new(ExecutionInputSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Mode != nil {
+		in, out := &in.Mode, &out.Mode
+		*out = new(string)
+		**out = **in
+	}
 	if in.Name != nil {
 		in, out := &in.Name, &out.Name
 		*out = new(string)
 		**out = **in
 	}
-	if in.Tags != nil {
-		in, out := &in.Tags, &out.Tags
-		*out = make([]*Tag, len(*in))
-		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = new(Tag)
-				(*in).DeepCopyInto(*out)
-			}
-		}
-	}
+	if in.RedriveGeneration != nil {
+		in, out := &in.RedriveGeneration, &out.RedriveGeneration
+		*out = new(int64)
+		**out = **in
+	}
+	if in.StateMachineARN != nil {
+		in, out := &in.StateMachineARN, &out.StateMachineARN
+		*out = new(string)
+		**out = **in
+	}
+	if in.StateMachineAliasRef != nil {
+		in, out := &in.StateMachineAliasRef, &out.StateMachineAliasRef
+		*out = new(corev1alpha1.AWSResourceReferenceWrapper)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StateMachineRef != nil {
+		in, out := &in.StateMachineRef, &out.StateMachineRef
+		*out = new(corev1alpha1.AWSResourceReferenceWrapper)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StateMachineVersionRef != nil {
+		in, out := &in.StateMachineVersionRef, &out.StateMachineVersionRef
+		*out = new(corev1alpha1.AWSResourceReferenceWrapper)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TraceHeader != nil {
+		in, out := &in.TraceHeader, &out.TraceHeader
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecutionSpec.
+func (in *ExecutionSpec) DeepCopy() *ExecutionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecutionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExecutionStartedEventDetails) DeepCopyInto(out *ExecutionStartedEventDetails) {
+	*out = *in
+	if in.RoleARN != nil {
+		in, out := &in.RoleARN, &out.RoleARN
+		*out = new(string)
+		**out = **in
+	}
+	if in.StateMachineAliasARN != nil {
+		in, out := &in.StateMachineAliasARN, &out.StateMachineAliasARN
+		*out = new(string)
+		**out = **in
+	}
+	if in.StateMachineVersionARN != nil {
+		in, out := &in.StateMachineVersionARN, &out.StateMachineVersionARN
+		*out = new(string)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActivitySpec.
-func (in *ActivitySpec) DeepCopy() *ActivitySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecutionStartedEventDetails.
+func (in *ExecutionStartedEventDetails) DeepCopy() *ExecutionStartedEventDetails {
 	if in == nil {
 		return nil
 	}
-	out := new(ActivitySpec)
+	out := new(ExecutionStartedEventDetails)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ActivityStatus) DeepCopyInto(out *ActivityStatus) {
+func (in *ExecutionStatus) DeepCopyInto(out *ExecutionStatus) {
 	*out = *in
 	if in.ACKResourceMetadata != nil {
 		in, out := &in.ACKResourceMetadata, &out.ACKResourceMetadata
@@ -182,110 +932,71 @@ func (in *ActivityStatus) DeepCopyInto(out *ActivityStatus) {
 			}
 		}
 	}
-	if in.CreationDate != nil {
-		in, out := &in.CreationDate, &out.CreationDate
-		*out = (*in).DeepCopy()
-	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActivityStatus.
-func (in *ActivityStatus) DeepCopy() *ActivityStatus {
-	if in == nil {
-		return nil
+	if in.BillingDetails != nil {
+		in, out := &in.BillingDetails, &out.BillingDetails
+		*out = new(BillingDetails)
+		(*in).DeepCopyInto(*out)
 	}
-	out := new(ActivityStatus)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CloudWatchLogsLogGroup) DeepCopyInto(out *CloudWatchLogsLogGroup) {
-	*out = *in
-	if in.LogGroupARN != nil {
-		in, out := &in.LogGroupARN, &out.LogGroupARN
+	if in.Cause != nil {
+		in, out := &in.Cause, &out.Cause
 		*out = new(string)
 		**out = **in
 	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudWatchLogsLogGroup.
-func (in *CloudWatchLogsLogGroup) DeepCopy() *CloudWatchLogsLogGroup {
-	if in == nil {
-		return nil
-	}
-	out := new(CloudWatchLogsLogGroup)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EncryptionConfiguration) DeepCopyInto(out *EncryptionConfiguration) {
-	*out = *in
-	if in.KMSDataKeyReusePeriodSeconds != nil {
-		in, out := &in.KMSDataKeyReusePeriodSeconds, &out.KMSDataKeyReusePeriodSeconds
-		*out = new(int64)
+	if in.Error != nil {
+		in, out := &in.Error, &out.Error
+		*out = new(string)
 		**out = **in
 	}
-	if in.KMSKeyID != nil {
-		in, out := &in.KMSKeyID, &out.KMSKeyID
+	if in.HistoryPageToken != nil {
+		in, out := &in.HistoryPageToken, &out.HistoryPageToken
 		*out = new(string)
 		**out = **in
 	}
-	if in.Type != nil {
-		in, out := &in.Type, &out.Type
-		*out = new(string)
+	if in.HistoryRecorded != nil {
+		in, out := &in.HistoryRecorded, &out.HistoryRecorded
+		*out = new(bool)
 		**out = **in
 	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EncryptionConfiguration.
-func (in *EncryptionConfiguration) DeepCopy() *EncryptionConfiguration {
-	if in == nil {
-		return nil
+	if in.LastHistoryEventID != nil {
+		in, out := &in.LastHistoryEventID, &out.LastHistoryEventID
+		*out = new(int64)
+		**out = **in
 	}
-	out := new(EncryptionConfiguration)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ExecutionListItem) DeepCopyInto(out *ExecutionListItem) {
-	*out = *in
-	if in.ExecutionARN != nil {
-		in, out := &in.ExecutionARN, &out.ExecutionARN
-		*out = new(string)
+	if in.ObservedRedriveGeneration != nil {
+		in, out := &in.ObservedRedriveGeneration, &out.ObservedRedriveGeneration
+		*out = new(int64)
 		**out = **in
 	}
-	if in.MapRunARN != nil {
-		in, out := &in.MapRunARN, &out.MapRunARN
+	if in.Output != nil {
+		in, out := &in.Output, &out.Output
 		*out = new(string)
 		**out = **in
 	}
-	if in.Name != nil {
-		in, out := &in.Name, &out.Name
-		*out = new(string)
+	if in.RedriveCount != nil {
+		in, out := &in.RedriveCount, &out.RedriveCount
+		*out = new(int64)
 		**out = **in
 	}
 	if in.RedriveDate != nil {
 		in, out := &in.RedriveDate, &out.RedriveDate
 		*out = (*in).DeepCopy()
 	}
-	if in.StartDate != nil {
-		in, out := &in.StartDate, &out.StartDate
-		*out = (*in).DeepCopy()
-	}
-	if in.StateMachineAliasARN != nil {
-		in, out := &in.StateMachineAliasARN, &out.StateMachineAliasARN
+	if in.RedriveStatus != nil {
+		in, out := &in.RedriveStatus, &out.RedriveStatus
 		*out = new(string)
 		**out = **in
 	}
-	if in.StateMachineARN != nil {
-		in, out := &in.StateMachineARN, &out.StateMachineARN
+	if in.RedriveStatusReason != nil {
+		in, out := &in.RedriveStatusReason, &out.RedriveStatusReason
 		*out = new(string)
 		**out = **in
 	}
-	if in.StateMachineVersionARN != nil {
-		in, out := &in.StateMachineVersionARN, &out.StateMachineVersionARN
+	if in.StartDate != nil {
+		in, out := &in.StartDate, &out.StartDate
+		*out = (*in).DeepCopy()
+	}
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
 		*out = new(string)
 		**out = **in
 	}
@@ -295,42 +1006,101 @@ func (in *ExecutionListItem) DeepCopyInto(out *ExecutionListItem) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecutionListItem.
-func (in *ExecutionListItem) DeepCopy() *ExecutionListItem {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecutionStatus.
+func (in *ExecutionStatus) DeepCopy() *ExecutionStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ExecutionListItem)
+	out := new(ExecutionStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ExecutionStartedEventDetails) DeepCopyInto(out *ExecutionStartedEventDetails) {
+func (in *ExecutionTemplate) DeepCopyInto(out *ExecutionTemplate) {
 	*out = *in
-	if in.RoleARN != nil {
-		in, out := &in.RoleARN, &out.RoleARN
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = new(ExecutionTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecutionTemplate.
+func (in *ExecutionTemplate) DeepCopy() *ExecutionTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecutionTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExecutionTemplateSpec) DeepCopyInto(out *ExecutionTemplateSpec) {
+	*out = *in
+	if in.Input != nil {
+		in, out := &in.Input, &out.Input
 		*out = new(string)
 		**out = **in
 	}
-	if in.StateMachineAliasARN != nil {
-		in, out := &in.StateMachineAliasARN, &out.StateMachineAliasARN
+	if in.InputFrom != nil {
+		in, out := &in.InputFrom, &out.InputFrom
+		*out = new(ExecutionInputSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Mode != nil {
+		in, out := &in.Mode, &out.Mode
 		*out = new(string)
 		**out = **in
 	}
-	if in.StateMachineVersionARN != nil {
-		in, out := &in.StateMachineVersionARN, &out.StateMachineVersionARN
+	if in.StateMachineARN != nil {
+		in, out := &in.StateMachineARN, &out.StateMachineARN
+		*out = new(string)
+		**out = **in
+	}
+	if in.StateMachineAliasRef != nil {
+		in, out := &in.StateMachineAliasRef, &out.StateMachineAliasRef
+		*out = new(corev1alpha1.AWSResourceReferenceWrapper)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StateMachineRef != nil {
+		in, out := &in.StateMachineRef, &out.StateMachineRef
+		*out = new(corev1alpha1.AWSResourceReferenceWrapper)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StateMachineVersionRef != nil {
+		in, out := &in.StateMachineVersionRef, &out.StateMachineVersionRef
+		*out = new(corev1alpha1.AWSResourceReferenceWrapper)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TraceHeader != nil {
+		in, out := &in.TraceHeader, &out.TraceHeader
 		*out = new(string)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecutionStartedEventDetails.
-func (in *ExecutionStartedEventDetails) DeepCopy() *ExecutionStartedEventDetails {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecutionTemplateSpec.
+func (in *ExecutionTemplateSpec) DeepCopy() *ExecutionTemplateSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ExecutionStartedEventDetails)
+	out := new(ExecutionTemplateSpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -338,10 +1108,55 @@ func (in *ExecutionStartedEventDetails) DeepCopy() *ExecutionStartedEventDetails
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HistoryEvent) DeepCopyInto(out *HistoryEvent) {
 	*out = *in
+	if in.ExecutionFailedEventDetails != nil {
+		in, out := &in.ExecutionFailedEventDetails, &out.ExecutionFailedEventDetails
+		*out = new(ExecutionFailedEventDetails)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MapRunStartedEventDetails != nil {
+		in, out := &in.MapRunStartedEventDetails, &out.MapRunStartedEventDetails
+		*out = new(MapRunStartedEventDetails)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PreviousEventID != nil {
+		in, out := &in.PreviousEventID, &out.PreviousEventID
+		*out = new(int64)
+		**out = **in
+	}
+	if in.StateEnteredEventDetails != nil {
+		in, out := &in.StateEnteredEventDetails, &out.StateEnteredEventDetails
+		*out = new(StateEnteredEventDetails)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StateExitedEventDetails != nil {
+		in, out := &in.StateExitedEventDetails, &out.StateExitedEventDetails
+		*out = new(StateExitedEventDetails)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TaskFailedEventDetails != nil {
+		in, out := &in.TaskFailedEventDetails, &out.TaskFailedEventDetails
+		*out = new(TaskFailedEventDetails)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TaskTimedOutEventDetails != nil {
+		in, out := &in.TaskTimedOutEventDetails, &out.TaskTimedOutEventDetails
+		*out = new(TaskTimedOutEventDetails)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Timestamp != nil {
 		in, out := &in.Timestamp, &out.Timestamp
 		*out = (*in).DeepCopy()
 	}
+	if in.Type != nil {
+		in, out := &in.Type, &out.Type
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HistoryEvent.
@@ -394,6 +1209,26 @@ func (in *LogDestination) DeepCopy() *LogDestination {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogGroupGeneration) DeepCopyInto(out *LogGroupGeneration) {
+	*out = *in
+	if in.RetentionDays != nil {
+		in, out := &in.RetentionDays, &out.RetentionDays
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogGroupGeneration.
+func (in *LogGroupGeneration) DeepCopy() *LogGroupGeneration {
+	if in == nil {
+		return nil
+	}
+	out := new(LogGroupGeneration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LoggingConfiguration) DeepCopyInto(out *LoggingConfiguration) {
 	*out = *in
@@ -518,12 +1353,120 @@ func (in *MapRunStartedEventDetails) DeepCopyInto(out *MapRunStartedEventDetails
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MapRunStartedEventDetails.
-func (in *MapRunStartedEventDetails) DeepCopy() *MapRunStartedEventDetails {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MapRunStartedEventDetails.
+func (in *MapRunStartedEventDetails) DeepCopy() *MapRunStartedEventDetails {
+	if in == nil {
+		return nil
+	}
+	out := new(MapRunStartedEventDetails)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutRollbackPolicy) DeepCopyInto(out *RolloutRollbackPolicy) {
+	*out = *in
+	if in.FailureThresholdPercentage != nil {
+		in, out := &in.FailureThresholdPercentage, &out.FailureThresholdPercentage
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MinimumExecutions != nil {
+		in, out := &in.MinimumExecutions, &out.MinimumExecutions
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutRollbackPolicy.
+func (in *RolloutRollbackPolicy) DeepCopy() *RolloutRollbackPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutRollbackPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStatus) DeepCopyInto(out *RolloutStatus) {
+	*out = *in
+	if in.CurrentStep != nil {
+		in, out := &in.CurrentStep, &out.CurrentStep
+		*out = new(int64)
+		**out = **in
+	}
+	if in.NextTransitionTime != nil {
+		in, out := &in.NextTransitionTime, &out.NextTransitionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.RolledBack != nil {
+		in, out := &in.RolledBack, &out.RolledBack
+		*out = new(bool)
+		**out = **in
+	}
+	if in.StableVersionARN != nil {
+		in, out := &in.StableVersionARN, &out.StableVersionARN
+		*out = new(string)
+		**out = **in
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.TargetVersionARN != nil {
+		in, out := &in.TargetVersionARN, &out.TargetVersionARN
+		*out = new(string)
+		**out = **in
+	}
+	if in.TargetWeight != nil {
+		in, out := &in.TargetWeight, &out.TargetWeight
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStatus.
+func (in *RolloutStatus) DeepCopy() *RolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStrategy) DeepCopyInto(out *RolloutStrategy) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(string)
+		**out = **in
+	}
+	if in.Rollback != nil {
+		in, out := &in.Rollback, &out.Rollback
+		*out = new(RolloutRollbackPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StepPercentage != nil {
+		in, out := &in.StepPercentage, &out.StepPercentage
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Type != nil {
+		in, out := &in.Type, &out.Type
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStrategy.
+func (in *RolloutStrategy) DeepCopy() *RolloutStrategy {
 	if in == nil {
 		return nil
 	}
-	out := new(MapRunStartedEventDetails)
+	out := new(RolloutStrategy)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -531,11 +1474,26 @@ func (in *MapRunStartedEventDetails) DeepCopy() *MapRunStartedEventDetails {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RoutingConfigurationListItem) DeepCopyInto(out *RoutingConfigurationListItem) {
 	*out = *in
+	if in.StateMachineRef != nil {
+		in, out := &in.StateMachineRef, &out.StateMachineRef
+		*out = new(corev1alpha1.AWSResourceReferenceWrapper)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.StateMachineVersionARN != nil {
 		in, out := &in.StateMachineVersionARN, &out.StateMachineVersionARN
 		*out = new(string)
 		**out = **in
 	}
+	if in.StateMachineVersionRef != nil {
+		in, out := &in.StateMachineVersionRef, &out.StateMachineVersionRef
+		*out = new(corev1alpha1.AWSResourceReferenceWrapper)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VersionNumber != nil {
+		in, out := &in.VersionNumber, &out.VersionNumber
+		*out = new(int64)
+		**out = **in
+	}
 	if in.Weight != nil {
 		in, out := &in.Weight, &out.Weight
 		*out = new(int64)
@@ -553,6 +1511,26 @@ func (in *RoutingConfigurationListItem) DeepCopy() *RoutingConfigurationListItem
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceAccountReference) DeepCopyInto(out *ServiceAccountReference) {
+	*out = *in
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountReference.
+func (in *ServiceAccountReference) DeepCopy() *ServiceAccountReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAccountReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StateEnteredEventDetails) DeepCopyInto(out *StateEnteredEventDetails) {
 	*out = *in
@@ -716,6 +1694,11 @@ func (in *StateMachineAliasSpec) DeepCopyInto(out *StateMachineAliasSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.RoutingConfiguration != nil {
 		in, out := &in.RoutingConfiguration, &out.RoutingConfiguration
 		*out = make([]*RoutingConfigurationListItem, len(*in))
@@ -762,6 +1745,11 @@ func (in *StateMachineAliasStatus) DeepCopyInto(out *StateMachineAliasStatus) {
 		in, out := &in.CreationDate, &out.CreationDate
 		*out = (*in).DeepCopy()
 	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StateMachineAliasStatus.
@@ -848,6 +1836,52 @@ func (in *StateMachineSpec) DeepCopyInto(out *StateMachineSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.DefinitionFrom != nil {
+		in, out := &in.DefinitionFrom, &out.DefinitionFrom
+		*out = new(DefinitionSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DefinitionObject != nil {
+		in, out := &in.DefinitionObject, &out.DefinitionObject
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DefinitionSubstitutions != nil {
+		in, out := &in.DefinitionSubstitutions, &out.DefinitionSubstitutions
+		*out = make(map[string]*DefinitionSubstitution, len(*in))
+		for key, val := range *in {
+			var outVal *DefinitionSubstitution
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = new(DefinitionSubstitution)
+				(*in).DeepCopyInto(*out)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.DefinitionValidation != nil {
+		in, out := &in.DefinitionValidation, &out.DefinitionValidation
+		*out = new(DefinitionValidationPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EncryptionConfiguration != nil {
+		in, out := &in.EncryptionConfiguration, &out.EncryptionConfiguration
+		*out = new(EncryptionConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExecutionRoleGeneration != nil {
+		in, out := &in.ExecutionRoleGeneration, &out.ExecutionRoleGeneration
+		*out = new(ExecutionRoleGeneration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LogGroupGeneration != nil {
+		in, out := &in.LogGroupGeneration, &out.LogGroupGeneration
+		*out = new(LogGroupGeneration)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.LoggingConfiguration != nil {
 		in, out := &in.LoggingConfiguration, &out.LoggingConfiguration
 		*out = new(LoggingConfiguration)
@@ -858,6 +1892,22 @@ func (in *StateMachineSpec) DeepCopyInto(out *StateMachineSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Publish != nil {
+		in, out := &in.Publish, &out.Publish
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ResourceRefs != nil {
+		in, out := &in.ResourceRefs, &out.ResourceRefs
+		*out = make([]*DefinitionResourceReference, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(DefinitionResourceReference)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
 	if in.RoleARN != nil {
 		in, out := &in.RoleARN, &out.RoleARN
 		*out = new(string)
@@ -868,6 +1918,11 @@ func (in *StateMachineSpec) DeepCopyInto(out *StateMachineSpec) {
 		*out = new(corev1alpha1.AWSResourceReferenceWrapper)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RoleServiceAccountRef != nil {
+		in, out := &in.RoleServiceAccountRef, &out.RoleServiceAccountRef
+		*out = new(ServiceAccountReference)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Tags != nil {
 		in, out := &in.Tags, &out.Tags
 		*out = make([]*Tag, len(*in))
@@ -889,6 +1944,16 @@ func (in *StateMachineSpec) DeepCopyInto(out *StateMachineSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.VersionDescription != nil {
+		in, out := &in.VersionDescription, &out.VersionDescription
+		*out = new(string)
+		**out = **in
+	}
+	if in.VersionRetention != nil {
+		in, out := &in.VersionRetention, &out.VersionRetention
+		*out = new(VersionRetentionPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StateMachineSpec.
@@ -924,6 +1989,53 @@ func (in *StateMachineStatus) DeepCopyInto(out *StateMachineStatus) {
 		in, out := &in.CreationDate, &out.CreationDate
 		*out = (*in).DeepCopy()
 	}
+	if in.DefinitionDiagnostics != nil {
+		in, out := &in.DefinitionDiagnostics, &out.DefinitionDiagnostics
+		*out = make([]*ValidateStateMachineDefinitionDiagnostic, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(ValidateStateMachineDefinitionDiagnostic)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.DefinitionSubstitutionValues != nil {
+		in, out := &in.DefinitionSubstitutionValues, &out.DefinitionSubstitutionValues
+		*out = make(map[string]*string, len(*in))
+		for key, val := range *in {
+			var outVal *string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = new(string)
+				**out = **in
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.LatestVersionARN != nil {
+		in, out := &in.LatestVersionARN, &out.LatestVersionARN
+		*out = new(string)
+		**out = **in
+	}
+	if in.ObservedRedriveSince != nil {
+		in, out := &in.ObservedRedriveSince, &out.ObservedRedriveSince
+		*out = new(string)
+		**out = **in
+	}
+	if in.ResolvedRoleARN != nil {
+		in, out := &in.ResolvedRoleARN, &out.ResolvedRoleARN
+		*out = new(string)
+		**out = **in
+	}
+	if in.RevisionID != nil {
+		in, out := &in.RevisionID, &out.RevisionID
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StateMachineStatus.
@@ -936,6 +2048,65 @@ func (in *StateMachineStatus) DeepCopy() *StateMachineStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StateMachineVersion) DeepCopyInto(out *StateMachineVersion) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StateMachineVersion.
+func (in *StateMachineVersion) DeepCopy() *StateMachineVersion {
+	if in == nil {
+		return nil
+	}
+	out := new(StateMachineVersion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StateMachineVersion) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StateMachineVersionList) DeepCopyInto(out *StateMachineVersionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]StateMachineVersion, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StateMachineVersionList.
+func (in *StateMachineVersionList) DeepCopy() *StateMachineVersionList {
+	if in == nil {
+		return nil
+	}
+	out := new(StateMachineVersionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StateMachineVersionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StateMachineVersionListItem) DeepCopyInto(out *StateMachineVersionListItem) {
 	*out = *in
@@ -960,6 +2131,86 @@ func (in *StateMachineVersionListItem) DeepCopy() *StateMachineVersionListItem {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StateMachineVersionSpec) DeepCopyInto(out *StateMachineVersionSpec) {
+	*out = *in
+	if in.Description != nil {
+		in, out := &in.Description, &out.Description
+		*out = new(string)
+		**out = **in
+	}
+	if in.RevisionID != nil {
+		in, out := &in.RevisionID, &out.RevisionID
+		*out = new(string)
+		**out = **in
+	}
+	if in.StateMachineARN != nil {
+		in, out := &in.StateMachineARN, &out.StateMachineARN
+		*out = new(string)
+		**out = **in
+	}
+	if in.StateMachineRef != nil {
+		in, out := &in.StateMachineRef, &out.StateMachineRef
+		*out = new(corev1alpha1.AWSResourceReferenceWrapper)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StateMachineVersionSpec.
+func (in *StateMachineVersionSpec) DeepCopy() *StateMachineVersionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StateMachineVersionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StateMachineVersionStatus) DeepCopyInto(out *StateMachineVersionStatus) {
+	*out = *in
+	if in.ACKResourceMetadata != nil {
+		in, out := &in.ACKResourceMetadata, &out.ACKResourceMetadata
+		*out = new(corev1alpha1.ResourceMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]*corev1alpha1.Condition, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(corev1alpha1.Condition)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.CreationDate != nil {
+		in, out := &in.CreationDate, &out.CreationDate
+		*out = (*in).DeepCopy()
+	}
+	if in.PublishedRevisionID != nil {
+		in, out := &in.PublishedRevisionID, &out.PublishedRevisionID
+		*out = new(string)
+		**out = **in
+	}
+	if in.VersionNumber != nil {
+		in, out := &in.VersionNumber, &out.VersionNumber
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StateMachineVersionStatus.
+func (in *StateMachineVersionStatus) DeepCopy() *StateMachineVersionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StateMachineVersionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Tag) DeepCopyInto(out *Tag) {
 	*out = *in
@@ -1008,6 +2259,16 @@ func (in *TaskCredentials) DeepCopy() *TaskCredentials {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TaskFailedEventDetails) DeepCopyInto(out *TaskFailedEventDetails) {
 	*out = *in
+	if in.Cause != nil {
+		in, out := &in.Cause, &out.Cause
+		*out = new(string)
+		**out = **in
+	}
+	if in.Error != nil {
+		in, out := &in.Error, &out.Error
+		*out = new(string)
+		**out = **in
+	}
 	if in.Resource != nil {
 		in, out := &in.Resource, &out.Resource
 		*out = new(string)
@@ -1188,6 +2449,16 @@ func (in *TaskSucceededEventDetails) DeepCopy() *TaskSucceededEventDetails {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TaskTimedOutEventDetails) DeepCopyInto(out *TaskTimedOutEventDetails) {
 	*out = *in
+	if in.Cause != nil {
+		in, out := &in.Cause, &out.Cause
+		*out = new(string)
+		**out = **in
+	}
+	if in.Error != nil {
+		in, out := &in.Error, &out.Error
+		*out = new(string)
+		**out = **in
+	}
 	if in.Resource != nil {
 		in, out := &in.Resource, &out.Resource
 		*out = new(string)
@@ -1229,3 +2500,63 @@ func (in *TracingConfiguration) DeepCopy() *TracingConfiguration {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValidateStateMachineDefinitionDiagnostic) DeepCopyInto(out *ValidateStateMachineDefinitionDiagnostic) {
+	*out = *in
+	if in.Code != nil {
+		in, out := &in.Code, &out.Code
+		*out = new(string)
+		**out = **in
+	}
+	if in.Location != nil {
+		in, out := &in.Location, &out.Location
+		*out = new(string)
+		**out = **in
+	}
+	if in.Message != nil {
+		in, out := &in.Message, &out.Message
+		*out = new(string)
+		**out = **in
+	}
+	if in.Severity != nil {
+		in, out := &in.Severity, &out.Severity
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValidateStateMachineDefinitionDiagnostic.
+func (in *ValidateStateMachineDefinitionDiagnostic) DeepCopy() *ValidateStateMachineDefinitionDiagnostic {
+	if in == nil {
+		return nil
+	}
+	out := new(ValidateStateMachineDefinitionDiagnostic)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VersionRetentionPolicy) DeepCopyInto(out *VersionRetentionPolicy) {
+	*out = *in
+	if in.KeepLast != nil {
+		in, out := &in.KeepLast, &out.KeepLast
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VersionRetentionPolicy.
+func (in *VersionRetentionPolicy) DeepCopy() *VersionRetentionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(VersionRetentionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,79 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// asl-lint checks Amazon States Language definitions offline, using the same
+// rules as the StateMachine controller. It prints one line per diagnostic
+// and exits with a non-zero status if any definition is rejected.
+//
+// Usage:
+//
+//	asl-lint [--warnings-as-errors] FILE... (use - for stdin)
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/aws-controllers-k8s/sfn-controller/pkg/asl/lint"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run lints the definitions named in args and returns the exit status: 0 if
+// all definitions are accepted, 1 if any is rejected or cannot be read, and 2
+// on usage errors.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("asl-lint", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	warningsAsErrors := flags.Bool("warnings-as-errors", false, "Reject definitions that only have WARNING diagnostics")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		fmt.Fprintln(stderr, "usage: asl-lint [--warnings-as-errors] FILE...")
+		return 2
+	}
+
+	failed := false
+	for _, path := range flags.Args() {
+		definition, err := readDefinition(path, stdin)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %s\n", path, err)
+			failed = true
+			continue
+		}
+		diagnostics := lint.Lint(string(definition))
+		for _, d := range diagnostics {
+			fmt.Fprintf(stdout, "%s: %s\n", path, d)
+		}
+		if lint.HasErrors(diagnostics) || (*warningsAsErrors && len(diagnostics) > 0) {
+			failed = true
+		}
+	}
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+func readDefinition(path string, stdin io.Reader) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(stdin)
+	}
+	return os.ReadFile(path)
+}
@@ -0,0 +1,140 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	validDefinition   = `{"StartAt": "A", "States": {"A": {"Type": "Succeed"}}}`
+	invalidDefinition = `{"StartAt": "A", "States": {"A": {"Type": "Pass"}}}`
+	warningDefinition = `{"StartAt": "C", "States": {
+		"C": {"Type": "Choice", "Choices": [{"Variable": "$.x", "IsPresent": true, "Next": "A"}]},
+		"A": {"Type": "Succeed"}}}`
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	valid := write("valid.json", validDefinition)
+	invalid := write("invalid.json", invalidDefinition)
+	warning := write("warning.json", warningDefinition)
+	missing := filepath.Join(dir, "missing.json")
+
+	tests := []struct {
+		name       string
+		args       []string
+		stdin      string
+		wantCode   int
+		wantStdout []string
+		wantStderr []string
+	}{
+		{
+			name:       "no files",
+			wantCode:   2,
+			wantStderr: []string{"usage: asl-lint"},
+		},
+		{
+			name:     "unknown flag",
+			args:     []string{"--unknown", valid},
+			wantCode: 2,
+		},
+		{
+			name:     "valid definition",
+			args:     []string{valid},
+			wantCode: 0,
+		},
+		{
+			name:       "invalid definition",
+			args:       []string{invalid},
+			wantCode:   1,
+			wantStdout: []string{invalid + ": ERROR MISSING_TRANSITION"},
+		},
+		{
+			name:       "one of several definitions is invalid",
+			args:       []string{valid, invalid},
+			wantCode:   1,
+			wantStdout: []string{invalid + ": ERROR MISSING_TRANSITION"},
+		},
+		{
+			name:       "unreadable file",
+			args:       []string{valid, missing},
+			wantCode:   1,
+			wantStderr: []string{missing + ": "},
+		},
+		{
+			name:       "warnings only",
+			args:       []string{warning},
+			wantCode:   0,
+			wantStdout: []string{warning + ": WARNING MISSING_CHOICE_DEFAULT"},
+		},
+		{
+			name:       "warnings as errors",
+			args:       []string{"--warnings-as-errors", warning},
+			wantCode:   1,
+			wantStdout: []string{warning + ": WARNING MISSING_CHOICE_DEFAULT"},
+		},
+		{
+			name:     "warnings as errors without diagnostics",
+			args:     []string{"--warnings-as-errors", valid},
+			wantCode: 0,
+		},
+		{
+			name:     "valid definition on stdin",
+			args:     []string{"-"},
+			stdin:    validDefinition,
+			wantCode: 0,
+		},
+		{
+			name:       "invalid definition on stdin",
+			args:       []string{"-"},
+			stdin:      `{`,
+			wantCode:   1,
+			wantStdout: []string{"-: ERROR INVALID_JSON"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			if code != tt.wantCode {
+				t.Errorf("run() = %d, want %d\nstdout: %s\nstderr: %s", code, tt.wantCode, stdout.String(), stderr.String())
+			}
+			if len(tt.wantStdout) == 0 && stdout.Len() > 0 {
+				t.Errorf("unexpected stdout: %s", stdout.String())
+			}
+			for _, want := range tt.wantStdout {
+				if !strings.Contains(stdout.String(), want) {
+					t.Errorf("stdout %q does not contain %q", stdout.String(), want)
+				}
+			}
+			for _, want := range tt.wantStderr {
+				if !strings.Contains(stderr.String(), want) {
+					t.Errorf("stderr %q does not contain %q", stderr.String(), want)
+				}
+			}
+		})
+	}
+}
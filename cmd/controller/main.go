@@ -38,12 +38,16 @@ import (
 	ctrlrtwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	svctypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+	cronexecution "github.com/aws-controllers-k8s/sfn-controller/pkg/cron_execution"
 	svcresource "github.com/aws-controllers-k8s/sfn-controller/pkg/resource"
 
 	_ "github.com/aws-controllers-k8s/sfn-controller/pkg/resource/activity"
-	_ "github.com/aws-controllers-k8s/sfn-controller/pkg/resource/state_machine"
+	_ "github.com/aws-controllers-k8s/sfn-controller/pkg/resource/execution"
+	smresource "github.com/aws-controllers-k8s/sfn-controller/pkg/resource/state_machine"
 	_ "github.com/aws-controllers-k8s/sfn-controller/pkg/resource/state_machine_alias"
+	_ "github.com/aws-controllers-k8s/sfn-controller/pkg/resource/state_machine_version"
 
+	commonutil "github.com/aws-controllers-k8s/sfn-controller/pkg/util"
 	"github.com/aws-controllers-k8s/sfn-controller/pkg/version"
 )
 
@@ -142,6 +146,10 @@ func main() {
 		os.Exit(1)
 	}
 
+	commonutil.SetEventRecorder(mgr.GetEventRecorderFor("ack-" + awsServiceAlias + "-controller"))
+	commonutil.SetKubeClient(mgr.GetClient())
+	commonutil.SetAPIReader(mgr.GetAPIReader())
+
 	stopChan := ctrlrt.SetupSignalHandler()
 
 	setupLog.Info(
@@ -183,6 +191,34 @@ func main() {
 		os.Exit(1)
 	}
 
+	for _, reconciler := range sc.GetReconcilers() {
+		if reconciler.GroupVersionKind().Kind != "StateMachine" {
+			continue
+		}
+		if err = smresource.SetupDefinitionSourceWatch(mgr, reconciler); err != nil {
+			setupLog.Error(
+				err, "unable to watch StateMachine definition sources",
+				"aws.service", awsServiceAlias,
+			)
+			os.Exit(1)
+		}
+		if err = smresource.SetupRedriveAnnotationWatch(mgr, reconciler); err != nil {
+			setupLog.Error(
+				err, "unable to watch StateMachine redrive annotations",
+				"aws.service", awsServiceAlias,
+			)
+			os.Exit(1)
+		}
+	}
+
+	if err = cronexecution.SetupController(mgr, ackCfg); err != nil {
+		setupLog.Error(
+			err, "unable to set up CronExecution controller",
+			"aws.service", awsServiceAlias,
+		)
+		os.Exit(1)
+	}
+
 	if err = mgr.AddHealthzCheck("health", ctrlrthealthz.Ping); err != nil {
 		setupLog.Error(
 			err, "unable to set up health check",
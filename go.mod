@@ -10,6 +10,7 @@ require (
 	github.com/aws/aws-sdk-go-v2/service/sfn v1.34.8
 	github.com/aws/smithy-go v1.22.2
 	github.com/go-logr/logr v1.4.3
+	github.com/robfig/cron/v3 v3.0.1
 	github.com/spf13/pflag v1.0.9
 	k8s.io/api v0.35.0
 	k8s.io/apimachinery v0.35.0
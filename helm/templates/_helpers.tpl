@@ -48,6 +48,15 @@ If release name contains chart name it will be used as a full name.
 {{- printf "%s/%s" $secret_mount_path .Values.aws.credentials.secretKey -}}
 {{- end -}}
 
+{{/* The Secret holding the webhook server certificate */}}
+{{- define "ack-sfn-controller.webhook.secret-name" -}}
+{{- if .Values.webhook.certManager.enabled -}}
+{{- printf "%s-webhook-server-cert" (include "ack-sfn-controller.app.fullname" .) -}}
+{{- else -}}
+{{- .Values.webhook.tls.secretName -}}
+{{- end -}}
+{{- end -}}
+
 {{/* The rules a of ClusterRole or Role */}}
 {{- define "ack-sfn-controller.rbac-rules" -}}
 rules:
@@ -65,18 +74,79 @@ rules:
   - ""
   resources:
   - namespaces
+  - serviceaccounts
   verbs:
   - get
   - list
   - watch
+- apiGroups:
+  - ""
+  resources:
+  - events
+  verbs:
+  - create
+  - patch
+- apiGroups:
+  - cloudwatchlogs.services.k8s.aws
+  resources:
+  - loggroups
+  verbs:
+  - create
+  - get
+  - list
+  - patch
+  - update
+- apiGroups:
+  - cloudwatchlogs.services.k8s.aws
+  resources:
+  - loggroups/status
+  verbs:
+  - get
+  - list
+- apiGroups:
+  - dynamodb.services.k8s.aws
+  resources:
+  - tables
+  - tables/status
+  verbs:
+  - get
+  - list
 - apiGroups:
   - iam.services.k8s.aws
   resources:
+  - policies
   - roles
+  verbs:
+  - create
+  - delete
+  - get
+  - list
+  - patch
+  - update
+- apiGroups:
+  - iam.services.k8s.aws
+  resources:
+  - policies/status
   - roles/status
   verbs:
   - get
   - list
+- apiGroups:
+  - kms.services.k8s.aws
+  resources:
+  - keys
+  - keys/status
+  verbs:
+  - get
+  - list
+- apiGroups:
+  - lambda.services.k8s.aws
+  resources:
+  - functions
+  - functions/status
+  verbs:
+  - get
+  - list
 - apiGroups:
   - services.k8s.aws
   resources:
@@ -103,7 +173,10 @@ rules:
   - sfn.services.k8s.aws
   resources:
   - activities
+  - cronexecutions
+  - executions
   - statemachinealiases
+  - statemachineversions
   - statemachines
   verbs:
   - create
@@ -117,12 +190,31 @@ rules:
   - sfn.services.k8s.aws
   resources:
   - activities/status
+  - cronexecutions/status
+  - executions/status
   - statemachinealiases/status
+  - statemachineversions/status
   - statemachines/status
   verbs:
   - get
   - patch
   - update
+- apiGroups:
+  - sns.services.k8s.aws
+  resources:
+  - topics
+  - topics/status
+  verbs:
+  - get
+  - list
+- apiGroups:
+  - sqs.services.k8s.aws
+  resources:
+  - queues
+  - queues/status
+  verbs:
+  - get
+  - list
 {{- end }}
 
 {{/* Convert k/v map to string like: "key1=value1,key2=value2,..." */}}
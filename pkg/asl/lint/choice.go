@@ -0,0 +1,177 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package lint
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// comparisonOperators are the Choice rule operators that compare the value
+// selected by Variable.
+var comparisonOperators = map[string]bool{
+	"BooleanEquals":                  true,
+	"BooleanEqualsPath":              true,
+	"IsBoolean":                      true,
+	"IsNull":                         true,
+	"IsNumeric":                      true,
+	"IsPresent":                      true,
+	"IsString":                       true,
+	"IsTimestamp":                    true,
+	"NumericEquals":                  true,
+	"NumericEqualsPath":              true,
+	"NumericGreaterThan":             true,
+	"NumericGreaterThanPath":         true,
+	"NumericGreaterThanEquals":       true,
+	"NumericGreaterThanEqualsPath":   true,
+	"NumericLessThan":                true,
+	"NumericLessThanPath":            true,
+	"NumericLessThanEquals":          true,
+	"NumericLessThanEqualsPath":      true,
+	"StringEquals":                   true,
+	"StringEqualsPath":               true,
+	"StringGreaterThan":              true,
+	"StringGreaterThanPath":          true,
+	"StringGreaterThanEquals":        true,
+	"StringGreaterThanEqualsPath":    true,
+	"StringLessThan":                 true,
+	"StringLessThanPath":             true,
+	"StringLessThanEquals":           true,
+	"StringLessThanEqualsPath":       true,
+	"StringMatches":                  true,
+	"TimestampEquals":                true,
+	"TimestampEqualsPath":            true,
+	"TimestampGreaterThan":           true,
+	"TimestampGreaterThanPath":       true,
+	"TimestampGreaterThanEquals":     true,
+	"TimestampGreaterThanEqualsPath": true,
+	"TimestampLessThan":              true,
+	"TimestampLessThanPath":          true,
+	"TimestampLessThanEquals":        true,
+	"TimestampLessThanEqualsPath":    true,
+}
+
+// lintChoices checks the rules of a Choice state and returns the transitions
+// to their Next states and to Default.
+func (l *linter) lintChoices(
+	location string,
+	name string,
+	s *state,
+) (edges []transition) {
+	if len(s.Choices) == 0 {
+		l.errorf(CodeInvalidChoiceRule, location+"/Choices",
+			"Choice state %q must have at least one rule", name)
+	}
+	for i, raw := range s.Choices {
+		ruleLocation := location + "/Choices/" + strconv.Itoa(i)
+		var rule map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &rule); err != nil || rule == nil {
+			l.errorf(CodeInvalidChoiceRule, ruleLocation, "Choice rule must be an object")
+			continue
+		}
+		var next string
+		if rawNext, ok := rule["Next"]; !ok {
+			l.errorf(CodeMissingTransition, ruleLocation, "Choice rule must set Next")
+		} else if err := json.Unmarshal(rawNext, &next); err != nil || next == "" {
+			l.errorf(CodeInvalidTransition, ruleLocation+"/Next", "Next must be a non-empty string")
+		} else {
+			edges = append(edges, transition{next, ruleLocation + "/Next"})
+		}
+		l.lintChoiceRule(ruleLocation, rule, true)
+	}
+	if s.Default != nil {
+		edges = append(edges, transition{*s.Default, location + "/Default"})
+	} else {
+		l.warnf(CodeMissingChoiceDefault, location,
+			"Choice state %q has no Default, executions that match no rule fail with States.NoChoiceMatched", name)
+	}
+	return edges
+}
+
+// lintChoiceRule checks that a Choice rule has exactly one operator and the
+// fields that operator needs. Nested rules of And, Or and Not cannot set
+// Next.
+func (l *linter) lintChoiceRule(
+	location string,
+	rule map[string]json.RawMessage,
+	topLevel bool,
+) {
+	if !topLevel {
+		if _, ok := rule["Next"]; ok {
+			l.errorf(CodeInvalidChoiceRule, location+"/Next", "nested Choice rules cannot set Next")
+		}
+	}
+	if _, ok := rule["Condition"]; ok {
+		// JSONata rules are a single expression evaluated by Step Functions.
+		if _, ok := rule["Variable"]; ok {
+			l.errorf(CodeInvalidChoiceRule, location, "Condition and Variable cannot be used together")
+		}
+		return
+	}
+
+	operators := []string{}
+	for field := range rule {
+		if field == "And" || field == "Or" || field == "Not" || comparisonOperators[field] {
+			operators = append(operators, field)
+		}
+	}
+	sort.Strings(operators)
+	switch len(operators) {
+	case 0:
+		l.errorf(CodeInvalidChoiceRule, location, "Choice rule has no comparison operator")
+		return
+	case 1:
+	default:
+		l.errorf(CodeInvalidChoiceRule, location,
+			"Choice rule has more than one operator: %s", strings.Join(operators, ", "))
+		return
+	}
+
+	operator := operators[0]
+	switch operator {
+	case "And", "Or":
+		var nested []map[string]json.RawMessage
+		if err := json.Unmarshal(rule[operator], &nested); err != nil || len(nested) == 0 {
+			l.errorf(CodeInvalidChoiceRule, location+"/"+operator,
+				"%s must be a non-empty array of Choice rules", operator)
+			return
+		}
+		for i, n := range nested {
+			l.lintChoiceRule(location+"/"+operator+"/"+strconv.Itoa(i), n, false)
+		}
+	case "Not":
+		var nested map[string]json.RawMessage
+		if err := json.Unmarshal(rule[operator], &nested); err != nil || nested == nil {
+			l.errorf(CodeInvalidChoiceRule, location+"/Not", "Not must be a Choice rule")
+			return
+		}
+		l.lintChoiceRule(location+"/Not", nested, false)
+	default:
+		var variable string
+		if raw, ok := rule["Variable"]; !ok {
+			l.errorf(CodeInvalidChoiceRule, location, "%s requires Variable", operator)
+		} else if err := json.Unmarshal(raw, &variable); err != nil || !strings.HasPrefix(variable, "$") {
+			l.errorf(CodeInvalidChoiceRule, location+"/Variable", "Variable must be a path starting with $")
+		}
+		if strings.HasSuffix(operator, "Path") {
+			var path string
+			if err := json.Unmarshal(rule[operator], &path); err != nil || !strings.HasPrefix(path, "$") {
+				l.errorf(CodeInvalidChoiceRule, location+"/"+operator,
+					"%s must be a path starting with $", operator)
+			}
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package lint
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+const errorStatesAll = "States.ALL"
+
+// predefinedErrors are the error names reserved by Step Functions. Any other
+// name starting with "States." is invalid.
+var predefinedErrors = map[string]bool{
+	errorStatesAll:                           true,
+	"States.BranchFailed":                    true,
+	"States.DataLimitExceeded":               true,
+	"States.ExceedToleratedFailureThreshold": true,
+	"States.HeartbeatTimeout":                true,
+	"States.Http.Socket":                     true,
+	"States.IntrinsicFailure":                true,
+	"States.ItemReaderFailed":                true,
+	"States.NoChoiceMatched":                 true,
+	"States.ParameterPathFailure":            true,
+	"States.Permissions":                     true,
+	"States.QueryEvaluationError":            true,
+	"States.ResultPathMatchFailure":          true,
+	"States.ResultWriterFailed":              true,
+	"States.Runtime":                         true,
+	"States.TaskFailed":                      true,
+	"States.Timeout":                         true,
+}
+
+// retrier holds the fields of a Retry entry that are checked.
+type retrier struct {
+	ErrorEquals []string `json:"ErrorEquals"`
+}
+
+// catcher holds the fields of a Catch entry that are checked.
+type catcher struct {
+	ErrorEquals []string `json:"ErrorEquals"`
+	Next        *string  `json:"Next"`
+}
+
+// lintRetriers checks the error names of the Retry entries of a state.
+func (l *linter) lintRetriers(location string, raws []json.RawMessage) {
+	for i, raw := range raws {
+		entryLocation := location + "/Retry/" + strconv.Itoa(i)
+		var r retrier
+		if err := json.Unmarshal(raw, &r); err != nil {
+			l.errorf(CodeInvalidRetrier, entryLocation, "Retry entry is not valid: %s", err)
+			continue
+		}
+		l.lintErrorEquals(CodeInvalidRetrier, entryLocation, r.ErrorEquals, i == len(raws)-1)
+	}
+}
+
+// lintCatchers checks the Catch entries of a state and returns the
+// transitions to their Next states.
+func (l *linter) lintCatchers(location string, raws []json.RawMessage) (edges []transition) {
+	for i, raw := range raws {
+		entryLocation := location + "/Catch/" + strconv.Itoa(i)
+		var c catcher
+		if err := json.Unmarshal(raw, &c); err != nil {
+			l.errorf(CodeInvalidCatcher, entryLocation, "Catch entry is not valid: %s", err)
+			continue
+		}
+		l.lintErrorEquals(CodeInvalidCatcher, entryLocation, c.ErrorEquals, i == len(raws)-1)
+		if c.Next == nil || *c.Next == "" {
+			l.errorf(CodeMissingTransition, entryLocation, "Catch entry must set Next")
+		} else {
+			edges = append(edges, transition{*c.Next, entryLocation + "/Next"})
+		}
+	}
+	return edges
+}
+
+// lintErrorEquals checks the error names of a Retry or Catch entry.
+// States.ALL must be the only name of its entry and appear in the last
+// entry.
+func (l *linter) lintErrorEquals(
+	code string,
+	location string,
+	names []string,
+	last bool,
+) {
+	if len(names) == 0 {
+		l.errorf(code, location+"/ErrorEquals", "ErrorEquals must contain at least one error name")
+		return
+	}
+	for i, name := range names {
+		nameLocation := location + "/ErrorEquals/" + strconv.Itoa(i)
+		switch {
+		case name == "":
+			l.errorf(CodeInvalidErrorName, nameLocation, "error name cannot be empty")
+		case strings.HasPrefix(name, "States.") && !predefinedErrors[name]:
+			l.errorf(CodeInvalidErrorName, nameLocation,
+				"%q is not a predefined error, names starting with States. are reserved", name)
+		case name == errorStatesAll && len(names) > 1:
+			l.errorf(CodeInvalidErrorName, nameLocation,
+				"%s must be the only error name of its entry", errorStatesAll)
+		case name == errorStatesAll && !last:
+			l.errorf(CodeInvalidErrorName, nameLocation,
+				"%s must appear in the last entry", errorStatesAll)
+		}
+	}
+}
@@ -0,0 +1,338 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package lint checks Amazon States Language definitions without calling
+// AWS. It is used by the StateMachine controller before a definition is sent
+// to Step Functions, and can be run from CI through cmd/asl-lint.
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MaxDefinitionSize is the largest definition, in bytes, accepted by Step
+// Functions.
+const MaxDefinitionSize = 1024 * 1024
+
+// Severity is the severity of a Diagnostic. The values match the ones
+// returned by the ValidateStateMachineDefinition API.
+type Severity string
+
+const (
+	SeverityError   Severity = "ERROR"
+	SeverityWarning Severity = "WARNING"
+)
+
+// Diagnostic codes reported by Lint.
+const (
+	CodeDefinitionTooLarge   = "DEFINITION_TOO_LARGE"
+	CodeInvalidJSON          = "INVALID_JSON"
+	CodeMissingStartAt       = "MISSING_START_AT"
+	CodeStartAtNotFound      = "START_AT_NOT_FOUND"
+	CodeMissingStates        = "MISSING_STATES"
+	CodeInvalidState         = "INVALID_STATE"
+	CodeInvalidStateType     = "INVALID_STATE_TYPE"
+	CodeDuplicateStateName   = "DUPLICATE_STATE_NAME"
+	CodeMissingTransition    = "MISSING_TRANSITION"
+	CodeAmbiguousTransition  = "AMBIGUOUS_TRANSITION"
+	CodeInvalidTransition    = "INVALID_TRANSITION"
+	CodeTransitionNotFound   = "TRANSITION_TARGET_NOT_FOUND"
+	CodeNoTerminalState      = "NO_TERMINAL_STATE"
+	CodeUnreachableState     = "UNREACHABLE_STATE"
+	CodeInvalidChoiceRule    = "INVALID_CHOICE_RULE"
+	CodeMissingChoiceDefault = "MISSING_CHOICE_DEFAULT"
+	CodeInvalidErrorName     = "INVALID_ERROR_NAME"
+	CodeInvalidRetrier       = "INVALID_RETRIER"
+	CodeInvalidCatcher       = "INVALID_CATCHER"
+	CodeInvalidBranch        = "INVALID_BRANCH"
+)
+
+// Diagnostic describes a problem found in a definition. Location is a JSON
+// pointer to the offending element, for example "/States/Foo/Next".
+type Diagnostic struct {
+	Code     string
+	Location string
+	Message  string
+	Severity Severity
+}
+
+// String returns a single line description of the diagnostic.
+func (d Diagnostic) String() string {
+	if d.Location == "" {
+		return fmt.Sprintf("%s %s: %s", d.Severity, d.Code, d.Message)
+	}
+	return fmt.Sprintf("%s %s: %s (at %s)", d.Severity, d.Code, d.Message, d.Location)
+}
+
+// HasErrors returns true if any of the supplied diagnostics has the ERROR
+// severity.
+func HasErrors(diagnostics []Diagnostic) bool {
+	for _, d := range diagnostics {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Lint parses the supplied Amazon States Language definition and returns
+// the problems found in its state graph. A nil result means that no problem
+// was found.
+func Lint(definition string) []Diagnostic {
+	l := &linter{stateNames: map[string]string{}}
+	if len(definition) > MaxDefinitionSize {
+		l.errorf(CodeDefinitionTooLarge, "",
+			"definition is %d bytes, the maximum is %d bytes", len(definition), MaxDefinitionSize)
+		return l.diagnostics
+	}
+	var root map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(definition), &root); err != nil {
+		l.errorf(CodeInvalidJSON, "", "definition is not a valid JSON object: %s", err)
+		return l.diagnostics
+	}
+	l.lintScope("", root)
+	return l.diagnostics
+}
+
+// linter accumulates the diagnostics of a single definition.
+type linter struct {
+	diagnostics []Diagnostic
+	// stateNames maps every state name seen so far to its location, state
+	// names have to be unique across the whole state machine, including
+	// nested Parallel branches and Map processors.
+	stateNames map[string]string
+}
+
+func (l *linter) errorf(code, location, format string, args ...interface{}) {
+	l.add(SeverityError, code, location, format, args...)
+}
+
+func (l *linter) warnf(code, location, format string, args ...interface{}) {
+	l.add(SeverityWarning, code, location, format, args...)
+}
+
+func (l *linter) add(severity Severity, code, location, format string, args ...interface{}) {
+	l.diagnostics = append(l.diagnostics, Diagnostic{
+		Code:     code,
+		Location: location,
+		Message:  fmt.Sprintf(format, args...),
+		Severity: severity,
+	})
+}
+
+// state holds the fields of a state that take part in the state graph.
+type state struct {
+	Type          *string           `json:"Type"`
+	Next          *string           `json:"Next"`
+	End           *bool             `json:"End"`
+	Default       *string           `json:"Default"`
+	Choices       []json.RawMessage `json:"Choices"`
+	Retry         []json.RawMessage `json:"Retry"`
+	Catch         []json.RawMessage `json:"Catch"`
+	Branches      []json.RawMessage `json:"Branches"`
+	Iterator      json.RawMessage   `json:"Iterator"`
+	ItemProcessor json.RawMessage   `json:"ItemProcessor"`
+}
+
+// transition is an edge of the state graph.
+type transition struct {
+	target   string
+	location string
+}
+
+// lintScope checks a top level definition, a Parallel branch or a Map
+// processor, all of which are made of StartAt and States.
+func (l *linter) lintScope(location string, scope map[string]json.RawMessage) {
+	var startAt string
+	if raw, ok := scope["StartAt"]; !ok {
+		l.errorf(CodeMissingStartAt, location, "StartAt is required")
+	} else if err := json.Unmarshal(raw, &startAt); err != nil || startAt == "" {
+		l.errorf(CodeMissingStartAt, location+"/StartAt", "StartAt must be a non-empty string")
+	}
+
+	var states map[string]json.RawMessage
+	if raw, ok := scope["States"]; !ok {
+		l.errorf(CodeMissingStates, location, "States is required")
+		return
+	} else if err := json.Unmarshal(raw, &states); err != nil || len(states) == 0 {
+		l.errorf(CodeMissingStates, location+"/States", "States must be a non-empty object")
+		return
+	}
+
+	names := make([]string, 0, len(states))
+	for name := range states {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	edges := map[string][]transition{}
+	terminal := false
+	for _, name := range names {
+		stateLocation := location + "/States/" + escapePointer(name)
+		if previous, ok := l.stateNames[name]; ok {
+			l.errorf(CodeDuplicateStateName, stateLocation,
+				"state name %q is already used at %s", name, previous)
+		} else {
+			l.stateNames[name] = stateLocation
+		}
+		var s state
+		if err := json.Unmarshal(states[name], &s); err != nil {
+			l.errorf(CodeInvalidState, stateLocation, "state %q is not a valid state object: %s", name, err)
+			continue
+		}
+		var isTerminal bool
+		edges[name], isTerminal = l.lintState(stateLocation, name, &s)
+		terminal = terminal || isTerminal
+	}
+
+	if startAt != "" {
+		if _, ok := states[startAt]; !ok {
+			l.errorf(CodeStartAtNotFound, location+"/StartAt",
+				"StartAt state %q does not exist", startAt)
+		}
+	}
+	for _, name := range names {
+		for _, t := range edges[name] {
+			if _, ok := states[t.target]; !ok {
+				l.errorf(CodeTransitionNotFound, t.location,
+					"state %q transitions to %q, which does not exist", name, t.target)
+			}
+		}
+	}
+	if !terminal {
+		l.errorf(CodeNoTerminalState, location+"/States",
+			"no state ends the execution, at least one Succeed, Fail or End state is required")
+	}
+
+	if _, ok := states[startAt]; !ok {
+		// Reachability is meaningless without a valid entry point.
+		return
+	}
+	reachable := map[string]bool{startAt: true}
+	queue := []string{startAt}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		for _, t := range edges[name] {
+			if _, ok := states[t.target]; ok && !reachable[t.target] {
+				reachable[t.target] = true
+				queue = append(queue, t.target)
+			}
+		}
+	}
+	for _, name := range names {
+		if !reachable[name] {
+			l.errorf(CodeUnreachableState, location+"/States/"+escapePointer(name),
+				"state %q is not reachable from StartAt %q", name, startAt)
+		}
+	}
+}
+
+// lintState checks a single state and returns its outgoing transitions and
+// whether it ends the execution.
+func (l *linter) lintState(
+	location string,
+	name string,
+	s *state,
+) (edges []transition, terminal bool) {
+	if s.Type == nil {
+		l.errorf(CodeInvalidStateType, location, "state %q has no Type", name)
+		return nil, false
+	}
+	stateType := *s.Type
+	switch stateType {
+	case "Pass", "Task", "Wait", "Parallel", "Map":
+		switch {
+		case s.Next != nil && s.End != nil && *s.End:
+			l.errorf(CodeAmbiguousTransition, location,
+				"state %q sets both Next and End", name)
+		case s.Next != nil:
+			edges = append(edges, transition{*s.Next, location + "/Next"})
+		case s.End != nil && *s.End:
+			terminal = true
+		default:
+			l.errorf(CodeMissingTransition, location,
+				"state %q must set either Next or End: true", name)
+		}
+	case "Choice":
+		if s.Next != nil || s.End != nil {
+			l.errorf(CodeInvalidTransition, location,
+				"Choice state %q cannot set Next or End", name)
+		}
+		edges = append(edges, l.lintChoices(location, name, s)...)
+	case "Succeed", "Fail":
+		if s.Next != nil || s.End != nil {
+			l.errorf(CodeInvalidTransition, location,
+				"%s state %q cannot set Next or End", stateType, name)
+		}
+		terminal = true
+	default:
+		l.errorf(CodeInvalidStateType, location+"/Type",
+			"state %q has unknown Type %q", name, stateType)
+		return nil, false
+	}
+
+	switch stateType {
+	case "Task", "Parallel", "Map":
+		l.lintRetriers(location, s.Retry)
+		edges = append(edges, l.lintCatchers(location, s.Catch)...)
+	default:
+		if len(s.Retry) > 0 || len(s.Catch) > 0 {
+			l.errorf(CodeInvalidState, location,
+				"%s state %q cannot set Retry or Catch", stateType, name)
+		}
+	}
+
+	switch stateType {
+	case "Parallel":
+		if len(s.Branches) == 0 {
+			l.errorf(CodeInvalidBranch, location+"/Branches",
+				"Parallel state %q must have at least one branch", name)
+		}
+		for i, raw := range s.Branches {
+			l.lintNestedScope(location+"/Branches/"+strconv.Itoa(i), raw)
+		}
+	case "Map":
+		switch {
+		case len(s.ItemProcessor) > 0:
+			l.lintNestedScope(location+"/ItemProcessor", s.ItemProcessor)
+		case len(s.Iterator) > 0:
+			l.lintNestedScope(location+"/Iterator", s.Iterator)
+		default:
+			l.errorf(CodeInvalidBranch, location,
+				"Map state %q must set ItemProcessor", name)
+		}
+	}
+	return edges, terminal
+}
+
+// lintNestedScope checks the state machine of a Parallel branch or a Map
+// processor.
+func (l *linter) lintNestedScope(location string, raw json.RawMessage) {
+	var scope map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &scope); err != nil || scope == nil {
+		l.errorf(CodeInvalidBranch, location, "must be an object with StartAt and States")
+		return
+	}
+	l.lintScope(location, scope)
+}
+
+// escapePointer escapes a state name for use in a JSON pointer, as defined
+// in RFC 6901.
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
@@ -0,0 +1,568 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package lint
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// codes returns the sorted codes of the supplied diagnostics.
+func codes(diagnostics []Diagnostic) []string {
+	out := []string{}
+	for _, d := range diagnostics {
+		out = append(out, d.Code)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func runLintTests(t *testing.T, tests []struct {
+	name       string
+	definition string
+	want       []string
+}) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := append([]string{}, tt.want...)
+			sort.Strings(want)
+			got := codes(Lint(tt.definition))
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Lint() codes = %v, want %v\n%v", got, want, Lint(tt.definition))
+			}
+		})
+	}
+}
+
+func TestLintStateGraph(t *testing.T) {
+	runLintTests(t, []struct {
+		name       string
+		definition string
+		want       []string
+	}{
+		{
+			name:       "valid single state",
+			definition: `{"StartAt": "A", "States": {"A": {"Type": "Pass", "End": true}}}`,
+		},
+		{
+			name: "valid chain",
+			definition: `{"StartAt": "A", "States": {
+				"A": {"Type": "Task", "Resource": "arn:aws:states:::lambda:invoke", "Next": "B"},
+				"B": {"Type": "Wait", "Seconds": 1, "Next": "C"},
+				"C": {"Type": "Succeed"}}}`,
+		},
+		{
+			name:       "definition too large",
+			definition: `{"StartAt": "A", "Comment": "` + strings.Repeat("x", MaxDefinitionSize) + `"}`,
+			want:       []string{CodeDefinitionTooLarge},
+		},
+		{
+			name:       "invalid JSON",
+			definition: `{"StartAt": "A",`,
+			want:       []string{CodeInvalidJSON},
+		},
+		{
+			name:       "not an object",
+			definition: `[]`,
+			want:       []string{CodeInvalidJSON},
+		},
+		{
+			name:       "missing StartAt",
+			definition: `{"States": {"A": {"Type": "Succeed"}}}`,
+			want:       []string{CodeMissingStartAt},
+		},
+		{
+			name:       "empty StartAt",
+			definition: `{"StartAt": "", "States": {"A": {"Type": "Succeed"}}}`,
+			want:       []string{CodeMissingStartAt},
+		},
+		{
+			name:       "StartAt not found",
+			definition: `{"StartAt": "B", "States": {"A": {"Type": "Succeed"}}}`,
+			want:       []string{CodeStartAtNotFound},
+		},
+		{
+			name:       "missing States",
+			definition: `{"StartAt": "A"}`,
+			want:       []string{CodeMissingStates},
+		},
+		{
+			name:       "empty States",
+			definition: `{"StartAt": "A", "States": {}}`,
+			want:       []string{CodeMissingStates},
+		},
+		{
+			name:       "state is not an object",
+			definition: `{"StartAt": "A", "States": {"A": 1}}`,
+			want:       []string{CodeInvalidState, CodeNoTerminalState},
+		},
+		{
+			name:       "state without Type",
+			definition: `{"StartAt": "A", "States": {"A": {"End": true}}}`,
+			want:       []string{CodeInvalidStateType, CodeNoTerminalState},
+		},
+		{
+			name:       "unknown state Type",
+			definition: `{"StartAt": "A", "States": {"A": {"Type": "Sleep", "End": true}}}`,
+			want:       []string{CodeInvalidStateType, CodeNoTerminalState},
+		},
+		{
+			name:       "Next and End",
+			definition: `{"StartAt": "A", "States": {"A": {"Type": "Pass", "Next": "A", "End": true}}}`,
+			want:       []string{CodeAmbiguousTransition, CodeNoTerminalState},
+		},
+		{
+			name:       "neither Next nor End",
+			definition: `{"StartAt": "A", "States": {"A": {"Type": "Pass"}}}`,
+			want:       []string{CodeMissingTransition, CodeNoTerminalState},
+		},
+		{
+			name:       "End false",
+			definition: `{"StartAt": "A", "States": {"A": {"Type": "Pass", "End": false}}}`,
+			want:       []string{CodeMissingTransition, CodeNoTerminalState},
+		},
+		{
+			name: "Next target not found",
+			definition: `{"StartAt": "A", "States": {
+				"A": {"Type": "Pass", "Next": "Missing"}}}`,
+			want: []string{CodeTransitionNotFound, CodeNoTerminalState},
+		},
+		{
+			name: "unreachable state",
+			definition: `{"StartAt": "A", "States": {
+				"A": {"Type": "Succeed"},
+				"B": {"Type": "Succeed"}}}`,
+			want: []string{CodeUnreachableState},
+		},
+		{
+			name: "loop without terminal state",
+			definition: `{"StartAt": "A", "States": {
+				"A": {"Type": "Pass", "Next": "B"},
+				"B": {"Type": "Pass", "Next": "A"}}}`,
+			want: []string{CodeNoTerminalState},
+		},
+		{
+			name:       "Succeed with Next",
+			definition: `{"StartAt": "A", "States": {"A": {"Type": "Succeed", "Next": "A"}}}`,
+			want:       []string{CodeInvalidTransition},
+		},
+		{
+			name:       "Fail with End",
+			definition: `{"StartAt": "A", "States": {"A": {"Type": "Fail", "End": true}}}`,
+			want:       []string{CodeInvalidTransition},
+		},
+		{
+			name: "Retry on a Pass state",
+			definition: `{"StartAt": "A", "States": {
+				"A": {"Type": "Pass", "End": true, "Retry": [{"ErrorEquals": ["States.ALL"]}]}}}`,
+			want: []string{CodeInvalidState},
+		},
+		{
+			name: "Catch on a Wait state",
+			definition: `{"StartAt": "A", "States": {
+				"A": {"Type": "Wait", "Seconds": 1, "End": true, "Catch": [{"ErrorEquals": ["States.ALL"], "Next": "A"}]}}}`,
+			want: []string{CodeInvalidState},
+		},
+	})
+}
+
+func TestLintNestedScopes(t *testing.T) {
+	runLintTests(t, []struct {
+		name       string
+		definition string
+		want       []string
+	}{
+		{
+			name: "valid Parallel",
+			definition: `{"StartAt": "P", "States": {
+				"P": {"Type": "Parallel", "End": true, "Branches": [
+					{"StartAt": "B1", "States": {"B1": {"Type": "Pass", "End": true}}},
+					{"StartAt": "B2", "States": {"B2": {"Type": "Succeed"}}}]}}}`,
+		},
+		{
+			name: "Parallel without branches",
+			definition: `{"StartAt": "P", "States": {
+				"P": {"Type": "Parallel", "End": true, "Branches": []}}}`,
+			want: []string{CodeInvalidBranch},
+		},
+		{
+			name: "Parallel branch is not an object",
+			definition: `{"StartAt": "P", "States": {
+				"P": {"Type": "Parallel", "End": true, "Branches": [1]}}}`,
+			want: []string{CodeInvalidBranch},
+		},
+		{
+			name: "invalid Parallel branch",
+			definition: `{"StartAt": "P", "States": {
+				"P": {"Type": "Parallel", "End": true, "Branches": [
+					{"StartAt": "B1", "States": {"B1": {"Type": "Pass"}}}]}}}`,
+			want: []string{CodeMissingTransition, CodeNoTerminalState},
+		},
+		{
+			name: "state name reused in a Parallel branch",
+			definition: `{"StartAt": "P", "States": {
+				"P": {"Type": "Parallel", "End": true, "Branches": [
+					{"StartAt": "P", "States": {"P": {"Type": "Succeed"}}}]}}}`,
+			want: []string{CodeDuplicateStateName},
+		},
+		{
+			name: "valid Map ItemProcessor",
+			definition: `{"StartAt": "M", "States": {
+				"M": {"Type": "Map", "End": true, "ItemProcessor": {
+					"StartAt": "I", "States": {"I": {"Type": "Pass", "End": true}}}}}}`,
+		},
+		{
+			name: "valid Map Iterator",
+			definition: `{"StartAt": "M", "States": {
+				"M": {"Type": "Map", "End": true, "Iterator": {
+					"StartAt": "I", "States": {"I": {"Type": "Pass", "End": true}}}}}}`,
+		},
+		{
+			name: "Map without processor",
+			definition: `{"StartAt": "M", "States": {
+				"M": {"Type": "Map", "End": true}}}`,
+			want: []string{CodeInvalidBranch},
+		},
+		{
+			name: "Map processor without States",
+			definition: `{"StartAt": "M", "States": {
+				"M": {"Type": "Map", "End": true, "ItemProcessor": {"StartAt": "I"}}}}`,
+			want: []string{CodeMissingStates},
+		},
+	})
+}
+
+func TestLintChoice(t *testing.T) {
+	choice := func(choiceState string) string {
+		return `{"StartAt": "C", "States": {
+			"C": ` + choiceState + `,
+			"A": {"Type": "Succeed"},
+			"B": {"Type": "Succeed"}}}`
+	}
+	runLintTests(t, []struct {
+		name       string
+		definition string
+		want       []string
+	}{
+		{
+			name: "valid comparison",
+			definition: choice(`{"Type": "Choice", "Default": "B", "Choices": [
+				{"Variable": "$.x", "NumericEquals": 1, "Next": "A"}]}`),
+		},
+		{
+			name: "valid path comparison",
+			definition: choice(`{"Type": "Choice", "Default": "B", "Choices": [
+				{"Variable": "$.x", "StringEqualsPath": "$.y", "Next": "A"}]}`),
+		},
+		{
+			name: "valid And",
+			definition: choice(`{"Type": "Choice", "Default": "B", "Choices": [
+				{"And": [
+					{"Variable": "$.x", "IsPresent": true},
+					{"Variable": "$.x", "NumericGreaterThan": 1}], "Next": "A"}]}`),
+		},
+		{
+			name: "valid nested Or and Not",
+			definition: choice(`{"Type": "Choice", "Default": "B", "Choices": [
+				{"Or": [
+					{"Not": {"Variable": "$.x", "IsNull": true}},
+					{"Variable": "$.y", "BooleanEquals": true}], "Next": "A"}]}`),
+		},
+		{
+			name: "valid JSONata Condition",
+			definition: choice(`{"Type": "Choice", "Default": "B", "Choices": [
+				{"Condition": "{% $states.input.x > 1 %}", "Next": "A"}]}`),
+		},
+		{
+			name: "missing Default",
+			definition: `{"StartAt": "C", "States": {
+				"C": {"Type": "Choice", "Choices": [{"Variable": "$.x", "IsPresent": true, "Next": "A"}]},
+				"A": {"Type": "Succeed"}}}`,
+			want: []string{CodeMissingChoiceDefault},
+		},
+		{
+			name:       "no rules",
+			definition: choice(`{"Type": "Choice", "Default": "A", "Choices": []}`),
+			want:       []string{CodeInvalidChoiceRule, CodeUnreachableState},
+		},
+		{
+			name: "Choice with Next",
+			definition: choice(`{"Type": "Choice", "Default": "B", "Next": "A", "Choices": [
+				{"Variable": "$.x", "IsPresent": true, "Next": "A"}]}`),
+			want: []string{CodeInvalidTransition},
+		},
+		{
+			name: "rule is not an object",
+			definition: choice(`{"Type": "Choice", "Default": "B", "Choices": [
+				1, {"Variable": "$.x", "IsPresent": true, "Next": "A"}]}`),
+			want: []string{CodeInvalidChoiceRule},
+		},
+		{
+			name: "rule without Next",
+			definition: choice(`{"Type": "Choice", "Default": "B", "Choices": [
+				{"Variable": "$.x", "IsPresent": true},
+				{"Variable": "$.x", "IsNull": true, "Next": "A"}]}`),
+			want: []string{CodeMissingTransition},
+		},
+		{
+			name: "rule with an empty Next",
+			definition: choice(`{"Type": "Choice", "Default": "B", "Choices": [
+				{"Variable": "$.x", "IsPresent": true, "Next": ""},
+				{"Variable": "$.x", "IsNull": true, "Next": "A"}]}`),
+			want: []string{CodeInvalidTransition},
+		},
+		{
+			name: "rule Next not found",
+			definition: choice(`{"Type": "Choice", "Default": "B", "Choices": [
+				{"Variable": "$.x", "IsPresent": true, "Next": "Missing"},
+				{"Variable": "$.x", "IsNull": true, "Next": "A"}]}`),
+			want: []string{CodeTransitionNotFound},
+		},
+		{
+			name: "Default not found",
+			definition: choice(`{"Type": "Choice", "Default": "Missing", "Choices": [
+				{"Variable": "$.x", "IsPresent": true, "Next": "A"}]}`),
+			want: []string{CodeTransitionNotFound, CodeUnreachableState},
+		},
+		{
+			name: "rule without operator",
+			definition: choice(`{"Type": "Choice", "Default": "B", "Choices": [
+				{"Variable": "$.x", "Next": "A"}]}`),
+			want: []string{CodeInvalidChoiceRule},
+		},
+		{
+			name: "rule with two operators",
+			definition: choice(`{"Type": "Choice", "Default": "B", "Choices": [
+				{"Variable": "$.x", "IsPresent": true, "IsNull": true, "Next": "A"}]}`),
+			want: []string{CodeInvalidChoiceRule},
+		},
+		{
+			name: "comparison without Variable",
+			definition: choice(`{"Type": "Choice", "Default": "B", "Choices": [
+				{"NumericEquals": 1, "Next": "A"}]}`),
+			want: []string{CodeInvalidChoiceRule},
+		},
+		{
+			name: "Variable is not a path",
+			definition: choice(`{"Type": "Choice", "Default": "B", "Choices": [
+				{"Variable": "x", "NumericEquals": 1, "Next": "A"}]}`),
+			want: []string{CodeInvalidChoiceRule},
+		},
+		{
+			name: "Path operator value is not a path",
+			definition: choice(`{"Type": "Choice", "Default": "B", "Choices": [
+				{"Variable": "$.x", "StringEqualsPath": "y", "Next": "A"}]}`),
+			want: []string{CodeInvalidChoiceRule},
+		},
+		{
+			name: "empty And",
+			definition: choice(`{"Type": "Choice", "Default": "B", "Choices": [
+				{"And": [], "Next": "A"}]}`),
+			want: []string{CodeInvalidChoiceRule},
+		},
+		{
+			name: "nested rule with Next",
+			definition: choice(`{"Type": "Choice", "Default": "B", "Choices": [
+				{"Or": [{"Variable": "$.x", "IsPresent": true, "Next": "B"}], "Next": "A"}]}`),
+			want: []string{CodeInvalidChoiceRule},
+		},
+		{
+			name: "invalid nested rule",
+			definition: choice(`{"Type": "Choice", "Default": "B", "Choices": [
+				{"And": [{"Variable": "$.x"}], "Next": "A"}]}`),
+			want: []string{CodeInvalidChoiceRule},
+		},
+		{
+			name: "Not is not a rule",
+			definition: choice(`{"Type": "Choice", "Default": "B", "Choices": [
+				{"Not": [], "Next": "A"}]}`),
+			want: []string{CodeInvalidChoiceRule},
+		},
+		{
+			name: "Condition with Variable",
+			definition: choice(`{"Type": "Choice", "Default": "B", "Choices": [
+				{"Condition": "{% true %}", "Variable": "$.x", "Next": "A"}]}`),
+			want: []string{CodeInvalidChoiceRule},
+		},
+	})
+}
+
+func TestLintRetryAndCatch(t *testing.T) {
+	task := func(fields string) string {
+		return `{"StartAt": "T", "States": {
+			"T": {"Type": "Task", "Resource": "arn:aws:states:::lambda:invoke", "End": true, ` + fields + `},
+			"H": {"Type": "Fail"}}}`
+	}
+	runLintTests(t, []struct {
+		name       string
+		definition string
+		want       []string
+	}{
+		{
+			name: "valid Retry and Catch",
+			definition: task(`
+				"Retry": [
+					{"ErrorEquals": ["Lambda.ServiceException", "States.Timeout"]},
+					{"ErrorEquals": ["States.ALL"]}],
+				"Catch": [{"ErrorEquals": ["States.ALL"], "Next": "H"}]`),
+		},
+		{
+			name:       "Retry entry is not valid",
+			definition: task(`"Retry": [{"ErrorEquals": "States.ALL"}], "Catch": [{"ErrorEquals": ["States.ALL"], "Next": "H"}]`),
+			want:       []string{CodeInvalidRetrier},
+		},
+		{
+			name:       "Retry without error names",
+			definition: task(`"Retry": [{"ErrorEquals": []}], "Catch": [{"ErrorEquals": ["States.ALL"], "Next": "H"}]`),
+			want:       []string{CodeInvalidRetrier},
+		},
+		{
+			name:       "empty error name",
+			definition: task(`"Retry": [{"ErrorEquals": [""]}], "Catch": [{"ErrorEquals": ["States.ALL"], "Next": "H"}]`),
+			want:       []string{CodeInvalidErrorName},
+		},
+		{
+			name:       "reserved error name",
+			definition: task(`"Retry": [{"ErrorEquals": ["States.Unknown"]}], "Catch": [{"ErrorEquals": ["States.ALL"], "Next": "H"}]`),
+			want:       []string{CodeInvalidErrorName},
+		},
+		{
+			name:       "States.ALL with other error names",
+			definition: task(`"Retry": [{"ErrorEquals": ["States.ALL", "Custom"]}], "Catch": [{"ErrorEquals": ["States.ALL"], "Next": "H"}]`),
+			want:       []string{CodeInvalidErrorName},
+		},
+		{
+			name: "States.ALL not in the last Retry entry",
+			definition: task(`"Retry": [
+				{"ErrorEquals": ["States.ALL"]},
+				{"ErrorEquals": ["Custom"]}], "Catch": [{"ErrorEquals": ["States.ALL"], "Next": "H"}]`),
+			want: []string{CodeInvalidErrorName},
+		},
+		{
+			name: "States.ALL not in the last Catch entry",
+			definition: task(`"Catch": [
+				{"ErrorEquals": ["States.ALL"], "Next": "H"},
+				{"ErrorEquals": ["Custom"], "Next": "H"}]`),
+			want: []string{CodeInvalidErrorName},
+		},
+		{
+			name:       "Catch entry is not valid",
+			definition: task(`"Catch": [1]`),
+			want:       []string{CodeInvalidCatcher, CodeUnreachableState},
+		},
+		{
+			name:       "Catch without error names",
+			definition: task(`"Catch": [{"ErrorEquals": [], "Next": "H"}]`),
+			want:       []string{CodeInvalidCatcher},
+		},
+		{
+			name:       "Catch without Next",
+			definition: task(`"Catch": [{"ErrorEquals": ["States.ALL"]}]`),
+			want:       []string{CodeMissingTransition, CodeUnreachableState},
+		},
+		{
+			name:       "Catch Next not found",
+			definition: task(`"Catch": [{"ErrorEquals": ["States.ALL"], "Next": "Missing"}]`),
+			want:       []string{CodeTransitionNotFound, CodeUnreachableState},
+		},
+	})
+}
+
+func TestLintDiagnosticDetails(t *testing.T) {
+	diagnostics := Lint(`{"StartAt": "a/b", "States": {
+		"a/b": {"Type": "Choice", "Choices": [{"Variable": "$.x", "IsPresent": true, "Next": "c~d"}]},
+		"c~d": {"Type": "Pass", "Next": "Missing"},
+		"e": {"Type": "Succeed"}}}`)
+	want := []Diagnostic{
+		{
+			Code:     CodeMissingChoiceDefault,
+			Location: "/States/a~1b",
+			Message:  `Choice state "a/b" has no Default, executions that match no rule fail with States.NoChoiceMatched`,
+			Severity: SeverityWarning,
+		},
+		{
+			Code:     CodeTransitionNotFound,
+			Location: "/States/c~0d/Next",
+			Message:  `state "c~d" transitions to "Missing", which does not exist`,
+			Severity: SeverityError,
+		},
+		{
+			Code:     CodeUnreachableState,
+			Location: "/States/e",
+			Message:  `state "e" is not reachable from StartAt "a/b"`,
+			Severity: SeverityError,
+		},
+	}
+	if !reflect.DeepEqual(diagnostics, want) {
+		t.Errorf("Lint() = %#v, want %#v", diagnostics, want)
+	}
+}
+
+func TestHasErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		diagnostics []Diagnostic
+		want        bool
+	}{
+		{name: "none"},
+		{
+			name:        "warnings only",
+			diagnostics: []Diagnostic{{Code: CodeMissingChoiceDefault, Severity: SeverityWarning}},
+		},
+		{
+			name: "errors and warnings",
+			diagnostics: []Diagnostic{
+				{Code: CodeMissingChoiceDefault, Severity: SeverityWarning},
+				{Code: CodeUnreachableState, Severity: SeverityError},
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HasErrors(tt.diagnostics); got != tt.want {
+				t.Errorf("HasErrors() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiagnosticString(t *testing.T) {
+	tests := []struct {
+		name       string
+		diagnostic Diagnostic
+		want       string
+	}{
+		{
+			name:       "without location",
+			diagnostic: Diagnostic{Code: CodeInvalidJSON, Message: "bad", Severity: SeverityError},
+			want:       "ERROR INVALID_JSON: bad",
+		},
+		{
+			name:       "with location",
+			diagnostic: Diagnostic{Code: CodeMissingChoiceDefault, Location: "/States/C", Message: "no default", Severity: SeverityWarning},
+			want:       "WARNING MISSING_CHOICE_DEFAULT: no default (at /States/C)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.diagnostic.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
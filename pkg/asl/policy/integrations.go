@@ -0,0 +1,407 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	patternSync             = ".sync"
+	patternSync2            = ".sync:2"
+	patternWaitForTaskToken = ".waitForTaskToken"
+)
+
+// iamServicePrefixes maps the service names of aws-sdk integrations to
+// their IAM action prefix when the two differ.
+var iamServicePrefixes = map[string]string{
+	"cloudwatchlogs": "logs",
+	"eventbridge":    "events",
+	"opensearch":     "es",
+	"sfn":            "states",
+}
+
+// iamActionOverrides maps integrations to their IAM action when it is not
+// the capitalized API action.
+var iamActionOverrides = map[string]string{
+	"s3:listObjectsV2": "s3:ListBucket",
+}
+
+// syncActions are the additional actions needed by the .sync pattern of the
+// optimized integrations to follow and stop the job they start.
+var syncActions = map[string][]string{
+	"batch:submitJob":       {"batch:DescribeJobs", "batch:TerminateJob"},
+	"codebuild:startBuild":  {"codebuild:BatchGetBuilds", "codebuild:StopBuild"},
+	"ecs:runTask":           {"ecs:DescribeTasks", "ecs:StopTask"},
+	"glue:startJobRun":      {"glue:BatchStopJobRun", "glue:GetJobRun", "glue:GetJobRuns"},
+	"states:startExecution": {"states:DescribeExecution", "states:StopExecution"},
+}
+
+// managedRules are the EventBridge rules Step Functions manages to be
+// notified of the completion of the jobs started by the .sync pattern of the
+// optimized integrations. Glue job runs are polled and need no rule. The
+// rules of the integrations not listed are matched with a wildcard.
+var managedRules = map[string]string{
+	"batch:submitJob":       "StepFunctionsGetEventsForBatchJobsRule",
+	"codebuild:startBuild":  "StepFunctionsGetEventsForCodeBuildStartBuildRule",
+	"ecs:runTask":           "StepFunctionsGetEventsForECSTaskRule",
+	"glue:startJobRun":      "",
+	"states:startExecution": "StepFunctionsGetEventsForStepFunctionsExecutionRule",
+}
+
+// task adds the permissions of a Task state, or of the ItemReader and
+// ResultWriter of a Map state.
+func (g *generator) task(
+	location string,
+	resource string,
+	params map[string]json.RawMessage,
+) error {
+	arn := strings.Split(resource, ":")
+	if len(arn) < 6 || arn[0] != "arn" {
+		return fmt.Errorf("%s: cannot determine the permissions of Resource %q", location, resource)
+	}
+	service := arn[2]
+	switch {
+	case service == "states" && arn[3] == "" && arn[4] == "":
+		return g.integration(location, strings.Join(arn[5:], ":"), params)
+	case service == "states" && arn[5] == "activity":
+		// Activity workers call GetActivityTask and SendTask*, the execution
+		// role does not need any permission.
+		return nil
+	case service == "lambda":
+		g.permissions.Allow([]string{"lambda:InvokeFunction"}, resource, resource+":*")
+		return nil
+	default:
+		return fmt.Errorf("%s: cannot determine the permissions of Resource %q", location, resource)
+	}
+}
+
+// integration adds the permissions of an optimized or aws-sdk service
+// integration, such as "lambda:invoke.waitForTaskToken".
+func (g *generator) integration(
+	location string,
+	integration string,
+	params map[string]json.RawMessage,
+) error {
+	pattern := ""
+	for _, p := range []string{patternSync2, patternSync, patternWaitForTaskToken} {
+		if strings.HasSuffix(integration, p) {
+			pattern = p
+			integration = strings.TrimSuffix(integration, p)
+			break
+		}
+	}
+	parts := strings.Split(integration, ":")
+	if len(parts) == 3 && parts[0] == "aws-sdk" {
+		prefix := parts[1]
+		if p, ok := iamServicePrefixes[prefix]; ok {
+			prefix = p
+		}
+		g.permissions.Allow([]string{prefix + ":" + capitalize(parts[2])}, g.sdkResources(prefix, params)...)
+		return nil
+	}
+	if len(parts) != 2 {
+		return fmt.Errorf("%s: unknown service integration %q", location, integration)
+	}
+	service, api := parts[0], parts[1]
+	sync := pattern == patternSync || pattern == patternSync2
+
+	switch integration {
+	case "lambda:invoke":
+		fn := g.arn("lambda", "function:*")
+		if name, ok := parameter(params, "FunctionName"); ok {
+			fn = name
+			if !strings.HasPrefix(name, "arn:") {
+				fn = g.arn("lambda", "function:"+name)
+			}
+		}
+		g.permissions.Allow([]string{"lambda:InvokeFunction"}, fn, fn+":*")
+	case "sqs:sendMessage":
+		resource := g.arn("sqs", "*")
+		if url, ok := parameter(params, "QueueUrl"); ok {
+			resource = g.queueARN(url)
+		}
+		g.permissions.Allow([]string{"sqs:SendMessage"}, resource)
+	case "sns:publish":
+		resource := g.arn("sns", "*")
+		if topic, ok := parameter(params, "TopicArn"); ok {
+			resource = topic
+		} else if target, ok := parameter(params, "TargetArn"); ok {
+			resource = target
+		}
+		g.permissions.Allow([]string{"sns:Publish"}, resource)
+	case "dynamodb:getItem", "dynamodb:putItem", "dynamodb:updateItem", "dynamodb:deleteItem":
+		resource := g.arn("dynamodb", "table/*")
+		if table, ok := parameter(params, "TableName"); ok {
+			resource = table
+			if !strings.HasPrefix(table, "arn:") {
+				resource = g.arn("dynamodb", "table/"+table)
+			}
+		}
+		g.permissions.Allow([]string{"dynamodb:" + capitalize(api)}, resource)
+	case "states:startExecution":
+		arn, _ := parameter(params, "StateMachineArn")
+		g.startExecution(arn, sync)
+	case "ecs:runTask":
+		g.runTask(params, sync)
+	default:
+		action := service + ":" + capitalize(api)
+		if override, ok := iamActionOverrides[integration]; ok {
+			action = override
+		}
+		resources := g.jobResources(integration, params)
+		g.permissions.Allow([]string{action}, resources...)
+		if sync {
+			syncResources := resources
+			if integration == "batch:submitJob" {
+				// DescribeJobs does not support resource-level permissions.
+				syncResources = []string{"*"}
+			}
+			g.permissions.Allow(syncActions[integration], syncResources...)
+			g.allowSyncRule(integration)
+		}
+		if service == "sagemaker" && strings.HasPrefix(api, "create") {
+			role := g.iamARN("role/*")
+			if arn, ok := parameter(params, "RoleArn"); ok {
+				role = arn
+			}
+			g.permissions.AllowPassRole("sagemaker.amazonaws.com", role)
+		}
+	}
+	return nil
+}
+
+// jobResources returns the resources an optimized integration acts on, as
+// far as they are known from the static parameters of the Task state.
+func (g *generator) jobResources(integration string, params map[string]json.RawMessage) []string {
+	switch integration {
+	case "batch:submitJob":
+		queue, definition := g.arn("batch", "job-queue/*"), g.arn("batch", "job-definition/*")
+		if name, ok := parameter(params, "JobQueue"); ok {
+			queue = g.nameOrARN("batch", "job-queue/", name)
+		}
+		if name, ok := parameter(params, "JobDefinition"); ok {
+			definition = anyRevision(g.nameOrARN("batch", "job-definition/", name))
+		}
+		return []string{definition, queue}
+	case "codebuild:startBuild":
+		if name, ok := parameter(params, "ProjectName"); ok {
+			return []string{g.nameOrARN("codebuild", "project/", name)}
+		}
+		return []string{g.arn("codebuild", "project/*")}
+	case "glue:startJobRun":
+		if name, ok := parameter(params, "JobName"); ok {
+			return []string{g.arn("glue", "job/"+name)}
+		}
+		return []string{g.arn("glue", "job/*")}
+	}
+	if integration == "s3:listObjectsV2" {
+		if bucket, ok := parameter(params, "Bucket"); ok {
+			return []string{fmt.Sprintf("arn:%s:s3:::%s", g.env.Partition, bucket)}
+		}
+		return []string{"*"}
+	}
+	if strings.HasPrefix(integration, "s3:") {
+		return s3Resources(g.env.Partition, params)
+	}
+	return []string{"*"}
+}
+
+// runTask adds the permissions of the ECS RunTask integration: running the
+// task definition, passing its roles to ECS and, for the .sync pattern,
+// following and stopping the task.
+func (g *generator) runTask(params map[string]json.RawMessage, sync bool) {
+	definition := g.arn("ecs", "task-definition/*")
+	if name, ok := parameter(params, "TaskDefinition"); ok {
+		definition = anyRevision(g.nameOrARN("ecs", "task-definition/", name))
+	}
+	g.permissions.Allow([]string{"ecs:RunTask"}, definition)
+
+	// The roles of the task definition are not known, unless they are
+	// overridden with static ARNs.
+	roles := []string{}
+	for _, field := range []string{"TaskRoleArn", "ExecutionRoleArn"} {
+		if arn, ok := parameter(params, "Overrides", field); ok {
+			roles = append(roles, arn)
+		}
+	}
+	if len(roles) == 0 {
+		roles = []string{g.iamARN("role/*")}
+	}
+	g.permissions.AllowPassRole("ecs-tasks.amazonaws.com", roles...)
+
+	if sync {
+		tasks := g.arn("ecs", "task/*")
+		if cluster, ok := parameter(params, "Cluster"); ok && !strings.HasPrefix(cluster, "arn:") {
+			tasks = g.arn("ecs", "task/"+cluster+"/*")
+		}
+		g.permissions.Allow(syncActions["ecs:runTask"], tasks)
+		g.allowSyncRule("ecs:runTask")
+	}
+}
+
+// sdkResources returns the resources an aws-sdk integration acts on: the
+// ARNs found in the static parameters of the Task state, the objects of the
+// S3 bucket it names, or "*" if they are not known.
+func (g *generator) sdkResources(prefix string, params map[string]json.RawMessage) []string {
+	if prefix == "s3" {
+		return s3Resources(g.env.Partition, params)
+	}
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	resources := []string{}
+	for _, name := range names {
+		if value, ok := parameter(params, name); ok && strings.HasPrefix(value, "arn:") {
+			resources = append(resources, value)
+		}
+	}
+	if len(resources) == 0 {
+		return []string{"*"}
+	}
+	return resources
+}
+
+// s3Resources returns the object or the objects under the prefix named by
+// the static Bucket, Key and Prefix parameters, the bucket and all its
+// objects if only the bucket is known, or "*".
+func s3Resources(partition string, params map[string]json.RawMessage) []string {
+	bucket, ok := parameter(params, "Bucket")
+	if !ok {
+		return []string{"*"}
+	}
+	bucketARN := fmt.Sprintf("arn:%s:s3:::%s", partition, bucket)
+	if key, ok := parameter(params, "Key"); ok {
+		return []string{bucketARN + "/" + key}
+	}
+	if prefix, ok := parameter(params, "Prefix"); ok {
+		return []string{bucketARN + "/" + prefix + "*"}
+	}
+	return []string{bucketARN, bucketARN + "/*"}
+}
+
+// startExecution adds the permissions to start, and for the .sync pattern
+// follow and stop, executions of a state machine. An empty ARN means that
+// the state machine is only known at runtime.
+func (g *generator) startExecution(stateMachineARN string, sync bool) {
+	stateMachine, executions := g.arn("states", "stateMachine:*"), g.arn("states", "execution:*")
+	if stateMachineARN != "" {
+		stateMachine = stateMachineARN
+		// arn:partition:states:region:account:stateMachine:name[:version]
+		parts := strings.Split(stateMachineARN, ":")
+		if len(parts) >= 7 {
+			executions = executionsARN(parts, ":*")
+			if len(parts) > 7 {
+				stateMachine = strings.Join(parts[:7], ":") + ":*"
+			}
+		}
+	}
+	g.permissions.Allow([]string{"states:StartExecution"}, stateMachine)
+	if sync {
+		g.permissions.Allow(syncActions["states:startExecution"], executions)
+		g.allowSyncRule("states:startExecution")
+	}
+}
+
+// childExecutions adds the permissions a distributed Map state needs to run
+// its iterations as child executions of the state machine itself.
+func (g *generator) childExecutions() {
+	stateMachine, executions := g.arn("states", "stateMachine:*"), g.arn("states", "execution:*")
+	if parts := strings.Split(g.env.StateMachineARN, ":"); len(parts) == 7 {
+		stateMachine = g.env.StateMachineARN
+		executions = executionsARN(parts, "/*")
+	}
+	g.permissions.Allow([]string{"states:StartExecution"}, stateMachine)
+	g.permissions.Allow([]string{"states:DescribeExecution", "states:StopExecution"}, executions)
+}
+
+// executionsARN returns the pattern matching the ARNs of the executions of
+// the state machine whose ARN parts are supplied.
+func executionsARN(stateMachineARN []string, suffix string) string {
+	return strings.Join(append(stateMachineARN[:5:5], "execution", stateMachineARN[6]), ":") + suffix
+}
+
+// allowSyncRule adds the permissions on the EventBridge rule Step Functions
+// manages to be notified of the completion of the jobs started by the .sync
+// pattern of the supplied integration, see managedRules.
+func (g *generator) allowSyncRule(integration string) {
+	rule, ok := managedRules[integration]
+	if !ok {
+		rule = "StepFunctions*"
+	}
+	if rule == "" {
+		return
+	}
+	g.permissions.Allow(
+		[]string{"events:DescribeRule", "events:PutRule", "events:PutTargets"},
+		g.arn("events", "rule/"+rule),
+	)
+}
+
+// arn returns the ARN of a resource of the supplied service in the region
+// and account of the state machine.
+func (g *generator) arn(service, resource string) string {
+	return fmt.Sprintf("arn:%s:%s:%s:%s:%s", g.env.Partition, service, g.env.Region, g.env.AccountID, resource)
+}
+
+// anyRevision returns the ARN of a job or task definition, matching any
+// revision if the ARN does not end with one.
+func anyRevision(arn string) string {
+	if strings.Contains(arn[strings.LastIndex(arn, "/")+1:], ":") {
+		return arn
+	}
+	return arn + ":*"
+}
+
+// iamARN returns the ARN of an IAM resource of the account of the state
+// machine.
+func (g *generator) iamARN(resource string) string {
+	return fmt.Sprintf("arn:%s:iam::%s:%s", g.env.Partition, g.env.AccountID, resource)
+}
+
+// nameOrARN returns the supplied ARN, or the ARN of the resource of the
+// supplied service with the supplied type prefix and name.
+func (g *generator) nameOrARN(service, prefix, name string) string {
+	if strings.HasPrefix(name, "arn:") {
+		return name
+	}
+	return g.arn(service, prefix+name)
+}
+
+// queueARN returns the ARN of an SQS queue given its URL, for example
+// https://sqs.us-west-2.amazonaws.com/123456789012/queue.
+func (g *generator) queueARN(url string) string {
+	parts := strings.Split(strings.TrimPrefix(strings.TrimPrefix(url, "https://"), "http://"), "/")
+	if len(parts) != 3 {
+		return "*"
+	}
+	region := g.env.Region
+	if host := strings.Split(parts[0], "."); len(host) > 2 && host[0] == "sqs" {
+		region = host[1]
+	}
+	return fmt.Sprintf("arn:%s:sqs:%s:%s:%s", g.env.Partition, region, parts[1], parts[2])
+}
+
+// capitalize returns the IAM action name of a camelCase API action.
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,442 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package policy
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const (
+	testAccountID = "123456789012"
+	testARNPrefix = ":us-east-1:123456789012:"
+)
+
+var testEnv = Environment{
+	Partition:       "aws",
+	Region:          "us-east-1",
+	AccountID:       testAccountID,
+	StateMachineARN: "arn:aws:states:us-east-1:123456789012:stateMachine:parent",
+}
+
+// taskDefinition returns a definition with a single Task state using the
+// supplied resource and parameters.
+func taskDefinition(resource, parameters string) string {
+	if parameters == "" {
+		parameters = "{}"
+	}
+	return `{"StartAt": "T", "States": {"T": {"Type": "Task", "End": true,
+		"Resource": "` + resource + `", "Parameters": ` + parameters + `}}}`
+}
+
+// arn returns the ARN of a resource of the supplied service in the test
+// region and account.
+func arn(service, resource string) string {
+	return "arn:aws:" + service + testARNPrefix + resource
+}
+
+// flatten returns the resources allowed for each action of the supplied
+// document. iam:PassRole actions are suffixed with the service the roles
+// may be passed to.
+func flatten(doc *Document) map[string][]string {
+	out := map[string][]string{}
+	for _, statement := range doc.Statement {
+		for _, action := range statement.Action {
+			key := action
+			if service := statement.Condition["StringEquals"]["iam:PassedToService"]; service != "" {
+				key += " " + service
+			}
+			out[key] = append(out[key], statement.Resource...)
+		}
+	}
+	for _, resources := range out {
+		sort.Strings(resources)
+	}
+	return out
+}
+
+func TestGenerateIntegrations(t *testing.T) {
+	syncRule := func(rule string) []string {
+		return []string{arn("events", "rule/"+rule)}
+	}
+	tests := []struct {
+		name       string
+		definition string
+		want       map[string][]string
+	}{
+		{
+			name:       "Lambda function ARN",
+			definition: taskDefinition(arn("lambda", "function:fn"), ""),
+			want: map[string][]string{
+				"lambda:InvokeFunction": {arn("lambda", "function:fn"), arn("lambda", "function:fn:*")},
+			},
+		},
+		{
+			name:       "lambda:invoke with a function name",
+			definition: taskDefinition("arn:aws:states:::lambda:invoke", `{"FunctionName": "fn"}`),
+			want: map[string][]string{
+				"lambda:InvokeFunction": {arn("lambda", "function:fn"), arn("lambda", "function:fn:*")},
+			},
+		},
+		{
+			name: "lambda:invoke with a function ARN",
+			definition: taskDefinition("arn:aws:states:::lambda:invoke",
+				`{"FunctionName": "arn:aws:lambda:us-west-2:210987654321:function:other"}`),
+			want: map[string][]string{
+				"lambda:InvokeFunction": {
+					"arn:aws:lambda:us-west-2:210987654321:function:other",
+					"arn:aws:lambda:us-west-2:210987654321:function:other:*",
+				},
+			},
+		},
+		{
+			name:       "lambda:invoke with a JSONPath function name",
+			definition: taskDefinition("arn:aws:states:::lambda:invoke", `{"FunctionName.$": "$.fn"}`),
+			want: map[string][]string{
+				"lambda:InvokeFunction": {arn("lambda", "function:*"), arn("lambda", "function:*:*")},
+			},
+		},
+		{
+			name:       "lambda:invoke with a JSONata function name",
+			definition: taskDefinition("arn:aws:states:::lambda:invoke", `{"FunctionName": "{% $states.input.fn %}"}`),
+			want: map[string][]string{
+				"lambda:InvokeFunction": {arn("lambda", "function:*"), arn("lambda", "function:*:*")},
+			},
+		},
+		{
+			name:       "lambda:invoke.waitForTaskToken",
+			definition: taskDefinition("arn:aws:states:::lambda:invoke.waitForTaskToken", `{"FunctionName": "fn"}`),
+			want: map[string][]string{
+				"lambda:InvokeFunction": {arn("lambda", "function:fn"), arn("lambda", "function:fn:*")},
+			},
+		},
+		{
+			name: "sqs:sendMessage with a queue URL",
+			definition: taskDefinition("arn:aws:states:::sqs:sendMessage",
+				`{"QueueUrl": "https://sqs.us-west-2.amazonaws.com/210987654321/queue"}`),
+			want: map[string][]string{
+				"sqs:SendMessage": {"arn:aws:sqs:us-west-2:210987654321:queue"},
+			},
+		},
+		{
+			name:       "sqs:sendMessage with a JSONPath queue URL",
+			definition: taskDefinition("arn:aws:states:::sqs:sendMessage", `{"QueueUrl.$": "$.queue"}`),
+			want: map[string][]string{
+				"sqs:SendMessage": {arn("sqs", "*")},
+			},
+		},
+		{
+			name:       "sns:publish with a topic ARN",
+			definition: taskDefinition("arn:aws:states:::sns:publish", `{"TopicArn": "`+arn("sns", "topic")+`"}`),
+			want: map[string][]string{
+				"sns:Publish": {arn("sns", "topic")},
+			},
+		},
+		{
+			name:       "sns:publish with a target ARN",
+			definition: taskDefinition("arn:aws:states:::sns:publish", `{"TargetArn": "`+arn("sns", "endpoint")+`"}`),
+			want: map[string][]string{
+				"sns:Publish": {arn("sns", "endpoint")},
+			},
+		},
+		{
+			name:       "sns:publish with a JSONPath topic ARN",
+			definition: taskDefinition("arn:aws:states:::sns:publish", `{"TopicArn.$": "$.topic"}`),
+			want: map[string][]string{
+				"sns:Publish": {arn("sns", "*")},
+			},
+		},
+		{
+			name:       "dynamodb:putItem with a table name",
+			definition: taskDefinition("arn:aws:states:::dynamodb:putItem", `{"TableName": "table"}`),
+			want: map[string][]string{
+				"dynamodb:PutItem": {arn("dynamodb", "table/table")},
+			},
+		},
+		{
+			name:       "dynamodb:getItem with a JSONPath table name",
+			definition: taskDefinition("arn:aws:states:::dynamodb:getItem", `{"TableName.$": "$.table"}`),
+			want: map[string][]string{
+				"dynamodb:GetItem": {arn("dynamodb", "table/*")},
+			},
+		},
+		{
+			name: "states:startExecution",
+			definition: taskDefinition("arn:aws:states:::states:startExecution",
+				`{"StateMachineArn": "`+arn("states", "stateMachine:child")+`"}`),
+			want: map[string][]string{
+				"states:StartExecution": {arn("states", "stateMachine:child")},
+			},
+		},
+		{
+			name: "states:startExecution.sync",
+			definition: taskDefinition("arn:aws:states:::states:startExecution.sync",
+				`{"StateMachineArn": "`+arn("states", "stateMachine:child")+`"}`),
+			want: map[string][]string{
+				"states:StartExecution":    {arn("states", "stateMachine:child")},
+				"states:DescribeExecution": {arn("states", "execution:child:*")},
+				"states:StopExecution":     {arn("states", "execution:child:*")},
+				"events:DescribeRule":      syncRule("StepFunctionsGetEventsForStepFunctionsExecutionRule"),
+				"events:PutRule":           syncRule("StepFunctionsGetEventsForStepFunctionsExecutionRule"),
+				"events:PutTargets":        syncRule("StepFunctionsGetEventsForStepFunctionsExecutionRule"),
+			},
+		},
+		{
+			name: "states:startExecution.sync:2 with a version ARN",
+			definition: taskDefinition("arn:aws:states:::states:startExecution.sync:2",
+				`{"StateMachineArn": "`+arn("states", "stateMachine:child:3")+`"}`),
+			want: map[string][]string{
+				"states:StartExecution":    {arn("states", "stateMachine:child:*")},
+				"states:DescribeExecution": {arn("states", "execution:child:*")},
+				"states:StopExecution":     {arn("states", "execution:child:*")},
+				"events:DescribeRule":      syncRule("StepFunctionsGetEventsForStepFunctionsExecutionRule"),
+				"events:PutRule":           syncRule("StepFunctionsGetEventsForStepFunctionsExecutionRule"),
+				"events:PutTargets":        syncRule("StepFunctionsGetEventsForStepFunctionsExecutionRule"),
+			},
+		},
+		{
+			name:       "states:startExecution.sync with a JSONPath state machine ARN",
+			definition: taskDefinition("arn:aws:states:::states:startExecution.sync", `{"StateMachineArn.$": "$.arn"}`),
+			want: map[string][]string{
+				"states:StartExecution":    {arn("states", "stateMachine:*")},
+				"states:DescribeExecution": {arn("states", "execution:*")},
+				"states:StopExecution":     {arn("states", "execution:*")},
+				"events:DescribeRule":      syncRule("StepFunctionsGetEventsForStepFunctionsExecutionRule"),
+				"events:PutRule":           syncRule("StepFunctionsGetEventsForStepFunctionsExecutionRule"),
+				"events:PutTargets":        syncRule("StepFunctionsGetEventsForStepFunctionsExecutionRule"),
+			},
+		},
+		{
+			name:       "ecs:runTask",
+			definition: taskDefinition("arn:aws:states:::ecs:runTask", `{"TaskDefinition": "family", "Cluster": "cluster"}`),
+			want: map[string][]string{
+				"ecs:RunTask":                          {arn("ecs", "task-definition/family:*")},
+				"iam:PassRole ecs-tasks.amazonaws.com": {"arn:aws:iam::123456789012:role/*"},
+			},
+		},
+		{
+			name: "ecs:runTask.sync",
+			definition: taskDefinition("arn:aws:states:::ecs:runTask.sync", `{
+				"TaskDefinition": "family:3",
+				"Cluster": "cluster",
+				"Overrides": {
+					"TaskRoleArn": "arn:aws:iam::123456789012:role/task",
+					"ExecutionRoleArn": "arn:aws:iam::123456789012:role/execution"}}`),
+			want: map[string][]string{
+				"ecs:RunTask":         {arn("ecs", "task-definition/family:3")},
+				"ecs:DescribeTasks":   {arn("ecs", "task/cluster/*")},
+				"ecs:StopTask":        {arn("ecs", "task/cluster/*")},
+				"events:DescribeRule": syncRule("StepFunctionsGetEventsForECSTaskRule"),
+				"events:PutRule":      syncRule("StepFunctionsGetEventsForECSTaskRule"),
+				"events:PutTargets":   syncRule("StepFunctionsGetEventsForECSTaskRule"),
+				"iam:PassRole ecs-tasks.amazonaws.com": {
+					"arn:aws:iam::123456789012:role/execution",
+					"arn:aws:iam::123456789012:role/task",
+				},
+			},
+		},
+		{
+			name: "ecs:runTask.waitForTaskToken with a task definition ARN",
+			definition: taskDefinition("arn:aws:states:::ecs:runTask.waitForTaskToken",
+				`{"TaskDefinition": "`+arn("ecs", "task-definition/family")+`", "Cluster.$": "$.cluster"}`),
+			want: map[string][]string{
+				"ecs:RunTask":                          {arn("ecs", "task-definition/family:*")},
+				"iam:PassRole ecs-tasks.amazonaws.com": {"arn:aws:iam::123456789012:role/*"},
+			},
+		},
+		{
+			name: "batch:submitJob.sync",
+			definition: taskDefinition("arn:aws:states:::batch:submitJob.sync",
+				`{"JobName": "job", "JobDefinition": "definition", "JobQueue": "queue"}`),
+			want: map[string][]string{
+				"batch:SubmitJob":     {arn("batch", "job-definition/definition:*"), arn("batch", "job-queue/queue")},
+				"batch:DescribeJobs":  {"*"},
+				"batch:TerminateJob":  {"*"},
+				"events:DescribeRule": syncRule("StepFunctionsGetEventsForBatchJobsRule"),
+				"events:PutRule":      syncRule("StepFunctionsGetEventsForBatchJobsRule"),
+				"events:PutTargets":   syncRule("StepFunctionsGetEventsForBatchJobsRule"),
+			},
+		},
+		{
+			name: "batch:submitJob with JSONPath parameters",
+			definition: taskDefinition("arn:aws:states:::batch:submitJob",
+				`{"JobName": "job", "JobDefinition.$": "$.definition", "JobQueue.$": "$.queue"}`),
+			want: map[string][]string{
+				"batch:SubmitJob": {arn("batch", "job-definition/*"), arn("batch", "job-queue/*")},
+			},
+		},
+		{
+			name:       "glue:startJobRun.sync",
+			definition: taskDefinition("arn:aws:states:::glue:startJobRun.sync", `{"JobName": "job"}`),
+			want: map[string][]string{
+				"glue:StartJobRun":     {arn("glue", "job/job")},
+				"glue:BatchStopJobRun": {arn("glue", "job/job")},
+				"glue:GetJobRun":       {arn("glue", "job/job")},
+				"glue:GetJobRuns":      {arn("glue", "job/job")},
+			},
+		},
+		{
+			name:       "codebuild:startBuild.sync",
+			definition: taskDefinition("arn:aws:states:::codebuild:startBuild.sync", `{"ProjectName": "project"}`),
+			want: map[string][]string{
+				"codebuild:StartBuild":     {arn("codebuild", "project/project")},
+				"codebuild:BatchGetBuilds": {arn("codebuild", "project/project")},
+				"codebuild:StopBuild":      {arn("codebuild", "project/project")},
+				"events:DescribeRule":      syncRule("StepFunctionsGetEventsForCodeBuildStartBuildRule"),
+				"events:PutRule":           syncRule("StepFunctionsGetEventsForCodeBuildStartBuildRule"),
+				"events:PutTargets":        syncRule("StepFunctionsGetEventsForCodeBuildStartBuildRule"),
+			},
+		},
+		{
+			name: "sagemaker:createTrainingJob.sync",
+			definition: taskDefinition("arn:aws:states:::sagemaker:createTrainingJob.sync",
+				`{"TrainingJobName": "job", "RoleArn": "arn:aws:iam::123456789012:role/sagemaker"}`),
+			want: map[string][]string{
+				"sagemaker:CreateTrainingJob":          {"*"},
+				"events:DescribeRule":                  syncRule("StepFunctions*"),
+				"events:PutRule":                       syncRule("StepFunctions*"),
+				"events:PutTargets":                    syncRule("StepFunctions*"),
+				"iam:PassRole sagemaker.amazonaws.com": {"arn:aws:iam::123456789012:role/sagemaker"},
+			},
+		},
+		{
+			name:       "aws-sdk with an S3 object",
+			definition: taskDefinition("arn:aws:states:::aws-sdk:s3:getObject", `{"Bucket": "bucket", "Key": "key"}`),
+			want: map[string][]string{
+				"s3:GetObject": {"arn:aws:s3:::bucket/key"},
+			},
+		},
+		{
+			name:       "aws-sdk with an ARN parameter",
+			definition: taskDefinition("arn:aws:states:::aws-sdk:secretsmanager:getSecretValue", `{"SecretId": "`+arn("secretsmanager", "secret:name")+`"}`),
+			want: map[string][]string{
+				"secretsmanager:GetSecretValue": {arn("secretsmanager", "secret:name")},
+			},
+		},
+		{
+			name:       "aws-sdk without ARN parameters",
+			definition: taskDefinition("arn:aws:states:::aws-sdk:secretsmanager:getSecretValue", `{"SecretId": "name"}`),
+			want: map[string][]string{
+				"secretsmanager:GetSecretValue": {"*"},
+			},
+		},
+		{
+			name:       "aws-sdk with a different IAM prefix",
+			definition: taskDefinition("arn:aws:states:::aws-sdk:eventbridge:putEvents", `{"Entries": []}`),
+			want: map[string][]string{
+				"events:PutEvents": {"*"},
+			},
+		},
+		{
+			name:       "activity",
+			definition: taskDefinition(arn("states", "activity:worker"), ""),
+			want:       map[string][]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			permissions, err := Generate(tt.definition, testEnv)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			for _, resources := range tt.want {
+				sort.Strings(resources)
+			}
+			if got := flatten(permissions.Document()); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Generate() permissions:\n got %v\nwant %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateMap(t *testing.T) {
+	definition := `{"StartAt": "M", "States": {"M": {"Type": "Map", "End": true,
+		"ItemReader": {
+			"Resource": "arn:aws:states:::s3:listObjectsV2",
+			"Parameters": {"Bucket": "input", "Prefix": "items/"}},
+		"ResultWriter": {
+			"Resource": "arn:aws:states:::s3:putObject",
+			"Parameters": {"Bucket": "output", "Prefix": "results/"}},
+		"ItemProcessor": {
+			"ProcessorConfig": {"Mode": "DISTRIBUTED", "ExecutionType": "STANDARD"},
+			"StartAt": "I",
+			"States": {"I": {"Type": "Task", "End": true,
+				"Resource": "arn:aws:states:::lambda:invoke",
+				"Arguments": {"FunctionName": "fn"}}}}}}}`
+	want := map[string][]string{
+		"s3:ListBucket":            {"arn:aws:s3:::input"},
+		"s3:PutObject":             {"arn:aws:s3:::output/results/*"},
+		"lambda:InvokeFunction":    {arn("lambda", "function:fn"), arn("lambda", "function:fn:*")},
+		"states:StartExecution":    {testEnv.StateMachineARN},
+		"states:DescribeExecution": {arn("states", "execution:parent/*")},
+		"states:StopExecution":     {arn("states", "execution:parent/*")},
+	}
+	permissions, err := Generate(definition, testEnv)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if got := flatten(permissions.Document()); !reflect.DeepEqual(got, want) {
+		t.Errorf("Generate() permissions:\n got %v\nwant %v", got, want)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition string
+		wantErr    string
+	}{
+		{
+			name:       "invalid JSON",
+			definition: `{`,
+			wantErr:    "not a valid JSON object",
+		},
+		{
+			name:       "States is not an object",
+			definition: `{"StartAt": "T", "States": []}`,
+			wantErr:    "/States must be an object",
+		},
+		{
+			name:       "JSONPath Resource",
+			definition: taskDefinition("$.resource", ""),
+			wantErr:    `/States/T: cannot determine the permissions of Resource "$.resource"`,
+		},
+		{
+			name:       "unsupported service",
+			definition: taskDefinition(arn("ec2", "instance/i-1"), ""),
+			wantErr:    "cannot determine the permissions",
+		},
+		{
+			name:       "unknown integration",
+			definition: taskDefinition("arn:aws:states:::unknown", ""),
+			wantErr:    `unknown service integration "unknown"`,
+		},
+		{
+			name: "invalid Parallel branch",
+			definition: `{"StartAt": "P", "States": {"P": {"Type": "Parallel", "End": true,
+				"Branches": [{"StartAt": "T", "States": {"T": {"Type": "Task", "End": true, "Resource": "x"}}}]}}}`,
+			wantErr: `/States/P/Branches/0/States/T: cannot determine the permissions of Resource "x"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Generate(tt.definition, testEnv)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Generate() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
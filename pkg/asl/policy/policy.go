@@ -0,0 +1,309 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package policy derives the IAM permissions a state machine execution role
+// needs from the Task states of an Amazon States Language definition.
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Environment identifies where the state machine runs. It is used to build
+// the ARNs of resources that the definition names without an ARN.
+type Environment struct {
+	Partition string
+	Region    string
+	AccountID string
+	// StateMachineARN is the ARN of the state machine itself, used by
+	// distributed Map states that start child executions.
+	StateMachineARN string
+}
+
+// Document is an IAM policy document.
+type Document struct {
+	Version   string      `json:"Version"`
+	Statement []Statement `json:"Statement"`
+}
+
+// Statement is an Allow statement of a Document.
+type Statement struct {
+	Effect    string                       `json:"Effect"`
+	Action    []string                     `json:"Action"`
+	Resource  []string                     `json:"Resource"`
+	Condition map[string]map[string]string `json:"Condition,omitempty"`
+}
+
+// String returns the JSON encoding of the document.
+func (d *Document) String() string {
+	b, _ := json.Marshal(d)
+	return string(b)
+}
+
+// Permissions accumulates the actions allowed on each resource, and the
+// roles that may be passed to each service.
+type Permissions struct {
+	actions  map[string]map[string]bool
+	passRole map[string]map[string]bool
+}
+
+// NewPermissions returns an empty set of permissions.
+func NewPermissions() *Permissions {
+	return &Permissions{
+		actions:  map[string]map[string]bool{},
+		passRole: map[string]map[string]bool{},
+	}
+}
+
+// Allow grants the supplied actions on the supplied resources.
+func (p *Permissions) Allow(actions []string, resources ...string) {
+	for _, action := range actions {
+		if p.actions[action] == nil {
+			p.actions[action] = map[string]bool{}
+		}
+		for _, resource := range resources {
+			p.actions[action][resource] = true
+		}
+	}
+}
+
+// AllowPassRole grants iam:PassRole on the supplied roles, only when they are
+// passed to the supplied service principal.
+func (p *Permissions) AllowPassRole(service string, roles ...string) {
+	if p.passRole[service] == nil {
+		p.passRole[service] = map[string]bool{}
+	}
+	for _, role := range roles {
+		p.passRole[service][role] = true
+	}
+}
+
+// Document returns a policy document with one statement per distinct set of
+// resources, so that the output is stable for a given set of permissions.
+// Actions allowed on "*" are not repeated on narrower resources.
+// iam:PassRole is granted in one statement per service, with an
+// iam:PassedToService condition.
+func (p *Permissions) Document() *Document {
+	byResources := map[string][]string{}
+	resourceSets := map[string][]string{}
+	for action, resources := range p.actions {
+		list := []string{}
+		if resources["*"] {
+			list = []string{"*"}
+		} else {
+			for resource := range resources {
+				list = append(list, resource)
+			}
+			sort.Strings(list)
+		}
+		key := strings.Join(list, "\n")
+		byResources[key] = append(byResources[key], action)
+		resourceSets[key] = list
+	}
+	keys := make([]string, 0, len(byResources))
+	for key := range byResources {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	doc := &Document{Version: "2012-10-17", Statement: []Statement{}}
+	for _, key := range keys {
+		actions := byResources[key]
+		sort.Strings(actions)
+		doc.Statement = append(doc.Statement, Statement{
+			Effect:   "Allow",
+			Action:   actions,
+			Resource: resourceSets[key],
+		})
+	}
+
+	services := make([]string, 0, len(p.passRole))
+	for service := range p.passRole {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+	for _, service := range services {
+		roles := make([]string, 0, len(p.passRole[service]))
+		for role := range p.passRole[service] {
+			roles = append(roles, role)
+		}
+		sort.Strings(roles)
+		doc.Statement = append(doc.Statement, Statement{
+			Effect:   "Allow",
+			Action:   []string{"iam:PassRole"},
+			Resource: roles,
+			Condition: map[string]map[string]string{
+				"StringEquals": {"iam:PassedToService": service},
+			},
+		})
+	}
+	return doc
+}
+
+// Generate returns the permissions needed by the Task states of the supplied
+// definition, including the states nested in Parallel branches and Map
+// processors. It returns an error if the permissions of a Task state cannot
+// be determined, for example when its Resource is computed at runtime.
+func Generate(definition string, env Environment) (*Permissions, error) {
+	var root map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(definition), &root); err != nil {
+		return nil, fmt.Errorf("definition is not a valid JSON object: %w", err)
+	}
+	g := &generator{env: env, permissions: NewPermissions()}
+	if err := g.scope("", root); err != nil {
+		return nil, err
+	}
+	return g.permissions, nil
+}
+
+// state holds the fields of a state that determine its permissions.
+type state struct {
+	Type          string                     `json:"Type"`
+	Resource      string                     `json:"Resource"`
+	Parameters    map[string]json.RawMessage `json:"Parameters"`
+	Arguments     map[string]json.RawMessage `json:"Arguments"`
+	Branches      []json.RawMessage          `json:"Branches"`
+	Iterator      json.RawMessage            `json:"Iterator"`
+	ItemProcessor json.RawMessage            `json:"ItemProcessor"`
+	ItemReader    *task                      `json:"ItemReader"`
+	ResultWriter  *task                      `json:"ResultWriter"`
+}
+
+// task is the Resource and parameters of an ItemReader or ResultWriter.
+type task struct {
+	Resource   string                     `json:"Resource"`
+	Parameters map[string]json.RawMessage `json:"Parameters"`
+	Arguments  map[string]json.RawMessage `json:"Arguments"`
+}
+
+type generator struct {
+	env         Environment
+	permissions *Permissions
+}
+
+func (g *generator) scope(location string, scope map[string]json.RawMessage) error {
+	var states map[string]json.RawMessage
+	if err := json.Unmarshal(scope["States"], &states); err != nil {
+		return fmt.Errorf("%s/States must be an object", location)
+	}
+	names := make([]string, 0, len(states))
+	for name := range states {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		stateLocation := location + "/States/" + name
+		var s state
+		if err := json.Unmarshal(states[name], &s); err != nil {
+			return fmt.Errorf("%s is not a valid state: %w", stateLocation, err)
+		}
+		if err := g.state(stateLocation, &s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *generator) state(location string, s *state) error {
+	switch s.Type {
+	case "Task":
+		return g.task(location, s.Resource, parameters(s.Parameters, s.Arguments))
+	case "Parallel":
+		for i, raw := range s.Branches {
+			if err := g.nested(fmt.Sprintf("%s/Branches/%d", location, i), raw); err != nil {
+				return err
+			}
+		}
+	case "Map":
+		processor := s.ItemProcessor
+		if len(processor) == 0 {
+			processor = s.Iterator
+		}
+		if len(processor) > 0 {
+			if err := g.nested(location+"/ItemProcessor", processor); err != nil {
+				return err
+			}
+			if isDistributed(processor) {
+				g.childExecutions()
+			}
+		}
+		if s.ItemReader != nil {
+			if err := g.task(location+"/ItemReader", s.ItemReader.Resource, parameters(s.ItemReader.Parameters, s.ItemReader.Arguments)); err != nil {
+				return err
+			}
+		}
+		if s.ResultWriter != nil {
+			if err := g.task(location+"/ResultWriter", s.ResultWriter.Resource, parameters(s.ResultWriter.Parameters, s.ResultWriter.Arguments)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (g *generator) nested(location string, raw json.RawMessage) error {
+	var scope map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &scope); err != nil {
+		return fmt.Errorf("%s must be an object: %w", location, err)
+	}
+	return g.scope(location, scope)
+}
+
+// isDistributed returns true if a Map processor runs child executions.
+func isDistributed(raw json.RawMessage) bool {
+	var processor struct {
+		ProcessorConfig struct {
+			Mode string `json:"Mode"`
+		} `json:"ProcessorConfig"`
+	}
+	_ = json.Unmarshal(raw, &processor)
+	return processor.ProcessorConfig.Mode == "DISTRIBUTED"
+}
+
+// parameters returns the JSONPath Parameters or the JSONata Arguments of a
+// state, whichever is set.
+func parameters(params, args map[string]json.RawMessage) map[string]json.RawMessage {
+	if params != nil {
+		return params
+	}
+	return args
+}
+
+// parameter returns the static string value of a parameter, following the
+// supplied path of nested objects. The second result is false if the
+// parameter is missing or computed at runtime, with a JSONPath "Name.$" key
+// or a JSONata "{% %}" expression.
+func parameter(params map[string]json.RawMessage, path ...string) (string, bool) {
+	for len(path) > 1 {
+		var nested map[string]json.RawMessage
+		if err := json.Unmarshal(params[path[0]], &nested); err != nil {
+			return "", false
+		}
+		params, path = nested, path[1:]
+	}
+	raw, ok := params[path[0]]
+	if !ok {
+		return "", false
+	}
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil || value == "" {
+		return "", false
+	}
+	if strings.HasPrefix(strings.TrimSpace(value), "{%") {
+		return "", false
+	}
+	return value, true
+}
@@ -0,0 +1,173 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package policy
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDocument(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(p *Permissions)
+		want  string
+	}{
+		{
+			name:  "no permissions",
+			build: func(p *Permissions) {},
+			want:  `{"Version":"2012-10-17","Statement":[]}`,
+		},
+		{
+			name: "actions grouped by resources",
+			build: func(p *Permissions) {
+				p.Allow([]string{"sqs:SendMessage"}, "arn:queue")
+				p.Allow([]string{"sns:Publish"}, "arn:topic")
+				p.Allow([]string{"lambda:InvokeFunction", "lambda:GetFunction"}, "arn:fn", "arn:fn:*")
+			},
+			want: `{"Version":"2012-10-17","Statement":[` +
+				`{"Effect":"Allow","Action":["lambda:GetFunction","lambda:InvokeFunction"],"Resource":["arn:fn","arn:fn:*"]},` +
+				`{"Effect":"Allow","Action":["sqs:SendMessage"],"Resource":["arn:queue"]},` +
+				`{"Effect":"Allow","Action":["sns:Publish"],"Resource":["arn:topic"]}]}`,
+		},
+		{
+			name: "wildcard resource replaces narrower resources",
+			build: func(p *Permissions) {
+				p.Allow([]string{"s3:GetObject"}, "arn:bucket/key")
+				p.Allow([]string{"s3:GetObject"}, "*")
+			},
+			want: `{"Version":"2012-10-17","Statement":[` +
+				`{"Effect":"Allow","Action":["s3:GetObject"],"Resource":["*"]}]}`,
+		},
+		{
+			name: "PassRole per service",
+			build: func(p *Permissions) {
+				p.AllowPassRole("sagemaker.amazonaws.com", "arn:role/b", "arn:role/a")
+				p.AllowPassRole("ecs-tasks.amazonaws.com", "arn:role/c")
+				p.AllowPassRole("sagemaker.amazonaws.com", "arn:role/a")
+			},
+			want: `{"Version":"2012-10-17","Statement":[` +
+				`{"Effect":"Allow","Action":["iam:PassRole"],"Resource":["arn:role/c"],` +
+				`"Condition":{"StringEquals":{"iam:PassedToService":"ecs-tasks.amazonaws.com"}}},` +
+				`{"Effect":"Allow","Action":["iam:PassRole"],"Resource":["arn:role/a","arn:role/b"],` +
+				`"Condition":{"StringEquals":{"iam:PassedToService":"sagemaker.amazonaws.com"}}}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPermissions()
+			tt.build(p)
+			if got := p.Document().String(); got != tt.want {
+				t.Errorf("Document() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateNestedScopes(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition string
+		want       map[string][]string
+	}{
+		{
+			name: "Parallel branches",
+			definition: `{"StartAt": "P", "States": {"P": {"Type": "Parallel", "End": true, "Branches": [
+				{"StartAt": "A", "States": {"A": {"Type": "Task", "End": true,
+					"Resource": "arn:aws:states:::sns:publish", "Parameters": {"TopicArn": "` + arn("sns", "a") + `"}}}},
+				{"StartAt": "B", "States": {"B": {"Type": "Task", "End": true,
+					"Resource": "arn:aws:states:::sns:publish", "Parameters": {"TopicArn": "` + arn("sns", "b") + `"}}}}]}}}`,
+			want: map[string][]string{
+				"sns:Publish": {arn("sns", "a"), arn("sns", "b")},
+			},
+		},
+		{
+			name: "inline Map with an Iterator",
+			definition: `{"StartAt": "M", "States": {"M": {"Type": "Map", "End": true, "Iterator": {
+				"StartAt": "A", "States": {"A": {"Type": "Task", "End": true,
+					"Resource": "arn:aws:states:::sqs:sendMessage.waitForTaskToken",
+					"Parameters": {"QueueUrl": "https://sqs.us-east-1.amazonaws.com/123456789012/queue"}}}}}}}`,
+			want: map[string][]string{
+				"sqs:SendMessage": {arn("sqs", "queue")},
+			},
+		},
+		{
+			name: "JSONata Arguments",
+			definition: `{"QueryLanguage": "JSONata", "StartAt": "A", "States": {"A": {"Type": "Task", "End": true,
+				"Resource": "arn:aws:states:::dynamodb:updateItem", "Arguments": {"TableName": "table"}}}}`,
+			want: map[string][]string{
+				"dynamodb:UpdateItem": {arn("dynamodb", "table/table")},
+			},
+		},
+		{
+			name: "states without permissions",
+			definition: `{"StartAt": "A", "States": {
+				"A": {"Type": "Pass", "Next": "B"},
+				"B": {"Type": "Wait", "Seconds": 1, "Next": "C"},
+				"C": {"Type": "Succeed"}}}`,
+			want: map[string][]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			permissions, err := Generate(tt.definition, testEnv)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			if got := flatten(permissions.Document()); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Generate() permissions:\n got %v\nwant %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParameter(t *testing.T) {
+	params := map[string]json.RawMessage{}
+	if err := json.Unmarshal([]byte(`{
+		"Name": "value",
+		"Empty": "",
+		"Number": 1,
+		"Path.$": "$.value",
+		"Expression": " {% $states.input.value %}",
+		"Nested": {"Name": "nested", "Deeper": {"Name": "deeper"}}
+	}`), &params); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		path   []string
+		want   string
+		wantOK bool
+	}{
+		{name: "static value", path: []string{"Name"}, want: "value", wantOK: true},
+		{name: "missing", path: []string{"Missing"}},
+		{name: "empty", path: []string{"Empty"}},
+		{name: "not a string", path: []string{"Number"}},
+		{name: "JSONPath", path: []string{"Path"}},
+		{name: "JSONata", path: []string{"Expression"}},
+		{name: "nested value", path: []string{"Nested", "Name"}, want: "nested", wantOK: true},
+		{name: "deeply nested value", path: []string{"Nested", "Deeper", "Name"}, want: "deeper", wantOK: true},
+		{name: "missing nested object", path: []string{"Missing", "Name"}},
+		{name: "not an object", path: []string{"Name", "Name"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parameter(params, tt.path...)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("parameter(%v) = %q, %v, want %q, %v", tt.path, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
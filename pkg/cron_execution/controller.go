@@ -0,0 +1,460 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package cron_execution implements the CronExecution controller. Unlike the
+// other resources of this controller, a CronExecution has no counterpart in
+// Step Functions: it only creates Execution objects on a schedule, the way a
+// CronJob creates Jobs, so it is reconciled by a plain controller-runtime
+// reconciler instead of the ACK runtime.
+package cron_execution
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcfg "github.com/aws-controllers-k8s/runtime/pkg/config"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/sfn/types"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlrt "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlrtlog "sigs.k8s.io/controller-runtime/pkg/log"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+	commonutil "github.com/aws-controllers-k8s/sfn-controller/pkg/util"
+)
+
+// +kubebuilder:rbac:groups=sfn.services.k8s.aws,resources=cronexecutions,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=sfn.services.k8s.aws,resources=cronexecutions/status,verbs=get;update;patch
+
+const (
+	// ownerIndexKey indexes Executions by the name of the CronExecution
+	// controlling them.
+	ownerIndexKey = "metadata.ownerReferences.cronExecution"
+
+	concurrencyPolicyAllow   = "Allow"
+	concurrencyPolicyForbid  = "Forbid"
+	concurrencyPolicyReplace = "Replace"
+
+	defaultSuccessfulExecutionsHistoryLimit = 3
+	defaultFailedExecutionsHistoryLimit     = 1
+
+	// referencesRequeuePeriod is how long to wait before resolving the state
+	// machine reference of the execution template again, like the ACK
+	// runtime does for resources that are not synced.
+	referencesRequeuePeriod = 30 * time.Second
+)
+
+// reconciler starts the Executions of CronExecutions on their schedule and
+// cleans up the finished ones.
+type reconciler struct {
+	kc        client.Client
+	apiReader client.Reader
+	cfg       ackcfg.Config
+	now       func() time.Time
+}
+
+// SetupController registers the CronExecution controller with the supplied
+// manager. The controller is notified of changes to the Executions it owns so
+// that Status.Active and the execution history are kept up to date as
+// executions complete.
+func SetupController(mgr ctrlrt.Manager, cfg ackcfg.Config) error {
+	err := mgr.GetFieldIndexer().IndexField(
+		context.Background(),
+		&svcapitypes.Execution{},
+		ownerIndexKey,
+		func(obj client.Object) []string {
+			owner := metav1.GetControllerOf(obj)
+			if owner == nil ||
+				owner.APIVersion != svcapitypes.GroupVersion.String() ||
+				owner.Kind != "CronExecution" {
+				return nil
+			}
+			return []string{owner.Name}
+		},
+	)
+	if err != nil {
+		return err
+	}
+	r := &reconciler{
+		kc:        mgr.GetClient(),
+		apiReader: mgr.GetAPIReader(),
+		cfg:       cfg,
+		now:       time.Now,
+	}
+	return ctrlrt.NewControllerManagedBy(
+		mgr,
+	).Named(
+		"cronexecution",
+	).For(
+		&svcapitypes.CronExecution{},
+	).Owns(
+		&svcapitypes.Execution{},
+	).Complete(r)
+}
+
+// Reconcile updates the status of the CronExecution from its Executions,
+// prunes the execution history and creates the Execution for the most recent
+// scheduled time if it was not created yet.
+func (r *reconciler) Reconcile(
+	ctx context.Context,
+	req ctrlrt.Request,
+) (ctrlrt.Result, error) {
+	log := ctrlrtlog.FromContext(ctx)
+
+	ko := &svcapitypes.CronExecution{}
+	if err := r.kc.Get(ctx, req.NamespacedName, ko); err != nil {
+		return ctrlrt.Result{}, client.IgnoreNotFound(err)
+	}
+	// Owned Executions are garbage collected with the CronExecution.
+	if !ko.DeletionTimestamp.IsZero() {
+		return ctrlrt.Result{}, nil
+	}
+
+	executions := &svcapitypes.ExecutionList{}
+	err := r.kc.List(
+		ctx, executions,
+		client.InNamespace(ko.Namespace),
+		client.MatchingFields{ownerIndexKey: ko.Name},
+	)
+	if err != nil {
+		return ctrlrt.Result{}, err
+	}
+	active, succeeded, failed := classifyExecutions(executions.Items)
+
+	orig := ko.DeepCopy()
+	ko.Status.Active = executionNames(active)
+	for _, execution := range succeeded {
+		if stopDate := execution.Status.StopDate; stopDate != nil &&
+			(ko.Status.LastSuccessfulTime == nil || ko.Status.LastSuccessfulTime.Before(stopDate)) {
+			ko.Status.LastSuccessfulTime = stopDate.DeepCopy()
+		}
+	}
+
+	if err := r.pruneHistory(ctx, succeeded, historyLimit(
+		ko.Spec.SuccessfulExecutionsHistoryLimit, defaultSuccessfulExecutionsHistoryLimit,
+	)); err != nil {
+		return ctrlrt.Result{}, err
+	}
+	if err := r.pruneHistory(ctx, failed, historyLimit(
+		ko.Spec.FailedExecutionsHistoryLimit, defaultFailedExecutionsHistoryLimit,
+	)); err != nil {
+		return ctrlrt.Result{}, err
+	}
+
+	result, err := r.reconcileSchedule(ctx, ko, active)
+	if patchErr := r.kc.Status().Patch(ctx, ko, client.MergeFrom(orig)); patchErr != nil {
+		log.Error(patchErr, "unable to update CronExecution status")
+		if err == nil {
+			err = patchErr
+		}
+	}
+	return result, err
+}
+
+// reconcileSchedule creates the Execution for the most recent scheduled time
+// unless it was already created, it was missed by more than the starting
+// deadline or the concurrency policy forbids it, and returns when to
+// reconcile again for the next scheduled time.
+func (r *reconciler) reconcileSchedule(
+	ctx context.Context,
+	ko *svcapitypes.CronExecution,
+	active []*svcapitypes.Execution,
+) (ctrlrt.Result, error) {
+	if ko.Spec.Suspend != nil && *ko.Spec.Suspend {
+		ko.Status.NextScheduleTime = nil
+		setCondition(ko, ackv1alpha1.ConditionTypeResourceSynced, corev1.ConditionTrue, "Suspended",
+			"executions are not started while the CronExecution is suspended")
+		return ctrlrt.Result{}, nil
+	}
+
+	sched, loc, err := parseSchedule(ko.Spec.Schedule, ko.Spec.TimeZone)
+	if err != nil {
+		setCondition(ko, ackv1alpha1.ConditionTypeTerminal, corev1.ConditionTrue, "InvalidSchedule", err.Error())
+		setCondition(ko, ackv1alpha1.ConditionTypeResourceSynced, corev1.ConditionFalse, "InvalidSchedule", err.Error())
+		return ctrlrt.Result{}, nil
+	}
+	removeCondition(ko, ackv1alpha1.ConditionTypeTerminal)
+
+	now := r.now()
+	scheduledTime, missed := mostRecentScheduleTime(sched, loc, earliestScheduleTime(ko, now), now)
+	next := sched.Next(now.In(loc))
+	ko.Status.NextScheduleTime = &metav1.Time{Time: next}
+	result := ctrlrt.Result{RequeueAfter: next.Sub(now)}
+	if missed > tooManyMissedScheduleTimes {
+		commonutil.RecordEvent(ko, corev1.EventTypeWarning, "TooManyMissedTimes",
+			"%d scheduled times were missed, set startingDeadlineSeconds to skip them", missed)
+	}
+
+	// The Execution resolves the state machine reference itself, it is only
+	// checked here so that no Execution is created while it cannot start.
+	if err := r.checkStateMachineReference(ctx, ko); err != nil {
+		setCondition(ko, ackv1alpha1.ConditionTypeReferencesResolved, corev1.ConditionFalse, "", err.Error())
+		setCondition(ko, ackv1alpha1.ConditionTypeResourceSynced, corev1.ConditionFalse, "", err.Error())
+		return ctrlrt.Result{RequeueAfter: referencesRequeuePeriod}, nil
+	}
+	setCondition(ko, ackv1alpha1.ConditionTypeReferencesResolved, corev1.ConditionTrue, "", "")
+	setCondition(ko, ackv1alpha1.ConditionTypeResourceSynced, corev1.ConditionTrue, "", "")
+
+	if scheduledTime == nil {
+		return result, nil
+	}
+
+	switch concurrencyPolicy(ko) {
+	case concurrencyPolicyForbid:
+		// The scheduled time is not recorded so that the execution is
+		// started once the active ones complete, if still within the
+		// starting deadline.
+		if len(active) > 0 {
+			commonutil.RecordEvent(ko, corev1.EventTypeNormal, "ExecutionAlreadyActive",
+				"Not starting the execution scheduled at %s because an execution is still running",
+				scheduledTime.Format(time.RFC3339))
+			return result, nil
+		}
+	case concurrencyPolicyReplace:
+		for _, execution := range active {
+			// Deleting a running Execution stops it.
+			if err := r.kc.Delete(ctx, execution, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+				return ctrlrt.Result{}, err
+			}
+			commonutil.RecordEvent(ko, corev1.EventTypeNormal, "SuccessfulDelete",
+				"Deleted running Execution %s", execution.Name)
+		}
+		ko.Status.Active = nil
+	}
+
+	execution := newExecution(ko, *scheduledTime)
+	// The Execution already exists when a previous reconcile created it but
+	// failed to record it, Status.Active already lists it then.
+	err = r.kc.Create(ctx, execution)
+	switch {
+	case apierrors.IsAlreadyExists(err):
+	case err != nil:
+		commonutil.RecordEvent(ko, corev1.EventTypeWarning, "FailedCreate",
+			"Error creating Execution %s: %v", execution.Name, err)
+		return ctrlrt.Result{}, err
+	default:
+		commonutil.RecordEvent(ko, corev1.EventTypeNormal, "SuccessfulCreate",
+			"Created Execution %s", execution.Name)
+		ko.Status.Active = append(ko.Status.Active, &execution.Name)
+	}
+	ko.Status.LastScheduleTime = &metav1.Time{Time: *scheduledTime}
+	return result, nil
+}
+
+// newExecution returns the Execution created for the supplied scheduled time.
+// Its name is derived from the scheduled time so that a reconcile retried
+// after a failed status update does not start a second execution.
+func newExecution(
+	ko *svcapitypes.CronExecution,
+	scheduledTime time.Time,
+) *svcapitypes.Execution {
+	template := ko.Spec.ExecutionTemplate
+	execution := &svcapitypes.Execution{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("%s-%d", ko.Name, scheduledTime.Unix()/60),
+			Namespace:   ko.Namespace,
+			Labels:      copyStringMap(template.Labels),
+			Annotations: copyStringMap(template.Annotations),
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(ko, svcapitypes.GroupVersion.WithKind("CronExecution")),
+			},
+		},
+	}
+	if spec := template.Spec; spec != nil {
+		spec = spec.DeepCopy()
+		execution.Spec = svcapitypes.ExecutionSpec{
+			Input:                  spec.Input,
+			InputFrom:              spec.InputFrom,
+			Mode:                   spec.Mode,
+			StateMachineARN:        spec.StateMachineARN,
+			StateMachineAliasRef:   spec.StateMachineAliasRef,
+			StateMachineRef:        spec.StateMachineRef,
+			StateMachineVersionRef: spec.StateMachineVersionRef,
+			TraceHeader:            spec.TraceHeader,
+		}
+	}
+	return execution
+}
+
+// pruneHistory deletes the oldest of the supplied finished Executions so that
+// at most limit of them are kept.
+func (r *reconciler) pruneHistory(
+	ctx context.Context,
+	executions []*svcapitypes.Execution,
+	limit int,
+) error {
+	if len(executions) <= limit {
+		return nil
+	}
+	sort.Slice(executions, func(i, j int) bool {
+		return finishTime(executions[i]).Before(finishTime(executions[j]))
+	})
+	for _, execution := range executions[:len(executions)-limit] {
+		if err := r.kc.Delete(ctx, execution, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// classifyExecutions splits the supplied Executions into the ones that are
+// still running, the ones that succeeded and the ones that failed, timed out,
+// were aborted or could not be started. Executions being deleted are
+// ignored.
+func classifyExecutions(
+	items []svcapitypes.Execution,
+) (active, succeeded, failed []*svcapitypes.Execution) {
+	for i := range items {
+		execution := &items[i]
+		if !execution.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if executionTerminal(execution) {
+			failed = append(failed, execution)
+			continue
+		}
+		status := ""
+		if execution.Status.Status != nil {
+			status = *execution.Status.Status
+		}
+		switch svcsdktypes.ExecutionStatus(status) {
+		case svcsdktypes.ExecutionStatusSucceeded:
+			succeeded = append(succeeded, execution)
+		case svcsdktypes.ExecutionStatusFailed,
+			svcsdktypes.ExecutionStatusTimedOut,
+			svcsdktypes.ExecutionStatusAborted:
+			failed = append(failed, execution)
+		default:
+			active = append(active, execution)
+		}
+	}
+	return active, succeeded, failed
+}
+
+// executionTerminal returns true if the supplied Execution has a Terminal
+// condition, e.g. because the state machine rejected its input.
+func executionTerminal(execution *svcapitypes.Execution) bool {
+	for _, cond := range execution.Status.Conditions {
+		if cond.Type == ackv1alpha1.ConditionTypeTerminal &&
+			cond.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// finishTime returns when the supplied Execution stopped, or when it was
+// created if Step Functions did not report it.
+func finishTime(execution *svcapitypes.Execution) time.Time {
+	if execution.Status.StopDate != nil {
+		return execution.Status.StopDate.Time
+	}
+	return execution.CreationTimestamp.Time
+}
+
+// executionNames returns the names of the supplied Executions.
+func executionNames(executions []*svcapitypes.Execution) []*string {
+	if len(executions) == 0 {
+		return nil
+	}
+	names := make([]*string, 0, len(executions))
+	for _, execution := range executions {
+		name := execution.Name
+		names = append(names, &name)
+	}
+	return names
+}
+
+// concurrencyPolicy returns the concurrency policy of the supplied
+// CronExecution, Allow by default.
+func concurrencyPolicy(ko *svcapitypes.CronExecution) string {
+	if ko.Spec.ConcurrencyPolicy == nil {
+		return concurrencyPolicyAllow
+	}
+	return *ko.Spec.ConcurrencyPolicy
+}
+
+// historyLimit returns the supplied history limit, or defaultLimit if it is
+// not set.
+func historyLimit(limit *int64, defaultLimit int) int {
+	if limit == nil {
+		return defaultLimit
+	}
+	return int(*limit)
+}
+
+// copyStringMap returns a copy of the supplied map.
+func copyStringMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+// setCondition sets the condition of the supplied type, updating its last
+// transition time when its status changes.
+func setCondition(
+	ko *svcapitypes.CronExecution,
+	condType ackv1alpha1.ConditionType,
+	status corev1.ConditionStatus,
+	reason string,
+	message string,
+) {
+	var cond *ackv1alpha1.Condition
+	for _, c := range ko.Status.Conditions {
+		if c.Type == condType {
+			cond = c
+			break
+		}
+	}
+	if cond == nil {
+		cond = &ackv1alpha1.Condition{Type: condType}
+		ko.Status.Conditions = append(ko.Status.Conditions, cond)
+	}
+	if cond.Status != status {
+		now := metav1.Now()
+		cond.LastTransitionTime = &now
+	}
+	cond.Status = status
+	cond.Reason = nil
+	if reason != "" {
+		cond.Reason = &reason
+	}
+	cond.Message = nil
+	if message != "" {
+		cond.Message = &message
+	}
+}
+
+// removeCondition removes the condition of the supplied type.
+func removeCondition(
+	ko *svcapitypes.CronExecution,
+	condType ackv1alpha1.ConditionType,
+) {
+	conditions := ko.Status.Conditions[:0]
+	for _, c := range ko.Status.Conditions {
+		if c.Type != condType {
+			conditions = append(conditions, c)
+		}
+	}
+	ko.Status.Conditions = conditions
+}
@@ -0,0 +1,71 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cron_execution
+
+import (
+	"context"
+	"fmt"
+
+	ackrt "github.com/aws-controllers-k8s/runtime/pkg/runtime"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+	commonutil "github.com/aws-controllers-k8s/sfn-controller/pkg/util"
+)
+
+// checkStateMachineReference makes sure the StateMachine, StateMachineAlias
+// or StateMachineVersion referenced from the execution template exists, is
+// synced and has an ARN, the way the Executions created from the template
+// resolve it. It returns nil when the template specifies the state machine
+// ARN directly.
+func (r *reconciler) checkStateMachineReference(
+	ctx context.Context,
+	ko *svcapitypes.CronExecution,
+) error {
+	spec := ko.Spec.ExecutionTemplate.Spec
+	if spec == nil {
+		return fmt.Errorf("provided execution template is empty: ExecutionTemplate.Spec")
+	}
+	kind, field, ref := "StateMachine", "StateMachineRef", spec.StateMachineRef
+	switch {
+	case spec.StateMachineAliasRef != nil:
+		kind, field, ref = "StateMachineAlias", "StateMachineAliasRef", spec.StateMachineAliasRef
+	case spec.StateMachineVersionRef != nil:
+		kind, field, ref = "StateMachineVersion", "StateMachineVersionRef", spec.StateMachineVersionRef
+	}
+	if ref == nil || ref.From == nil {
+		return nil
+	}
+	arr := ref.From
+	if arr.Name == nil || *arr.Name == "" {
+		return fmt.Errorf("provided resource reference is nil or empty: ExecutionTemplate.Spec.%s", field)
+	}
+	namespace, err := ackrt.ResolveCrossNamespaceReference(
+		ctx,
+		r.cfg.EnableCrossNamespace,
+		&ko.Status.Conditions,
+		ackrt.CrossNamespaceRefKindResource,
+		ko.ObjectMeta.GetNamespace(),
+		arr.Namespace,
+		*arr.Name,
+	)
+	if err != nil {
+		return err
+	}
+	_, err = commonutil.GetReferencedResourceARN(
+		ctx, r.apiReader,
+		svcapitypes.GroupVersion.WithKind(kind),
+		*arr.Name, namespace,
+	)
+	return err
+}
@@ -0,0 +1,100 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cron_execution
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+)
+
+// tooManyMissedScheduleTimes is the number of missed scheduled times above
+// which a warning event is recorded, usually because the controller was down
+// and the CronExecution has no starting deadline.
+const tooManyMissedScheduleTimes = 100
+
+// parseSchedule parses the supplied standard cron schedule and loads the time
+// zone it is interpreted in.
+func parseSchedule(
+	schedule *string,
+	timeZone *string,
+) (cron.Schedule, *time.Location, error) {
+	if schedule == nil {
+		return nil, nil, fmt.Errorf("schedule is required")
+	}
+	// The cron library supports specifying the time zone in the schedule,
+	// which would silently take precedence over Spec.TimeZone.
+	if strings.Contains(*schedule, "TZ=") {
+		return nil, nil, fmt.Errorf(
+			"invalid schedule %q: specify the time zone with timeZone instead of TZ or CRON_TZ",
+			*schedule,
+		)
+	}
+	sched, err := cron.ParseStandard(*schedule)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid schedule %q: %v", *schedule, err)
+	}
+	loc := time.UTC
+	if timeZone != nil && *timeZone != "" {
+		loc, err = time.LoadLocation(*timeZone)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid time zone %q: %v", *timeZone, err)
+		}
+	}
+	return sched, loc, nil
+}
+
+// earliestScheduleTime returns the time after which scheduled times have not
+// been handled yet: the last scheduled time, or the creation time of the
+// CronExecution, bounded by the starting deadline.
+func earliestScheduleTime(
+	ko *svcapitypes.CronExecution,
+	now time.Time,
+) time.Time {
+	earliest := ko.CreationTimestamp.Time
+	if ko.Status.LastScheduleTime != nil {
+		earliest = ko.Status.LastScheduleTime.Time
+	}
+	if ko.Spec.StartingDeadlineSeconds != nil {
+		deadline := now.Add(-time.Duration(*ko.Spec.StartingDeadlineSeconds) * time.Second)
+		if deadline.After(earliest) {
+			earliest = deadline
+		}
+	}
+	return earliest
+}
+
+// mostRecentScheduleTime returns the most recent scheduled time after
+// earliest and not after now, or nil if there is none, along with the number
+// of scheduled times in that interval. Only the most recent one is started,
+// the ones before it are missed.
+func mostRecentScheduleTime(
+	sched cron.Schedule,
+	loc *time.Location,
+	earliest time.Time,
+	now time.Time,
+) (*time.Time, int) {
+	var mostRecent *time.Time
+	count := 0
+	for t := sched.Next(earliest.In(loc)); !t.IsZero() && !t.After(now); t = sched.Next(t) {
+		t := t
+		mostRecent = &t
+		count++
+	}
+	return mostRecent, count
+}
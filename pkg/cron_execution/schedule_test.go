@@ -0,0 +1,221 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cron_execution
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+)
+
+func TestParseSchedule(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule *string
+		timeZone *string
+		wantErr  bool
+		wantLoc  string
+	}{
+		{
+			name:     "UTC by default",
+			schedule: aws.String("*/5 * * * *"),
+			wantLoc:  "UTC",
+		},
+		{
+			name:     "empty time zone",
+			schedule: aws.String("@hourly"),
+			timeZone: aws.String(""),
+			wantLoc:  "UTC",
+		},
+		{
+			name:     "time zone",
+			schedule: aws.String("0 9 * * 1-5"),
+			timeZone: aws.String("Europe/Paris"),
+			wantLoc:  "Europe/Paris",
+		},
+		{
+			name:    "missing schedule",
+			wantErr: true,
+		},
+		{
+			name:     "invalid schedule",
+			schedule: aws.String("every minute"),
+			wantErr:  true,
+		},
+		{
+			name:     "seconds field",
+			schedule: aws.String("0 */5 * * * *"),
+			wantErr:  true,
+		},
+		{
+			name:     "time zone in the schedule",
+			schedule: aws.String("CRON_TZ=Europe/Paris 0 9 * * *"),
+			wantErr:  true,
+		},
+		{
+			name:     "invalid time zone",
+			schedule: aws.String("0 9 * * *"),
+			timeZone: aws.String("Mars/Olympus_Mons"),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sched, loc, err := parseSchedule(tt.schedule, tt.timeZone)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSchedule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if sched == nil {
+				t.Fatal("parseSchedule() schedule = nil")
+			}
+			if loc.String() != tt.wantLoc {
+				t.Errorf("parseSchedule() location = %s, want %s", loc, tt.wantLoc)
+			}
+		})
+	}
+}
+
+func TestEarliestScheduleTime(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	created := now.Add(-24 * time.Hour)
+	lastScheduled := now.Add(-time.Hour)
+
+	tests := []struct {
+		name                    string
+		lastScheduleTime        *time.Time
+		startingDeadlineSeconds *int64
+		want                    time.Time
+	}{
+		{
+			name: "never scheduled",
+			want: created,
+		},
+		{
+			name:             "scheduled",
+			lastScheduleTime: &lastScheduled,
+			want:             lastScheduled,
+		},
+		{
+			name:                    "deadline after the last scheduled time",
+			lastScheduleTime:        &lastScheduled,
+			startingDeadlineSeconds: aws.Int64(60),
+			want:                    now.Add(-time.Minute),
+		},
+		{
+			name:                    "deadline before the last scheduled time",
+			lastScheduleTime:        &lastScheduled,
+			startingDeadlineSeconds: aws.Int64(7200),
+			want:                    lastScheduled,
+		},
+		{
+			name:                    "deadline after the creation time",
+			startingDeadlineSeconds: aws.Int64(300),
+			want:                    now.Add(-5 * time.Minute),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ko := &svcapitypes.CronExecution{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.Time{Time: created}},
+				Spec:       svcapitypes.CronExecutionSpec{StartingDeadlineSeconds: tt.startingDeadlineSeconds},
+			}
+			if tt.lastScheduleTime != nil {
+				ko.Status.LastScheduleTime = &metav1.Time{Time: *tt.lastScheduleTime}
+			}
+			if got := earliestScheduleTime(ko, now); !got.Equal(tt.want) {
+				t.Errorf("earliestScheduleTime() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMostRecentScheduleTime(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 3, 1, 12, 7, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		schedule  string
+		loc       *time.Location
+		earliest  time.Time
+		want      *time.Time
+		wantCount int
+	}{
+		{
+			name:     "nothing scheduled yet",
+			schedule: "0 * * * *",
+			loc:      time.UTC,
+			earliest: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "one scheduled time",
+			schedule:  "*/5 * * * *",
+			loc:       time.UTC,
+			earliest:  time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+			want:      aws.Time(time.Date(2024, 3, 1, 12, 5, 0, 0, time.UTC)),
+			wantCount: 1,
+		},
+		{
+			name:      "missed scheduled times",
+			schedule:  "*/5 * * * *",
+			loc:       time.UTC,
+			earliest:  time.Date(2024, 3, 1, 11, 0, 0, 0, time.UTC),
+			want:      aws.Time(time.Date(2024, 3, 1, 12, 5, 0, 0, time.UTC)),
+			wantCount: 13,
+		},
+		{
+			name:      "scheduled time equal to now",
+			schedule:  "7 * * * *",
+			loc:       time.UTC,
+			earliest:  time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+			want:      aws.Time(now),
+			wantCount: 1,
+		},
+		{
+			name:      "time zone",
+			schedule:  "0 13 * * *",
+			loc:       paris,
+			earliest:  time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			want:      aws.Time(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)),
+			wantCount: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sched, _, err := parseSchedule(&tt.schedule, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, count := mostRecentScheduleTime(sched, tt.loc, tt.earliest, now)
+			if count != tt.wantCount {
+				t.Errorf("mostRecentScheduleTime() count = %d, want %d", count, tt.wantCount)
+			}
+			switch {
+			case got == nil && tt.want == nil:
+			case got == nil || tt.want == nil || !got.Equal(*tt.want):
+				t.Errorf("mostRecentScheduleTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
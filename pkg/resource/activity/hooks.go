@@ -15,14 +15,23 @@ package activity
 
 import (
 	"context"
+	"errors"
 
 	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
 	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
 
 	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
 	commonutil "github.com/aws-controllers-k8s/sfn-controller/pkg/util"
 )
 
+var (
+	errImmutableEncryptionConfiguration = errors.New(
+		"encryptionConfiguration cannot be changed after an activity is " +
+			"created; delete and recreate the Activity to use a different key",
+	)
+)
+
 // setResourceAdditionalFields queries and adds the tags to an Activity resource
 func (rm *resourceManager) setResourceAdditionalFields(
 	ctx context.Context,
@@ -55,6 +64,11 @@ func (rm *resourceManager) customUpdateActivity(
 	latest *resource,
 	delta *ackcompare.Delta,
 ) (*resource, error) {
+	// Activities cannot be updated, only their tags can. Any other change
+	// requires the activity to be recreated.
+	if delta.DifferentAt("Spec.EncryptionConfiguration") {
+		return nil, ackerr.NewTerminalError(errImmutableEncryptionConfiguration)
+	}
 	if delta.DifferentAt("Spec.Tags") {
 		err := commonutil.SyncResourceTags(
 			ctx,
@@ -76,6 +90,9 @@ func customPreCompare(
 	a *resource,
 	b *resource,
 ) {
+	if !commonutil.EqualEncryptionConfiguration(a.ko.Spec.EncryptionConfiguration, b.ko.Spec.EncryptionConfiguration) {
+		delta.Add("Spec.EncryptionConfiguration", a.ko.Spec.EncryptionConfiguration, b.ko.Spec.EncryptionConfiguration)
+	}
 	if len(a.ko.Spec.Tags) != len(b.ko.Spec.Tags) {
 		delta.Add("Spec.Tags", a.ko.Spec.Tags, b.ko.Spec.Tags)
 	} else if len(a.ko.Spec.Tags) > 0 {
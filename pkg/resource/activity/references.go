@@ -17,14 +17,21 @@ package activity
 
 import (
 	"context"
+	"fmt"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	ackrt "github.com/aws-controllers-k8s/runtime/pkg/runtime"
 	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
 
 	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+	commonutil "github.com/aws-controllers-k8s/sfn-controller/pkg/util"
 )
 
+// +kubebuilder:rbac:groups=kms.services.k8s.aws,resources=keys,verbs=get;list
+// +kubebuilder:rbac:groups=kms.services.k8s.aws,resources=keys/status,verbs=get;list
+
 // ClearResolvedReferences removes any reference values that were made
 // concrete in the spec. It returns a copy of the input AWSResource which
 // contains the original *Ref values, but none of their respective concrete
@@ -32,6 +39,12 @@ import (
 func (rm *resourceManager) ClearResolvedReferences(res acktypes.AWSResource) acktypes.AWSResource {
 	ko := rm.concreteResource(res).ko.DeepCopy()
 
+	if ko.Spec.EncryptionConfiguration != nil {
+		if ko.Spec.EncryptionConfiguration.KMSKeyRef != nil {
+			ko.Spec.EncryptionConfiguration.KMSKeyID = nil
+		}
+	}
+
 	return &resource{ko}
 }
 
@@ -47,11 +60,67 @@ func (rm *resourceManager) ResolveReferences(
 	apiReader client.Reader,
 	res acktypes.AWSResource,
 ) (acktypes.AWSResource, bool, error) {
-	return res, false, nil
+	ko := rm.concreteResource(res).ko
+
+	resourceHasReferences := false
+	err := validateReferenceFields(ko)
+	if fieldHasReferences, err := rm.resolveReferenceForEncryptionConfiguration_KMSKeyID(ctx, apiReader, ko); err != nil {
+		return &resource{ko}, (resourceHasReferences || fieldHasReferences), err
+	} else {
+		resourceHasReferences = resourceHasReferences || fieldHasReferences
+	}
+
+	return &resource{ko}, resourceHasReferences, err
 }
 
 // validateReferenceFields validates the reference field and corresponding
 // identifier field.
 func validateReferenceFields(ko *svcapitypes.Activity) error {
+
+	if ko.Spec.EncryptionConfiguration != nil {
+		if ko.Spec.EncryptionConfiguration.KMSKeyRef != nil && ko.Spec.EncryptionConfiguration.KMSKeyID != nil {
+			return ackerr.ResourceReferenceAndIDNotSupportedFor("EncryptionConfiguration.KMSKeyID", "EncryptionConfiguration.KMSKeyRef")
+		}
+	}
 	return nil
 }
+
+// resolveReferenceForEncryptionConfiguration_KMSKeyID reads the resource
+// referenced from EncryptionConfiguration.KMSKeyRef field and sets the
+// EncryptionConfiguration.KMSKeyID from referenced resource. Returns a
+// boolean indicating whether a reference contains references, or an error
+func (rm *resourceManager) resolveReferenceForEncryptionConfiguration_KMSKeyID(
+	ctx context.Context,
+	apiReader client.Reader,
+	ko *svcapitypes.Activity,
+) (hasReferences bool, err error) {
+	if ko.Spec.EncryptionConfiguration == nil {
+		return false, nil
+	}
+	if ko.Spec.EncryptionConfiguration.KMSKeyRef != nil && ko.Spec.EncryptionConfiguration.KMSKeyRef.From != nil {
+		hasReferences = true
+		arr := ko.Spec.EncryptionConfiguration.KMSKeyRef.From
+		if arr.Name == nil || *arr.Name == "" {
+			return hasReferences, fmt.Errorf("provided resource reference is nil or empty: EncryptionConfiguration.KMSKeyRef")
+		}
+		namespace, err := ackrt.ResolveCrossNamespaceReference(
+			ctx,
+			rm.cfg.EnableCrossNamespace,
+			&ko.Status.Conditions,
+			ackrt.CrossNamespaceRefKindResource,
+			ko.ObjectMeta.GetNamespace(),
+			arr.Namespace,
+			*arr.Name,
+		)
+		if err != nil {
+			return hasReferences, err
+		}
+		keyARN, err := commonutil.GetReferencedResourceARN(ctx, apiReader, commonutil.KMSKeyGVK, *arr.Name, namespace)
+		if err != nil {
+			return hasReferences, err
+		}
+		ko.Spec.EncryptionConfiguration.KMSKeyID = &keyARN
+	}
+
+	return hasReferences, nil
+}
@@ -19,6 +19,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
 	"strings"
 
@@ -102,6 +103,25 @@ func (rm *resourceManager) sdkFind(
 	} else {
 		ko.Status.CreationDate = nil
 	}
+	if resp.EncryptionConfiguration != nil {
+		f2 := &svcapitypes.EncryptionConfiguration{}
+		if resp.EncryptionConfiguration.KmsDataKeyReusePeriodSeconds != nil {
+			kmsDataKeyReusePeriodSecondsCopy := int64(*resp.EncryptionConfiguration.KmsDataKeyReusePeriodSeconds)
+			f2.KMSDataKeyReusePeriodSeconds = &kmsDataKeyReusePeriodSecondsCopy
+		}
+		if resp.EncryptionConfiguration.KmsKeyId != nil {
+			f2.KMSKeyID = resp.EncryptionConfiguration.KmsKeyId
+		}
+		if resp.EncryptionConfiguration.Type != "" {
+			f2.Type = aws.String(string(resp.EncryptionConfiguration.Type))
+		}
+		if r.ko.Spec.EncryptionConfiguration != nil {
+			f2.KMSKeyRef = r.ko.Spec.EncryptionConfiguration.KMSKeyRef
+		}
+		ko.Spec.EncryptionConfiguration = f2
+	} else {
+		ko.Spec.EncryptionConfiguration = nil
+	}
 	if resp.Name != nil {
 		ko.Spec.Name = resp.Name
 	} else {
@@ -192,22 +212,40 @@ func (rm *resourceManager) newCreateRequestPayload(
 ) (*svcsdk.CreateActivityInput, error) {
 	res := &svcsdk.CreateActivityInput{}
 
+	if r.ko.Spec.EncryptionConfiguration != nil {
+		f0 := &svcsdktypes.EncryptionConfiguration{}
+		if r.ko.Spec.EncryptionConfiguration.KMSDataKeyReusePeriodSeconds != nil {
+			kmsDataKeyReusePeriodSecondsCopy0 := *r.ko.Spec.EncryptionConfiguration.KMSDataKeyReusePeriodSeconds
+			if kmsDataKeyReusePeriodSecondsCopy0 > math.MaxInt32 || kmsDataKeyReusePeriodSecondsCopy0 < math.MinInt32 {
+				return nil, fmt.Errorf("error: field KmsDataKeyReusePeriodSeconds is of type int32")
+			}
+			kmsDataKeyReusePeriodSecondsCopy := int32(kmsDataKeyReusePeriodSecondsCopy0)
+			f0.KmsDataKeyReusePeriodSeconds = &kmsDataKeyReusePeriodSecondsCopy
+		}
+		if r.ko.Spec.EncryptionConfiguration.KMSKeyID != nil {
+			f0.KmsKeyId = r.ko.Spec.EncryptionConfiguration.KMSKeyID
+		}
+		if r.ko.Spec.EncryptionConfiguration.Type != nil {
+			f0.Type = svcsdktypes.EncryptionType(*r.ko.Spec.EncryptionConfiguration.Type)
+		}
+		res.EncryptionConfiguration = f0
+	}
 	if r.ko.Spec.Name != nil {
 		res.Name = r.ko.Spec.Name
 	}
 	if r.ko.Spec.Tags != nil {
-		f1 := []svcsdktypes.Tag{}
-		for _, f1iter := range r.ko.Spec.Tags {
-			f1elem := &svcsdktypes.Tag{}
-			if f1iter.Key != nil {
-				f1elem.Key = f1iter.Key
+		f2 := []svcsdktypes.Tag{}
+		for _, f2iter := range r.ko.Spec.Tags {
+			f2elem := &svcsdktypes.Tag{}
+			if f2iter.Key != nil {
+				f2elem.Key = f2iter.Key
 			}
-			if f1iter.Value != nil {
-				f1elem.Value = f1iter.Value
+			if f2iter.Value != nil {
+				f2elem.Value = f2iter.Value
 			}
-			f1 = append(f1, *f1elem)
+			f2 = append(f2, *f2elem)
 		}
-		res.Tags = f1
+		res.Tags = f2
 	}
 
 	return res, nil
@@ -361,6 +399,21 @@ func (rm *resourceManager) updateConditions(
 // and if the exception indicates that it is a Terminal exception
 // 'Terminal' exception are specified in generator configuration
 func (rm *resourceManager) terminalAWSError(err error) bool {
-	// No terminal_errors specified for this resource in generator config
-	return false
+	if err == nil {
+		return false
+	}
+
+	var terminalErr smithy.APIError
+	if !errors.As(err, &terminalErr) {
+		return false
+	}
+	switch terminalErr.ErrorCode() {
+	case "InvalidEncryptionConfiguration",
+		"InvalidName",
+		"TooManyTags",
+		"ValidationException":
+		return true
+	default:
+		return false
+	}
 }
@@ -0,0 +1,49 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package activity
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	smithy "github.com/aws/smithy-go"
+)
+
+func TestTerminalAWSError(t *testing.T) {
+	rm := &resourceManager{}
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{"InvalidEncryptionConfiguration", true},
+		{"InvalidName", true},
+		{"TooManyTags", true},
+		{"ValidationException", true},
+		{"ActivityLimitExceeded", false},
+		{"ThrottlingException", false},
+	}
+	for _, tt := range tests {
+		err := fmt.Errorf("operation error: %w", &smithy.GenericAPIError{Code: tt.code, Message: "failed"})
+		if got := rm.terminalAWSError(err); got != tt.want {
+			t.Errorf("terminalAWSError(%s) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+	if rm.terminalAWSError(errors.New("connection reset by peer")) {
+		t.Error("terminalAWSError() = true for an error that is not an AWS error")
+	}
+	if rm.terminalAWSError(nil) {
+		t.Error("terminalAWSError(nil) = true")
+	}
+}
@@ -0,0 +1,246 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/sfn"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/sfn/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+	commonutil "github.com/aws-controllers-k8s/sfn-controller/pkg/util"
+)
+
+const (
+	// maxHistoryEventsPerSync is the maximum number of history events
+	// recorded as Kubernetes Events per reconcile. Kubernetes throttles the
+	// Events of an object once a burst of about 25 is exceeded, the remaining
+	// history events are recorded on the following reconciles.
+	maxHistoryEventsPerSync = 25
+	// maxHistoryEventMessageLength bounds the length of the error causes
+	// included in the Kubernetes Event messages.
+	maxHistoryEventMessageLength = 512
+	// historyPageSize is the number of history events read per
+	// GetExecutionHistory call, the maximum the API accepts.
+	historyPageSize = 1000
+)
+
+// recordExecutionHistory records the key events of the execution history
+// that were not recorded yet as Kubernetes Events on the supplied Execution,
+// and stores the ID of the last event read in Status.LastHistoryEventID.
+// Once the execution stopped and its whole history was recorded, the history
+// is not read anymore, see Status.HistoryRecorded. The history is a debugging
+// aid, failing to read it is logged and does not fail the reconcile.
+func (rm *resourceManager) recordExecutionHistory(
+	ctx context.Context,
+	ko *svcapitypes.Execution,
+) {
+	rlog := ackrtlog.FromContext(ctx)
+	if !executionRunning(ko) && aws.ToBool(ko.Status.HistoryRecorded) {
+		return
+	}
+	// The status was read before the history, the history of a stopped
+	// execution is complete once read to the end.
+	stopped := !executionRunning(ko)
+	events, pageToken, complete, err := rm.getNewExecutionHistory(ctx, ko)
+	if err != nil {
+		rlog.Info("unable to read execution history", "error", err)
+		return
+	}
+	for _, event := range events {
+		if eventType, reason, message, ok := kubernetesEventFor(event); ok {
+			commonutil.RecordEvent(ko, eventType, reason, "%s", message)
+		}
+		ko.Status.LastHistoryEventID = event.ID
+	}
+	ko.Status.HistoryPageToken = pageToken
+	ko.Status.HistoryRecorded = aws.Bool(stopped && complete)
+}
+
+// getNewExecutionHistory returns the history events of the execution that
+// follow Status.LastHistoryEventID, oldest first, the pagination token of the
+// page holding the last of them and whether the history was read to the end.
+// The history is read from the page of Status.HistoryPageToken, or from the
+// first page once the token expired, and the read stops once
+// maxHistoryEventsPerSync events to record are returned, the following
+// events are read on the next reconcile.
+func (rm *resourceManager) getNewExecutionHistory(
+	ctx context.Context,
+	ko *svcapitypes.Execution,
+) (events []*svcapitypes.HistoryEvent, pageToken *string, complete bool, err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.getNewExecutionHistory")
+	defer func() {
+		exit(err)
+	}()
+
+	lastEventID := int64(0)
+	if ko.Status.LastHistoryEventID != nil {
+		lastEventID = *ko.Status.LastHistoryEventID
+	}
+	executionARN := string(*ko.Status.ACKResourceMetadata.ARN)
+	pageToken = ko.Status.HistoryPageToken
+	input := &svcsdk.GetExecutionHistoryInput{
+		ExecutionArn:         &executionARN,
+		IncludeExecutionData: aws.Bool(false),
+		MaxResults:           historyPageSize,
+		NextToken:            pageToken,
+		ReverseOrder:         false,
+	}
+	recorded := 0
+	for {
+		var resp *svcsdk.GetExecutionHistoryOutput
+		resp, err = rm.sdkapi.GetExecutionHistory(ctx, input)
+		rm.metrics.RecordAPICall("READ_MANY", "GetExecutionHistory", err)
+		if err != nil {
+			// Pagination tokens expire after 24 hours, the events already
+			// recorded are skipped by their ID.
+			var invalidToken *svcsdktypes.InvalidToken
+			if errors.As(err, &invalidToken) && input.NextToken != nil && len(events) == 0 {
+				pageToken = nil
+				input.NextToken = nil
+				continue
+			}
+			return nil, nil, false, err
+		}
+		for _, event := range resp.Events {
+			if event.Id <= lastEventID {
+				continue
+			}
+			historyEvent := historyEventFromSDK(event)
+			events = append(events, historyEvent)
+			if _, _, _, ok := kubernetesEventFor(historyEvent); ok {
+				recorded++
+				if recorded == maxHistoryEventsPerSync {
+					return events, pageToken, false, nil
+				}
+			}
+		}
+		if resp.NextToken == nil {
+			return events, pageToken, true, nil
+		}
+		pageToken = resp.NextToken
+		input.NextToken = pageToken
+	}
+}
+
+// historyEventFromSDK returns the details of the supplied history event that
+// are recorded as Kubernetes Events.
+func historyEventFromSDK(event svcsdktypes.HistoryEvent) *svcapitypes.HistoryEvent {
+	id := event.Id
+	previousEventID := event.PreviousEventId
+	eventType := string(event.Type)
+	ko := &svcapitypes.HistoryEvent{
+		ID:              &id,
+		PreviousEventID: &previousEventID,
+		Type:            &eventType,
+	}
+	if event.Timestamp != nil {
+		ko.Timestamp = &metav1.Time{Time: *event.Timestamp}
+	}
+	if d := event.ExecutionFailedEventDetails; d != nil {
+		ko.ExecutionFailedEventDetails = &svcapitypes.ExecutionFailedEventDetails{
+			Cause: d.Cause,
+			Error: d.Error,
+		}
+	}
+	if d := event.MapRunStartedEventDetails; d != nil {
+		ko.MapRunStartedEventDetails = &svcapitypes.MapRunStartedEventDetails{
+			MapRunARN: d.MapRunArn,
+		}
+	}
+	if d := event.StateEnteredEventDetails; d != nil {
+		ko.StateEnteredEventDetails = &svcapitypes.StateEnteredEventDetails{
+			Name: d.Name,
+		}
+	}
+	if d := event.StateExitedEventDetails; d != nil {
+		ko.StateExitedEventDetails = &svcapitypes.StateExitedEventDetails{
+			Name: d.Name,
+		}
+	}
+	if d := event.TaskFailedEventDetails; d != nil {
+		ko.TaskFailedEventDetails = &svcapitypes.TaskFailedEventDetails{
+			Cause:        d.Cause,
+			Error:        d.Error,
+			Resource:     d.Resource,
+			ResourceType: d.ResourceType,
+		}
+	}
+	if d := event.TaskTimedOutEventDetails; d != nil {
+		ko.TaskTimedOutEventDetails = &svcapitypes.TaskTimedOutEventDetails{
+			Cause:        d.Cause,
+			Error:        d.Error,
+			Resource:     d.Resource,
+			ResourceType: d.ResourceType,
+		}
+	}
+	return ko
+}
+
+// kubernetesEventFor returns the type, reason and message of the Kubernetes
+// Event recorded for the supplied history event, and false if the history
+// event is not recorded.
+func kubernetesEventFor(
+	event *svcapitypes.HistoryEvent,
+) (eventType, reason, message string, ok bool) {
+	switch {
+	case event.StateEnteredEventDetails != nil:
+		return corev1.EventTypeNormal, "StateEntered",
+			fmt.Sprintf("Entered state %s", aws.ToString(event.StateEnteredEventDetails.Name)), true
+	case event.StateExitedEventDetails != nil:
+		return corev1.EventTypeNormal, "StateExited",
+			fmt.Sprintf("Exited state %s", aws.ToString(event.StateExitedEventDetails.Name)), true
+	case event.TaskFailedEventDetails != nil:
+		d := event.TaskFailedEventDetails
+		return corev1.EventTypeWarning, "TaskFailed",
+			fmt.Sprintf("Task %s:%s failed: %s",
+				aws.ToString(d.ResourceType), aws.ToString(d.Resource),
+				errorMessage(d.Error, d.Cause)), true
+	case event.TaskTimedOutEventDetails != nil:
+		d := event.TaskTimedOutEventDetails
+		return corev1.EventTypeWarning, "TaskTimedOut",
+			fmt.Sprintf("Task %s:%s timed out: %s",
+				aws.ToString(d.ResourceType), aws.ToString(d.Resource),
+				errorMessage(d.Error, d.Cause)), true
+	case event.MapRunStartedEventDetails != nil:
+		return corev1.EventTypeNormal, "MapRunStarted",
+			fmt.Sprintf("Started Map Run %s", aws.ToString(event.MapRunStartedEventDetails.MapRunARN)), true
+	case event.ExecutionFailedEventDetails != nil:
+		d := event.ExecutionFailedEventDetails
+		return corev1.EventTypeWarning, "ExecutionFailed",
+			fmt.Sprintf("Execution failed: %s", errorMessage(d.Error, d.Cause)), true
+	}
+	return "", "", "", false
+}
+
+// errorMessage returns the supplied error code and cause, truncating the
+// cause to maxHistoryEventMessageLength.
+func errorMessage(code, cause *string) string {
+	message := aws.ToString(code)
+	if c := aws.ToString(cause); c != "" {
+		if len(c) > maxHistoryEventMessageLength {
+			c = c[:maxHistoryEventMessageLength] + "..."
+		}
+		message += ": " + c
+	}
+	return message
+}
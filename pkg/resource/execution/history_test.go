@@ -0,0 +1,204 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package execution
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/sfn/types"
+	corev1 "k8s.io/api/core/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+)
+
+func TestKubernetesEventFor(t *testing.T) {
+	tests := []struct {
+		name         string
+		event        svcsdktypes.HistoryEvent
+		wantType     string
+		wantReason   string
+		wantMessage  string
+		wantRecorded bool
+	}{
+		{
+			name:  "execution started",
+			event: svcsdktypes.HistoryEvent{Type: svcsdktypes.HistoryEventTypeExecutionStarted},
+		},
+		{
+			name: "state entered",
+			event: svcsdktypes.HistoryEvent{
+				Type:                     svcsdktypes.HistoryEventTypeTaskStateEntered,
+				StateEnteredEventDetails: &svcsdktypes.StateEnteredEventDetails{Name: aws.String("Charge")},
+			},
+			wantType:     corev1.EventTypeNormal,
+			wantReason:   "StateEntered",
+			wantMessage:  "Entered state Charge",
+			wantRecorded: true,
+		},
+		{
+			name: "state exited",
+			event: svcsdktypes.HistoryEvent{
+				Type:                    svcsdktypes.HistoryEventTypeTaskStateExited,
+				StateExitedEventDetails: &svcsdktypes.StateExitedEventDetails{Name: aws.String("Charge")},
+			},
+			wantType:     corev1.EventTypeNormal,
+			wantReason:   "StateExited",
+			wantMessage:  "Exited state Charge",
+			wantRecorded: true,
+		},
+		{
+			name: "task failed",
+			event: svcsdktypes.HistoryEvent{
+				Type: svcsdktypes.HistoryEventTypeTaskFailed,
+				TaskFailedEventDetails: &svcsdktypes.TaskFailedEventDetails{
+					Resource:     aws.String("invoke"),
+					ResourceType: aws.String("lambda"),
+					Error:        aws.String("Lambda.Unknown"),
+					Cause:        aws.String("The function timed out"),
+				},
+			},
+			wantType:     corev1.EventTypeWarning,
+			wantReason:   "TaskFailed",
+			wantMessage:  "Task lambda:invoke failed: Lambda.Unknown: The function timed out",
+			wantRecorded: true,
+		},
+		{
+			name: "task timed out",
+			event: svcsdktypes.HistoryEvent{
+				Type: svcsdktypes.HistoryEventTypeTaskTimedOut,
+				TaskTimedOutEventDetails: &svcsdktypes.TaskTimedOutEventDetails{
+					Resource:     aws.String("startExecution.sync"),
+					ResourceType: aws.String("states"),
+					Error:        aws.String("States.Timeout"),
+				},
+			},
+			wantType:     corev1.EventTypeWarning,
+			wantReason:   "TaskTimedOut",
+			wantMessage:  "Task states:startExecution.sync timed out: States.Timeout",
+			wantRecorded: true,
+		},
+		{
+			name: "map run started",
+			event: svcsdktypes.HistoryEvent{
+				Type: svcsdktypes.HistoryEventTypeMapRunStarted,
+				MapRunStartedEventDetails: &svcsdktypes.MapRunStartedEventDetails{
+					MapRunArn: aws.String("arn:aws:states:us-west-2:111111111111:mapRun:hello/Map:run"),
+				},
+			},
+			wantType:     corev1.EventTypeNormal,
+			wantReason:   "MapRunStarted",
+			wantMessage:  "Started Map Run arn:aws:states:us-west-2:111111111111:mapRun:hello/Map:run",
+			wantRecorded: true,
+		},
+		{
+			name: "execution failed",
+			event: svcsdktypes.HistoryEvent{
+				Type: svcsdktypes.HistoryEventTypeExecutionFailed,
+				ExecutionFailedEventDetails: &svcsdktypes.ExecutionFailedEventDetails{
+					Error: aws.String("States.TaskFailed"),
+					Cause: aws.String("Charge failed"),
+				},
+			},
+			wantType:     corev1.EventTypeWarning,
+			wantReason:   "ExecutionFailed",
+			wantMessage:  "Execution failed: States.TaskFailed: Charge failed",
+			wantRecorded: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eventType, reason, message, ok := kubernetesEventFor(historyEventFromSDK(tt.event))
+			if ok != tt.wantRecorded {
+				t.Fatalf("kubernetesEventFor() recorded = %v, want %v", ok, tt.wantRecorded)
+			}
+			if eventType != tt.wantType || reason != tt.wantReason || message != tt.wantMessage {
+				t.Errorf("kubernetesEventFor() = (%q, %q, %q), want (%q, %q, %q)",
+					eventType, reason, message, tt.wantType, tt.wantReason, tt.wantMessage)
+			}
+		})
+	}
+
+	// Events without details are not recorded.
+	if _, _, _, ok := kubernetesEventFor(&svcapitypes.HistoryEvent{}); ok {
+		t.Error("kubernetesEventFor() recorded an event without details")
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	longCause := strings.Repeat("x", maxHistoryEventMessageLength+1)
+	tests := []struct {
+		name  string
+		code  *string
+		cause *string
+		want  string
+	}{
+		{
+			name: "nothing",
+			want: "",
+		},
+		{
+			name: "code",
+			code: aws.String("States.Timeout"),
+			want: "States.Timeout",
+		},
+		{
+			name:  "code and cause",
+			code:  aws.String("States.TaskFailed"),
+			cause: aws.String("Charge failed"),
+			want:  "States.TaskFailed: Charge failed",
+		},
+		{
+			name:  "empty cause",
+			code:  aws.String("States.TaskFailed"),
+			cause: aws.String(""),
+			want:  "States.TaskFailed",
+		},
+		{
+			name:  "cause at the maximum length",
+			code:  aws.String("States.TaskFailed"),
+			cause: aws.String(longCause[1:]),
+			want:  "States.TaskFailed: " + longCause[1:],
+		},
+		{
+			name:  "truncated cause",
+			code:  aws.String("States.TaskFailed"),
+			cause: aws.String(longCause),
+			want:  "States.TaskFailed: " + longCause[1:] + "...",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorMessage(tt.code, tt.cause); got != tt.want {
+				t.Errorf("errorMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordExecutionHistoryRecorded(t *testing.T) {
+	// The history of a stopped execution that was recorded is not read
+	// again, rm has no client to read it with.
+	rm := &resourceManager{}
+	ko := &svcapitypes.Execution{}
+	ko.Status.Status = aws.String(string(svcsdktypes.ExecutionStatusSucceeded))
+	ko.Status.HistoryRecorded = aws.Bool(true)
+	ko.Status.LastHistoryEventID = aws.Int64(12)
+	rm.recordExecutionHistory(context.TODO(), ko)
+	if got := aws.ToInt64(ko.Status.LastHistoryEventID); got != 12 {
+		t.Errorf("recordExecutionHistory() LastHistoryEventID = %d, want 12", got)
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package execution
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/sfn"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/sfn/types"
+	smithy "github.com/aws/smithy-go"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+)
+
+var (
+	errImmutableExecution = errors.New(
+		"executions cannot be modified once started, create a new Execution instead",
+	)
+	expressExecutionMessage = "executions of EXPRESS state machines cannot be described, " +
+		"their status is only available in the CloudWatch Logs of the state machine"
+)
+
+// customFindExecution describes the execution whose ARN is stored in the
+// resource status. Executions are never started again once their ARN is
+// known: when Step Functions no longer knows the execution, because its
+// history expired, the last observed state is kept. The key events of the
+// execution history are recorded as Kubernetes Events on the resource.
+func (rm *resourceManager) customFindExecution(
+	ctx context.Context,
+	r *resource,
+) (latest *resource, err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.customFindExecution")
+	defer func() {
+		exit(err)
+	}()
+	// The execution ARN is only known once StartExecution succeeded, the
+	// execution does not exist before that.
+	if !executionStarted(r.ko) {
+		return nil, ackerr.NotFound
+	}
+	executionARN := string(*r.ko.Status.ACKResourceMetadata.ARN)
+	// The outcome of executions started in Sync mode is recorded when they
+	// are started.
+	if executionSyncMode(r.ko) {
+		latest = &resource{r.ko.DeepCopy()}
+		setLatestRedriveGeneration(latest.ko)
+		return latest, nil
+	}
+	// There is nothing to poll for EXPRESS executions, mark them synced so
+	// that they are not requeued until the next resync.
+	if isExpressExecutionARN(executionARN) {
+		ko := r.ko.DeepCopy()
+		reason := "ExpressExecution"
+		latest = &resource{ko}
+		ackcondition.SetSynced(latest, corev1.ConditionTrue, &expressExecutionMessage, &reason)
+		setLatestRedriveGeneration(latest.ko)
+		return latest, nil
+	}
+
+	var resp *svcsdk.DescribeExecutionOutput
+	resp, err = rm.sdkapi.DescribeExecution(
+		ctx,
+		&svcsdk.DescribeExecutionInput{
+			ExecutionArn: &executionARN,
+		},
+	)
+	rm.metrics.RecordAPICall("READ_ONE", "DescribeExecution", err)
+	if err != nil {
+		var awsErr smithy.APIError
+		if errors.As(err, &awsErr) && awsErr.ErrorCode() == "ExecutionDoesNotExist" {
+			latest = &resource{r.ko.DeepCopy()}
+			setLatestRedriveGeneration(latest.ko)
+			return latest, nil
+		}
+		return nil, err
+	}
+
+	ko := r.ko.DeepCopy()
+
+	ko.Status.Cause = resp.Cause
+	ko.Status.Error = resp.Error
+	ko.Status.Output = resp.Output
+	if resp.RedriveCount != nil {
+		redriveCount := int64(*resp.RedriveCount)
+		ko.Status.RedriveCount = &redriveCount
+	} else {
+		ko.Status.RedriveCount = nil
+	}
+	if resp.RedriveDate != nil {
+		ko.Status.RedriveDate = &metav1.Time{Time: *resp.RedriveDate}
+	} else {
+		ko.Status.RedriveDate = nil
+	}
+	if resp.RedriveStatus != "" {
+		redriveStatus := string(resp.RedriveStatus)
+		ko.Status.RedriveStatus = &redriveStatus
+	} else {
+		ko.Status.RedriveStatus = nil
+	}
+	ko.Status.RedriveStatusReason = resp.RedriveStatusReason
+	if resp.StartDate != nil {
+		ko.Status.StartDate = &metav1.Time{Time: *resp.StartDate}
+	} else {
+		ko.Status.StartDate = nil
+	}
+	if resp.Status != "" {
+		status := string(resp.Status)
+		ko.Status.Status = &status
+	} else {
+		ko.Status.Status = nil
+	}
+	if resp.StopDate != nil {
+		ko.Status.StopDate = &metav1.Time{Time: *resp.StopDate}
+	} else {
+		ko.Status.StopDate = nil
+	}
+
+	rm.recordExecutionHistory(ctx, ko)
+	setLatestRedriveGeneration(ko)
+	rm.setStatusDefaults(ko)
+	return &resource{ko}, nil
+}
+
+// customUpdateExecution redrives the execution when Spec.RedriveGeneration
+// was increased, and returns a terminal error for any other change: an
+// execution runs with the state machine, input and name it was started with.
+func (rm *resourceManager) customUpdateExecution(
+	ctx context.Context,
+	desired *resource,
+	latest *resource,
+	delta *ackcompare.Delta,
+) (*resource, error) {
+	if delta.DifferentExcept("Spec.RedriveGeneration") {
+		return nil, ackerr.NewTerminalError(errImmutableExecution)
+	}
+	return rm.redriveExecution(ctx, desired, latest)
+}
+
+// executionStarted returns true if StartExecution succeeded for the supplied
+// Execution, that is if its ARN is known.
+func executionStarted(ko *svcapitypes.Execution) bool {
+	return ko.Status.ACKResourceMetadata != nil && ko.Status.ACKResourceMetadata.ARN != nil
+}
+
+// executionRunning returns true if the supplied Execution was started and
+// was last observed running.
+func executionRunning(ko *svcapitypes.Execution) bool {
+	return executionStarted(ko) && ko.Status.Status != nil &&
+		*ko.Status.Status == string(svcsdktypes.ExecutionStatusRunning)
+}
+
+// defaultExecutionName returns the name of an execution started without
+// Spec.Name: the UID of the Execution. StartExecution is idempotent for a
+// given name and input, so retrying it after the ARN could not be recorded
+// does not start a second execution.
+func defaultExecutionName(ko *svcapitypes.Execution) *string {
+	name := string(ko.UID)
+	return &name
+}
+
+// isExpressExecutionARN returns true if the supplied ARN is the ARN of an
+// execution of an EXPRESS state machine, e.g.
+// arn:aws:states:us-west-2:111122223333:express:name:id:id.
+func isExpressExecutionARN(arn string) bool {
+	parts := strings.SplitN(arn, ":", 7)
+	return len(parts) == 7 && parts[5] == "express"
+}
@@ -0,0 +1,38 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package execution
+
+import (
+	"testing"
+)
+
+func TestIsExpressExecutionARN(t *testing.T) {
+	tests := []struct {
+		arn  string
+		want bool
+	}{
+		{"arn:aws:states:us-west-2:111111111111:express:hello:run:4f5c5a4e-4a1e-4d5b-9c43-08f1f6f0a3b2", true},
+		{"arn:aws-cn:states:cn-north-1:111111111111:express:hello:run:4f5c5a4e-4a1e-4d5b-9c43-08f1f6f0a3b2", true},
+		{"arn:aws:states:us-west-2:111111111111:execution:hello:run", false},
+		{"arn:aws:states:us-west-2:111111111111:execution:express:run", false},
+		{"arn:aws:states:us-west-2:111111111111:stateMachine:express", false},
+		{"arn:aws:states:us-west-2:111111111111:express", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := isExpressExecutionARN(tt.arn); got != tt.want {
+			t.Errorf("isExpressExecutionARN(%q) = %v, want %v", tt.arn, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package execution
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+	commonutil "github.com/aws-controllers-k8s/sfn-controller/pkg/util"
+)
+
+// resolveReferenceForInputFrom reads the ConfigMap or Secret key referenced
+// from the InputFrom field and sets the Input from its value. The input is
+// only read until the execution is started, so that the source can be
+// changed or deleted afterwards. Returns a boolean indicating whether a
+// reference contains references, or an error
+func (rm *resourceManager) resolveReferenceForInputFrom(
+	ctx context.Context,
+	apiReader client.Reader,
+	ko *svcapitypes.Execution,
+) (hasReferences bool, err error) {
+	if ko.Spec.InputFrom == nil || executionStarted(ko) {
+		return false, nil
+	}
+	namespace := ko.ObjectMeta.GetNamespace()
+	var input string
+	switch {
+	case ko.Spec.InputFrom.ConfigMapKeyRef != nil:
+		input, err = commonutil.GetConfigMapKey(ctx, apiReader, namespace, ko.Spec.InputFrom.ConfigMapKeyRef)
+	case ko.Spec.InputFrom.SecretKeyRef != nil:
+		input, err = commonutil.GetSecretKey(ctx, apiReader, namespace, ko.Spec.InputFrom.SecretKeyRef)
+	default:
+		err = fmt.Errorf("provided input source is nil or empty: InputFrom")
+	}
+	if err != nil {
+		return true, err
+	}
+	ko.Spec.Input = &input
+	return true, nil
+}
@@ -0,0 +1,50 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package execution
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+)
+
+func TestIsSynced(t *testing.T) {
+	rm := &resourceManager{}
+	tests := []struct {
+		status *string
+		want   bool
+	}{
+		{nil, false},
+		{aws.String("RUNNING"), false},
+		{aws.String("PENDING_REDRIVE"), false},
+		{aws.String("SUCCEEDED"), true},
+		{aws.String("FAILED"), true},
+		{aws.String("TIMED_OUT"), true},
+		{aws.String("ABORTED"), true},
+	}
+	for _, tt := range tests {
+		ko := &svcapitypes.Execution{}
+		ko.Status.Status = tt.status
+		got, err := rm.IsSynced(context.TODO(), &resource{ko})
+		if err != nil {
+			t.Fatalf("IsSynced() error = %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("IsSynced() with status %v = %v, want %v", aws.ToString(tt.status), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/sfn"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/sfn/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+)
+
+// redriveRequeuePeriod is how long to wait before redriving an execution
+// that is still running when Spec.RedriveGeneration is increased.
+const redriveRequeuePeriod = 30 * time.Second
+
+var errRedriveExecutionRunning = errors.New(
+	"execution is still running, waiting for it to complete before redriving it",
+)
+
+// redriveExecution redrives the execution for the Spec.RedriveGeneration of
+// the desired resource. The redrive is delayed while the execution is still
+// running, and a terminal error is returned if Step Functions reports that it
+// cannot be redriven.
+func (rm *resourceManager) redriveExecution(
+	ctx context.Context,
+	desired *resource,
+	latest *resource,
+) (updated *resource, err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.redriveExecution")
+	defer func() {
+		exit(err)
+	}()
+
+	// The spec of latest only differs from the desired one by the
+	// RedriveGeneration set by setLatestRedriveGeneration, return the desired
+	// spec so that it is not patched.
+	ko := desired.ko.DeepCopy()
+	ko.Status = *latest.ko.Status.DeepCopy()
+	updated = &resource{ko}
+
+	if !redriveRequested(ko) {
+		return updated, nil
+	}
+	if executionRunning(ko) {
+		return updated, ackrequeue.NeededAfter(errRedriveExecutionRunning, redriveRequeuePeriod)
+	}
+	if ko.Status.RedriveStatus == nil ||
+		*ko.Status.RedriveStatus != string(svcsdktypes.ExecutionRedriveStatusRedrivable) {
+		reason := "EXPRESS executions cannot be redriven"
+		if ko.Status.RedriveStatusReason != nil {
+			reason = *ko.Status.RedriveStatusReason
+		}
+		return updated, ackerr.NewTerminalError(
+			fmt.Errorf("execution cannot be redriven: %s", reason),
+		)
+	}
+
+	executionARN := string(*ko.Status.ACKResourceMetadata.ARN)
+	// The client token makes retrying the redrive of a given generation
+	// idempotent.
+	clientToken := fmt.Sprintf("%s-%d", ko.UID, *ko.Spec.RedriveGeneration)
+	var resp *svcsdk.RedriveExecutionOutput
+	resp, err = rm.sdkapi.RedriveExecution(
+		ctx,
+		&svcsdk.RedriveExecutionInput{
+			ExecutionArn: &executionARN,
+			ClientToken:  &clientToken,
+		},
+	)
+	rm.metrics.RecordAPICall("UPDATE", "RedriveExecution", err)
+	if err != nil {
+		return updated, err
+	}
+
+	ko.Status.ObservedRedriveGeneration = ko.Spec.RedriveGeneration
+	if resp.RedriveDate != nil {
+		ko.Status.RedriveDate = &metav1.Time{Time: *resp.RedriveDate}
+	}
+	// The execution is running again, its status is described on the next
+	// reconcile.
+	status := string(svcsdktypes.ExecutionStatusRunning)
+	ko.Status.Status = &status
+	ko.Status.StopDate = nil
+	return updated, nil
+}
+
+// redriveRequested returns true if Spec.RedriveGeneration was increased
+// since the execution was last redriven.
+func redriveRequested(ko *svcapitypes.Execution) bool {
+	if ko.Spec.RedriveGeneration == nil {
+		return false
+	}
+	observed := int64(0)
+	if ko.Status.ObservedRedriveGeneration != nil {
+		observed = *ko.Status.ObservedRedriveGeneration
+	}
+	return *ko.Spec.RedriveGeneration > observed
+}
+
+// setLatestRedriveGeneration sets the Spec.RedriveGeneration of the latest
+// observed resource to the last generation the execution was redriven for,
+// so that the delta with the desired resource triggers an update when a
+// redrive is requested.
+func setLatestRedriveGeneration(ko *svcapitypes.Execution) {
+	if redriveRequested(ko) {
+		ko.Spec.RedriveGeneration = ko.Status.ObservedRedriveGeneration
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package execution
+
+import (
+	"context"
+	"fmt"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	ackrt "github.com/aws-controllers-k8s/runtime/pkg/runtime"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+)
+
+// resolveReferenceForStateMachineQualifierRefs reads the StateMachineAlias
+// or StateMachineVersion referenced from the StateMachineAliasRef or
+// StateMachineVersionRef field and sets the StateMachineARN to its ARN.
+// Returns a boolean indicating whether a reference contains references, or
+// an error
+func (rm *resourceManager) resolveReferenceForStateMachineQualifierRefs(
+	ctx context.Context,
+	apiReader client.Reader,
+	ko *svcapitypes.Execution,
+) (hasReferences bool, err error) {
+	ref, field := ko.Spec.StateMachineAliasRef, "StateMachineAliasRef"
+	if ref == nil {
+		ref, field = ko.Spec.StateMachineVersionRef, "StateMachineVersionRef"
+	}
+	if ref == nil || ref.From == nil {
+		return false, nil
+	}
+	hasReferences = true
+	arr := ref.From
+	if arr.Name == nil || *arr.Name == "" {
+		return hasReferences, fmt.Errorf("provided resource reference is nil or empty: %s", field)
+	}
+	namespace, err := ackrt.ResolveCrossNamespaceReference(
+		ctx,
+		rm.cfg.EnableCrossNamespace,
+		&ko.Status.Conditions,
+		ackrt.CrossNamespaceRefKindResource,
+		ko.ObjectMeta.GetNamespace(),
+		arr.Namespace,
+		*arr.Name,
+	)
+	if err != nil {
+		return hasReferences, err
+	}
+	if ko.Spec.StateMachineAliasRef != nil {
+		obj := &svcapitypes.StateMachineAlias{}
+		if err := getReferencedResourceState_StateMachineAlias(ctx, apiReader, obj, *arr.Name, namespace); err != nil {
+			return hasReferences, err
+		}
+		ko.Spec.StateMachineARN = (*string)(obj.Status.ACKResourceMetadata.ARN)
+		return hasReferences, nil
+	}
+	obj := &svcapitypes.StateMachineVersion{}
+	if err := getReferencedResourceState_StateMachineVersion(ctx, apiReader, obj, *arr.Name, namespace); err != nil {
+		return hasReferences, err
+	}
+	ko.Spec.StateMachineARN = (*string)(obj.Status.ACKResourceMetadata.ARN)
+	return hasReferences, nil
+}
+
+// getReferencedResourceState_StateMachineAlias looks up whether a referenced resource
+// exists and is in a ACK.ResourceSynced=True state. If the referenced resource does exist and is
+// in a Synced state, returns nil, otherwise returns `ackerr.ResourceReferenceTerminalFor` or
+// `ResourceReferenceNotSyncedFor` depending on if the resource is in a Terminal state.
+func getReferencedResourceState_StateMachineAlias(
+	ctx context.Context,
+	apiReader client.Reader,
+	obj *svcapitypes.StateMachineAlias,
+	name string, // the Kubernetes name of the referenced resource
+	namespace string, // the Kubernetes namespace of the referenced resource
+) error {
+	namespacedName := types.NamespacedName{
+		Namespace: namespace,
+		Name:      name,
+	}
+	err := apiReader.Get(ctx, namespacedName, obj)
+	if err != nil {
+		return err
+	}
+	return checkReferencedResourceState("StateMachineAlias", obj.Status.Conditions, obj.Status.ACKResourceMetadata, name, namespace)
+}
+
+// getReferencedResourceState_StateMachineVersion looks up whether a referenced resource
+// exists and is in a ACK.ResourceSynced=True state. If the referenced resource does exist and is
+// in a Synced state, returns nil, otherwise returns `ackerr.ResourceReferenceTerminalFor` or
+// `ResourceReferenceNotSyncedFor` depending on if the resource is in a Terminal state.
+func getReferencedResourceState_StateMachineVersion(
+	ctx context.Context,
+	apiReader client.Reader,
+	obj *svcapitypes.StateMachineVersion,
+	name string, // the Kubernetes name of the referenced resource
+	namespace string, // the Kubernetes namespace of the referenced resource
+) error {
+	namespacedName := types.NamespacedName{
+		Namespace: namespace,
+		Name:      name,
+	}
+	err := apiReader.Get(ctx, namespacedName, obj)
+	if err != nil {
+		return err
+	}
+	return checkReferencedResourceState("StateMachineVersion", obj.Status.Conditions, obj.Status.ACKResourceMetadata, name, namespace)
+}
+
+// checkReferencedResourceState returns an error unless the referenced
+// resource is synced, not terminal and has an ARN.
+func checkReferencedResourceState(
+	kind string,
+	conditions []*ackv1alpha1.Condition,
+	metadata *ackv1alpha1.ResourceMetadata,
+	name string,
+	namespace string,
+) error {
+	for _, cond := range conditions {
+		if cond.Type == ackv1alpha1.ConditionTypeTerminal &&
+			cond.Status == corev1.ConditionTrue {
+			return ackerr.ResourceReferenceTerminalFor(
+				kind,
+				namespace, name)
+		}
+	}
+	var refResourceSynced bool
+	for _, cond := range conditions {
+		if cond.Type == ackv1alpha1.ConditionTypeResourceSynced &&
+			cond.Status == corev1.ConditionTrue {
+			refResourceSynced = true
+		}
+	}
+	if !refResourceSynced {
+		return ackerr.ResourceReferenceNotSyncedFor(
+			kind,
+			namespace, name)
+	}
+	if metadata == nil || metadata.ARN == nil {
+		return ackerr.ResourceReferenceMissingTargetFieldFor(
+			kind,
+			namespace, name,
+			"Status.ACKResourceMetadata.ARN")
+	}
+	return nil
+}
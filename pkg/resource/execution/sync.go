@@ -0,0 +1,104 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package execution
+
+import (
+	"context"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/sfn"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+)
+
+// executionModeSync starts the execution of an EXPRESS state machine with
+// StartSyncExecution, which returns once the execution completes.
+const executionModeSync = "Sync"
+
+// startSyncExecution starts the execution with StartSyncExecution and records
+// its outcome in the resource status. StartSyncExecution returns once the
+// execution completes, so the status, output and billing details are known
+// when the Execution is created, and there is nothing to poll afterwards.
+func (rm *resourceManager) startSyncExecution(
+	ctx context.Context,
+	desired *resource,
+) (created *resource, err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.startSyncExecution")
+	defer func() {
+		exit(err)
+	}()
+
+	input := &svcsdk.StartSyncExecutionInput{
+		Input:           desired.ko.Spec.Input,
+		Name:            desired.ko.Spec.Name,
+		StateMachineArn: desired.ko.Spec.StateMachineARN,
+		TraceHeader:     desired.ko.Spec.TraceHeader,
+	}
+	var resp *svcsdk.StartSyncExecutionOutput
+	resp, err = rm.sdkapi.StartSyncExecution(ctx, input)
+	rm.metrics.RecordAPICall("CREATE", "StartSyncExecution", err)
+	if err != nil {
+		return nil, err
+	}
+
+	ko := desired.ko.DeepCopy()
+
+	if ko.Status.ACKResourceMetadata == nil {
+		ko.Status.ACKResourceMetadata = &ackv1alpha1.ResourceMetadata{}
+	}
+	if resp.ExecutionArn != nil {
+		arn := ackv1alpha1.AWSResourceName(*resp.ExecutionArn)
+		ko.Status.ACKResourceMetadata.ARN = &arn
+	}
+	if resp.BillingDetails != nil {
+		ko.Status.BillingDetails = &svcapitypes.BillingDetails{
+			BilledDurationInMilliseconds: &resp.BillingDetails.BilledDurationInMilliseconds,
+			BilledMemoryUsedInMB:         &resp.BillingDetails.BilledMemoryUsedInMB,
+		}
+	} else {
+		ko.Status.BillingDetails = nil
+	}
+	ko.Status.Cause = resp.Cause
+	ko.Status.Error = resp.Error
+	ko.Status.Output = resp.Output
+	if resp.StartDate != nil {
+		ko.Status.StartDate = &metav1.Time{Time: *resp.StartDate}
+	} else {
+		ko.Status.StartDate = nil
+	}
+	if resp.Status != "" {
+		status := string(resp.Status)
+		ko.Status.Status = &status
+	} else {
+		ko.Status.Status = nil
+	}
+	if resp.StopDate != nil {
+		ko.Status.StopDate = &metav1.Time{Time: *resp.StopDate}
+	} else {
+		ko.Status.StopDate = nil
+	}
+
+	ko.Status.ObservedRedriveGeneration = ko.Spec.RedriveGeneration
+	rm.setStatusDefaults(ko)
+	return &resource{ko}, nil
+}
+
+// executionSyncMode returns true if the supplied Execution is started with
+// StartSyncExecution.
+func executionSyncMode(ko *svcapitypes.Execution) bool {
+	return ko.Spec.Mode != nil && *ko.Spec.Mode == executionModeSync
+}
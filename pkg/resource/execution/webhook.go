@@ -0,0 +1,253 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package execution
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackrtwebhook "github.com/aws-controllers-k8s/runtime/pkg/webhook"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlrt "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+)
+
+// +kubebuilder:webhook:path=/validate-sfn-services-k8s-aws-v1alpha1-execution,mutating=false,failurePolicy=fail,sideEffects=None,groups=sfn.services.k8s.aws,resources=executions,verbs=create,versions=v1alpha1,name=vexecution.sfn.services.k8s.aws,admissionReviewVersions=v1
+
+func init() {
+	if err := ackrtwebhook.RegisterWebhook(ackrtwebhook.New(
+		svcapitypes.GroupVersion.Version,
+		"Execution",
+		"validating",
+		setupValidatingWebhook,
+	)); err != nil {
+		panic(err)
+	}
+}
+
+// setupValidatingWebhook registers the Execution validating webhook with the
+// webhook server of the supplied manager. It is only called when the webhook
+// server is enabled.
+func setupValidatingWebhook(mgr ctrlrt.Manager) error {
+	return ctrlrt.NewWebhookManagedBy(
+		mgr, &svcapitypes.Execution{},
+	).WithValidator(
+		&validator{apiReader: mgr.GetAPIReader()},
+	).Complete()
+}
+
+// validator rejects Executions in Sync mode of STANDARD state machines, that
+// StartSyncExecution would otherwise reject once the Execution is created.
+type validator struct {
+	apiReader client.Reader
+}
+
+var _ admission.Validator[*svcapitypes.Execution] = &validator{}
+
+// ValidateCreate rejects the supplied Execution if it is in Sync mode and the
+// state machine it starts is a STANDARD state machine. The state machine is
+// looked up from StateMachineRef, StateMachineVersionRef or
+// StateMachineAliasRef, or from the StateMachine resource of the namespace of
+// the Execution whose ARN matches StateMachineARN. Executions whose state
+// machine type cannot be determined, e.g. of state machines not managed by a
+// StateMachine resource or when the lookup fails, are admitted with a
+// warning: they fail with a terminal error if the state machine turns out not
+// to be EXPRESS.
+func (v *validator) ValidateCreate(
+	ctx context.Context,
+	ko *svcapitypes.Execution,
+) (admission.Warnings, error) {
+	if !executionSyncMode(ko) {
+		return nil, nil
+	}
+	sm, err := v.getStateMachine(ctx, ko)
+	if err != nil {
+		return admission.Warnings{
+			fmt.Sprintf("unable to determine the type of the state machine of the Execution: %v", err),
+		}, nil
+	}
+	if sm == nil {
+		return nil, nil
+	}
+	if sm.Spec.Type == nil || *sm.Spec.Type == string(svcapitypes.StateMachineType_STANDARD) {
+		return nil, fmt.Errorf(
+			"mode Sync is only supported for EXPRESS state machines, StateMachine %s/%s is STANDARD",
+			sm.Namespace, sm.Name,
+		)
+	}
+	return nil, nil
+}
+
+// ValidateUpdate admits all updates, the fields validated on creation are
+// immutable.
+func (v *validator) ValidateUpdate(
+	ctx context.Context,
+	oldKo *svcapitypes.Execution,
+	newKo *svcapitypes.Execution,
+) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// ValidateDelete admits all deletions.
+func (v *validator) ValidateDelete(
+	ctx context.Context,
+	ko *svcapitypes.Execution,
+) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// getStateMachine returns the StateMachine the supplied Execution starts, or
+// nil if it cannot be found.
+func (v *validator) getStateMachine(
+	ctx context.Context,
+	ko *svcapitypes.Execution,
+) (*svcapitypes.StateMachine, error) {
+	switch {
+	case ko.Spec.StateMachineAliasRef != nil && ko.Spec.StateMachineAliasRef.From != nil:
+		alias := &svcapitypes.StateMachineAlias{}
+		found, err := v.get(ctx, ko.Namespace, ko.Spec.StateMachineAliasRef.From, alias)
+		if !found || err != nil {
+			return nil, err
+		}
+		return v.getAliasStateMachine(ctx, alias)
+	case ko.Spec.StateMachineVersionRef != nil && ko.Spec.StateMachineVersionRef.From != nil:
+		version := &svcapitypes.StateMachineVersion{}
+		found, err := v.get(ctx, ko.Namespace, ko.Spec.StateMachineVersionRef.From, version)
+		if !found || err != nil {
+			return nil, err
+		}
+		return v.getVersionStateMachine(ctx, version)
+	case ko.Spec.StateMachineRef != nil && ko.Spec.StateMachineRef.From != nil:
+		return v.getReferencedStateMachine(ctx, ko.Namespace, ko.Spec.StateMachineRef)
+	case ko.Spec.StateMachineARN != nil:
+		return v.getStateMachineByARN(ctx, ko.Namespace, *ko.Spec.StateMachineARN)
+	}
+	return nil, nil
+}
+
+// getAliasStateMachine returns the StateMachine the first routing entry of
+// the supplied StateMachineAlias routes to. All the entries route to
+// versions of the same state machine.
+func (v *validator) getAliasStateMachine(
+	ctx context.Context,
+	alias *svcapitypes.StateMachineAlias,
+) (*svcapitypes.StateMachine, error) {
+	if len(alias.Spec.RoutingConfiguration) == 0 || alias.Spec.RoutingConfiguration[0] == nil {
+		return nil, nil
+	}
+	item := alias.Spec.RoutingConfiguration[0]
+	switch {
+	case item.StateMachineVersionRef != nil && item.StateMachineVersionRef.From != nil:
+		version := &svcapitypes.StateMachineVersion{}
+		found, err := v.get(ctx, alias.Namespace, item.StateMachineVersionRef.From, version)
+		if !found || err != nil {
+			return nil, err
+		}
+		return v.getVersionStateMachine(ctx, version)
+	case item.StateMachineRef != nil && item.StateMachineRef.From != nil:
+		return v.getReferencedStateMachine(ctx, alias.Namespace, item.StateMachineRef)
+	case item.StateMachineVersionARN != nil:
+		return v.getStateMachineByARN(ctx, alias.Namespace, *item.StateMachineVersionARN)
+	}
+	return nil, nil
+}
+
+// getVersionStateMachine returns the StateMachine the supplied
+// StateMachineVersion was published from.
+func (v *validator) getVersionStateMachine(
+	ctx context.Context,
+	version *svcapitypes.StateMachineVersion,
+) (*svcapitypes.StateMachine, error) {
+	if ref := version.Spec.StateMachineRef; ref != nil && ref.From != nil {
+		return v.getReferencedStateMachine(ctx, version.Namespace, ref)
+	}
+	if version.Spec.StateMachineARN != nil {
+		return v.getStateMachineByARN(ctx, version.Namespace, *version.Spec.StateMachineARN)
+	}
+	return nil, nil
+}
+
+// getReferencedStateMachine returns the StateMachine referenced from the
+// supplied reference, or nil if it does not exist yet.
+func (v *validator) getReferencedStateMachine(
+	ctx context.Context,
+	namespace string,
+	ref *ackv1alpha1.AWSResourceReferenceWrapper,
+) (*svcapitypes.StateMachine, error) {
+	sm := &svcapitypes.StateMachine{}
+	found, err := v.get(ctx, namespace, ref.From, sm)
+	if !found || err != nil {
+		return nil, err
+	}
+	return sm, nil
+}
+
+// getStateMachineByARN returns the StateMachine resource of the supplied
+// namespace that manages the state machine with the supplied ARN, which may
+// be qualified with a version number or an alias name, or nil if there is
+// none. The StateMachines of other namespaces are not looked up, listing them
+// would read every StateMachine of the cluster on each admission.
+func (v *validator) getStateMachineByARN(
+	ctx context.Context,
+	namespace string,
+	arn string,
+) (*svcapitypes.StateMachine, error) {
+	// arn:partition:states:region:account:stateMachine:name[:qualifier]
+	parts := strings.Split(arn, ":")
+	if len(parts) < 7 {
+		return nil, nil
+	}
+	stateMachineARN := strings.Join(parts[:7], ":")
+	list := &svcapitypes.StateMachineList{}
+	if err := v.apiReader.List(ctx, list, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	for i := range list.Items {
+		metadata := list.Items[i].Status.ACKResourceMetadata
+		if metadata != nil && metadata.ARN != nil && string(*metadata.ARN) == stateMachineARN {
+			return &list.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// get reads the object referenced from the supplied reference into obj, and
+// returns whether it was found.
+func (v *validator) get(
+	ctx context.Context,
+	namespace string,
+	ref *ackv1alpha1.AWSResourceReference,
+	obj client.Object,
+) (bool, error) {
+	if ref.Name == nil || *ref.Name == "" {
+		return false, nil
+	}
+	if ref.Namespace != nil && *ref.Namespace != "" {
+		namespace = *ref.Namespace
+	}
+	err := v.apiReader.Get(ctx, types.NamespacedName{
+		Namespace: namespace,
+		Name:      *ref.Name,
+	}, obj)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
@@ -0,0 +1,102 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package execution
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+)
+
+const testStateMachineARN = "arn:aws:states:us-west-2:111111111111:stateMachine:hello"
+
+// standardStateMachine returns a STANDARD StateMachine of the supplied
+// namespace managing testStateMachineARN.
+func standardStateMachine(namespace string) *svcapitypes.StateMachine {
+	arn := ackv1alpha1.AWSResourceName(testStateMachineARN)
+	return &svcapitypes.StateMachine{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "hello"},
+		Spec:       svcapitypes.StateMachineSpec{Type: aws.String(string(svcapitypes.StateMachineType_STANDARD))},
+		Status: svcapitypes.StateMachineStatus{
+			ACKResourceMetadata: &ackv1alpha1.ResourceMetadata{ARN: &arn},
+		},
+	}
+}
+
+func TestValidateCreateStateMachineARN(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := svcapitypes.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		objects      []client.Object
+		listErr      error
+		wantErr      bool
+		wantWarnings int
+	}{
+		{
+			name:    "STANDARD state machine of the namespace",
+			objects: []client.Object{standardStateMachine("default")},
+			wantErr: true,
+		},
+		{
+			name:    "STANDARD state machine of another namespace",
+			objects: []client.Object{standardStateMachine("other")},
+		},
+		{
+			name:         "lookup failure",
+			listErr:      errors.New("etcdserver: request timed out"),
+			wantWarnings: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objects...)
+			if tt.listErr != nil {
+				builder = builder.WithInterceptorFuncs(interceptor.Funcs{
+					List: func(context.Context, client.WithWatch, client.ObjectList, ...client.ListOption) error {
+						return tt.listErr
+					},
+				})
+			}
+			v := &validator{apiReader: builder.Build()}
+			ko := &svcapitypes.Execution{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "run"},
+				Spec: svcapitypes.ExecutionSpec{
+					Mode:            aws.String(executionModeSync),
+					StateMachineARN: aws.String(testStateMachineARN),
+				},
+			}
+			warnings, err := v.ValidateCreate(context.TODO(), ko)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(warnings) != tt.wantWarnings {
+				t.Errorf("ValidateCreate() warnings = %v, want %d", warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
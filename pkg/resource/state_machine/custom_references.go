@@ -0,0 +1,126 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package state_machine
+
+import (
+	"context"
+	"errors"
+
+	iamapitypes "github.com/aws-controllers-k8s/iam-controller/apis/v1alpha1"
+	ackrt "github.com/aws-controllers-k8s/runtime/pkg/runtime"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+	commonutil "github.com/aws-controllers-k8s/sfn-controller/pkg/util"
+)
+
+// customReferenceResolvers resolve the fields of a StateMachine read from
+// other Kubernetes objects that are not references of generator.yaml, in
+// order: the definition must be read before ExecutionRoleGeneration
+// generates its permissions.
+var customReferenceResolvers = []func(
+	rm *resourceManager,
+	ctx context.Context,
+	apiReader client.Reader,
+	ko *svcapitypes.StateMachine,
+) (bool, error){
+	(*resourceManager).resolveReferenceForDefinitionFrom,
+	(*resourceManager).resolveReferenceForDefinitionSubstitutions,
+	(*resourceManager).resolveReferenceForResourceRefs,
+	(*resourceManager).resolveReferenceForLogGroupGeneration,
+	(*resourceManager).resolveReferenceForExecutionRoleGeneration,
+	(*resourceManager).resolveReferenceForRoleServiceAccountRef,
+}
+
+// resolveCustomReferences resolves the fields of the supplied StateMachine
+// read from other Kubernetes objects, and checks that the role of RoleRef,
+// resolved by ResolveReferences, can be assumed by Step Functions. It is
+// called whenever the StateMachine is read, before it is compared with or
+// sent to Step Functions. The values are resolved again on every call, into
+// the Definition and LoggingConfiguration.Destinations left unset by the
+// user, see the StateMachineSpec validation rules, or into the status, so
+// that ClearResolvedReferences does not need to know about them.
+func (rm *resourceManager) resolveCustomReferences(
+	ctx context.Context,
+	ko *svcapitypes.StateMachine,
+) error {
+	apiReader := commonutil.APIReader()
+	if apiReader == nil {
+		return errors.New("kubernetes client is not set, cannot resolve references")
+	}
+	ko.Status.ResolvedRoleARN = nil
+	for _, resolve := range customReferenceResolvers {
+		if _, err := resolve(rm, ctx, apiReader, ko); err != nil {
+			return err
+		}
+	}
+	return rm.checkRoleRefTrust(ctx, apiReader, ko)
+}
+
+// checkRoleRefTrust checks that the trust policy of the Role referenced from
+// RoleRef allows Step Functions to assume it. CreateStateMachine and
+// UpdateStateMachine fail with an opaque error otherwise.
+func (rm *resourceManager) checkRoleRefTrust(
+	ctx context.Context,
+	apiReader client.Reader,
+	ko *svcapitypes.StateMachine,
+) error {
+	if ko.Spec.RoleRef == nil || ko.Spec.RoleRef.From == nil || ko.Spec.RoleRef.From.Name == nil {
+		return nil
+	}
+	arr := ko.Spec.RoleRef.From
+	namespace, err := ackrt.ResolveCrossNamespaceReference(
+		ctx,
+		rm.cfg.EnableCrossNamespace,
+		&ko.Status.Conditions,
+		ackrt.CrossNamespaceRefKindResource,
+		ko.ObjectMeta.GetNamespace(),
+		arr.Namespace,
+		*arr.Name,
+	)
+	if err != nil {
+		return err
+	}
+	obj := &iamapitypes.Role{}
+	if err := getReferencedResourceState_Role(ctx, apiReader, obj, *arr.Name, namespace); err != nil {
+		return err
+	}
+	if err := checkRoleTrust(obj, string(rm.awsRegion), string(rm.awsAccountID)); err != nil {
+		return executionRoleTerminalError(ko, "ExecutionRoleNotTrusted", err)
+	}
+	return nil
+}
+
+// executionRoleARN returns the ARN of the execution role of the supplied
+// StateMachine: RoleARN, set by the user or resolved from RoleRef, or
+// Status.ResolvedRoleARN.
+func executionRoleARN(ko *svcapitypes.StateMachine) *string {
+	if ko.Spec.RoleARN != nil {
+		return ko.Spec.RoleARN
+	}
+	return ko.Status.ResolvedRoleARN
+}
+
+// setLatestRoleARN clears the RoleARN read from Step Functions when it is the
+// Status.ResolvedRoleARN of desired, which has no RoleARN, so that they
+// compare equal.
+func setLatestRoleARN(desired, latest *svcapitypes.StateMachine) {
+	if desired.Spec.RoleARN != nil || desired.Status.ResolvedRoleARN == nil {
+		return
+	}
+	if aws.ToString(latest.Spec.RoleARN) == *desired.Status.ResolvedRoleARN {
+		latest.Spec.RoleARN = nil
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package state_machine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+)
+
+// definitionPlaceholder matches the ${Key} placeholders of a definition.
+var definitionPlaceholder = regexp.MustCompile(`\$\{([A-Za-z0-9_.:-]+)\}`)
+
+// resolveDefinition returns the definition of the supplied StateMachine as
+// sent to Step Functions. When Spec.DefinitionObject is used, it is
+// serialized canonically, that is as compact JSON with sorted keys. When
+// Spec.DefinitionSubstitutions or Spec.ResourceRefs is set, the placeholders
+// are replaced with the values resolved in Status.DefinitionSubstitutionValues.
+func resolveDefinition(ko *svcapitypes.StateMachine) (*string, error) {
+	definition := ko.Spec.Definition
+	if ko.Spec.DefinitionObject != nil {
+		if ko.Spec.Definition != nil {
+			return nil, ackerr.NewTerminalError(errors.New(
+				"only one of definition or definitionObject can be set",
+			))
+		}
+		canonical, err := canonicalDefinition(ko.Spec.DefinitionObject.Raw)
+		if err != nil {
+			return nil, ackerr.NewTerminalError(fmt.Errorf("invalid definitionObject: %w", err))
+		}
+		definition = &canonical
+	}
+	if definition == nil || (len(ko.Spec.DefinitionSubstitutions) == 0 && len(ko.Spec.ResourceRefs) == 0) {
+		return definition, nil
+	}
+	rendered, err := substituteDefinition(*definition, ko.Status.DefinitionSubstitutionValues)
+	if err != nil {
+		return nil, ackerr.NewTerminalError(err)
+	}
+	return &rendered, nil
+}
+
+// substituteDefinition replaces the ${Key} placeholders of the definition
+// with the supplied values. Values are JSON escaped when the placeholder is
+// inside a string literal, and must be a JSON scalar otherwise, so that they
+// cannot change the structure of the definition. It returns an error listing
+// the placeholders that have no value or an invalid one.
+func substituteDefinition(definition string, values map[string]*string) (string, error) {
+	unresolved := []string{}
+	invalid := []string{}
+	var rendered strings.Builder
+	inString, escaped := false, false
+	pos := 0
+	for _, match := range definitionPlaceholder.FindAllStringSubmatchIndex(definition, -1) {
+		inString, escaped = scanStringLiteral(definition[pos:match[0]], inString, escaped)
+		rendered.WriteString(definition[pos:match[0]])
+		pos = match[1]
+		placeholder := definition[match[0]:match[1]]
+		value, ok := values[definition[match[2]:match[3]]]
+		switch {
+		case !ok || value == nil:
+			unresolved = append(unresolved, placeholder)
+			rendered.WriteString(placeholder)
+		case inString:
+			rendered.WriteString(escapeJSONString(*value))
+		case isJSONScalar(*value):
+			rendered.WriteString(*value)
+		default:
+			invalid = append(invalid, placeholder)
+			rendered.WriteString(placeholder)
+		}
+	}
+	rendered.WriteString(definition[pos:])
+	if len(unresolved) > 0 {
+		sort.Strings(unresolved)
+		return "", fmt.Errorf("unresolved definition substitutions: %s", strings.Join(unresolved, ", "))
+	}
+	if len(invalid) > 0 {
+		sort.Strings(invalid)
+		return "", fmt.Errorf(
+			"definition substitutions used outside of a JSON string must be a JSON number, string, boolean or null: %s",
+			strings.Join(invalid, ", "),
+		)
+	}
+	return rendered.String(), nil
+}
+
+// scanStringLiteral returns whether the end of s is inside a JSON string
+// literal, and right after a backslash in it, given the state at its start.
+func scanStringLiteral(s string, inString, escaped bool) (bool, bool) {
+	for i := 0; i < len(s); i++ {
+		switch {
+		case !inString:
+			inString = s[i] == '"'
+		case escaped:
+			escaped = false
+		case s[i] == '\\':
+			escaped = true
+		case s[i] == '"':
+			inString = false
+		}
+	}
+	return inString, escaped
+}
+
+// escapeJSONString returns the supplied value encoded as the content of a
+// JSON string literal, without the surrounding quotes.
+func escapeJSONString(value string) string {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(value)
+	encoded := strings.TrimSuffix(b.String(), "\n")
+	return encoded[1 : len(encoded)-1]
+}
+
+// isJSONScalar returns true if the supplied value is a single JSON number,
+// string, boolean or null.
+func isJSONScalar(value string) bool {
+	var v interface{}
+	if err := json.Unmarshal([]byte(value), &v); err != nil {
+		return false
+	}
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		return false
+	}
+	return true
+}
+
+// canonicalDefinition re-encodes a JSON document so that equal documents
+// have the same serialization.
+func canonicalDefinition(raw []byte) (string, error) {
+	var doc interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// setLatestDefinition is called after the definition read from Step
+// Functions is set in Spec.Definition of latest. If it matches the rendered
+// definition of desired, the definition fields of desired are kept so that
+// templates and definitionObject are not replaced by the rendered document.
+// Otherwise, the definition is moved into Spec.DefinitionObject when desired
+// uses it, so that both are compared on the same field.
+func setLatestDefinition(desired, latest *svcapitypes.StateMachine) {
+	if latest.Spec.Definition == nil {
+		return
+	}
+	rendered, err := resolveDefinition(desired)
+	if err == nil && equalDocument(rendered, latest.Spec.Definition) {
+		latest.Spec.Definition = desired.Spec.Definition
+		latest.Spec.DefinitionObject = desired.Spec.DefinitionObject
+		return
+	}
+	if desired.Spec.DefinitionObject != nil {
+		latest.Spec.DefinitionObject = &runtime.RawExtension{
+			Raw: []byte(*latest.Spec.Definition),
+		}
+		latest.Spec.Definition = nil
+	}
+}
+
+// equalRenderedDefinition returns true if both resources render to the same
+// JSON document. A definition that cannot be rendered is never equal.
+func equalRenderedDefinition(a, b *svcapitypes.StateMachine) bool {
+	renderedA, err := resolveDefinition(a)
+	if err != nil {
+		return false
+	}
+	renderedB, err := resolveDefinition(b)
+	if err != nil {
+		return false
+	}
+	return equalDocument(renderedA, renderedB)
+}
+
+// equalDocument returns true if both strings hold the same JSON document.
+func equalDocument(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	equal, err := ackcompare.DocumentEqual(*a, *b)
+	return err == nil && equal
+}
@@ -0,0 +1,304 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package state_machine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlrt "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+	commonutil "github.com/aws-controllers-k8s/sfn-controller/pkg/util"
+)
+
+const (
+	// definitionSourceIndexKey indexes StateMachines by the ConfigMaps and
+	// Secrets their definition is read from, as "<kind>/<name>".
+	definitionSourceIndexKey = "spec.definitionFrom"
+
+	definitionSourceConfigMap    = "ConfigMap"
+	definitionSourceSecret       = "Secret"
+	definitionSourceActivity     = "Activity"
+	definitionSourceStateMachine = "StateMachine"
+)
+
+// fieldRefKinds are the kinds of the objects DefinitionSubstitutions can read
+// a field of, by GroupVersionKind. The controller has RBAC permissions to
+// get them and watches them, see SetupDefinitionSourceWatch.
+var fieldRefKinds = map[schema.GroupVersionKind]string{
+	corev1.SchemeGroupVersion.WithKind(definitionSourceConfigMap):   definitionSourceConfigMap,
+	svcapitypes.GroupVersion.WithKind(definitionSourceActivity):     definitionSourceActivity,
+	svcapitypes.GroupVersion.WithKind(definitionSourceStateMachine): definitionSourceStateMachine,
+}
+
+// resolveReferenceForDefinitionFrom reads the ConfigMap or Secret key
+// referenced from the DefinitionFrom field and sets the Definition from its
+// value. Returns a boolean indicating whether a reference contains
+// references, or an error
+func (rm *resourceManager) resolveReferenceForDefinitionFrom(
+	ctx context.Context,
+	apiReader client.Reader,
+	ko *svcapitypes.StateMachine,
+) (hasReferences bool, err error) {
+	if ko.Spec.DefinitionFrom == nil {
+		return false, nil
+	}
+	kind, selector := definitionSourceSelector(ko.Spec.DefinitionFrom)
+	if selector == nil || selector.Name == nil || selector.Key == nil {
+		return true, fmt.Errorf("provided definition source is nil or empty: DefinitionFrom")
+	}
+	namespace := ko.ObjectMeta.GetNamespace()
+	var definition string
+	if kind == definitionSourceConfigMap {
+		definition, err = commonutil.GetConfigMapKey(ctx, apiReader, namespace, selector)
+	} else {
+		definition, err = commonutil.GetSecretKey(ctx, apiReader, namespace, selector)
+	}
+	if err != nil {
+		return true, err
+	}
+	ko.Spec.Definition = &definition
+	return true, nil
+}
+
+// resolveReferenceForDefinitionSubstitutions resolves the values of the
+// DefinitionSubstitutions field into Status.DefinitionSubstitutionValues.
+// Returns a boolean indicating whether a substitution reads a ConfigMap or
+// another Kubernetes object, or an error
+func (rm *resourceManager) resolveReferenceForDefinitionSubstitutions(
+	ctx context.Context,
+	apiReader client.Reader,
+	ko *svcapitypes.StateMachine,
+) (hasReferences bool, err error) {
+	ko.Status.DefinitionSubstitutionValues = nil
+	if len(ko.Spec.DefinitionSubstitutions) == 0 {
+		return false, nil
+	}
+	namespace := ko.ObjectMeta.GetNamespace()
+	values := make(map[string]*string, len(ko.Spec.DefinitionSubstitutions))
+	for key, substitution := range ko.Spec.DefinitionSubstitutions {
+		if substitution == nil {
+			return hasReferences, fmt.Errorf("provided definition substitution is nil: DefinitionSubstitutions.%s", key)
+		}
+		var value string
+		switch {
+		case substitution.Value != nil:
+			value = *substitution.Value
+		case substitution.ConfigMapKeyRef != nil:
+			hasReferences = true
+			value, err = commonutil.GetConfigMapKey(ctx, apiReader, namespace, substitution.ConfigMapKeyRef)
+		case substitution.FieldRef != nil:
+			hasReferences = true
+			value, err = getObjectFieldValue(ctx, apiReader, namespace, substitution.FieldRef)
+		default:
+			err = fmt.Errorf("provided definition substitution is empty: DefinitionSubstitutions.%s", key)
+		}
+		if err != nil {
+			return hasReferences, err
+		}
+		values[key] = &value
+	}
+	ko.Status.DefinitionSubstitutionValues = values
+	return hasReferences, nil
+}
+
+// getObjectFieldValue returns the value of a scalar field of a Kubernetes
+// object of one of the fieldRefKinds.
+func getObjectFieldValue(
+	ctx context.Context,
+	apiReader client.Reader,
+	namespace string,
+	selector *svcapitypes.DefinitionFieldSelector,
+) (string, error) {
+	if selector.APIVersion == nil || selector.Kind == nil || selector.Name == nil || selector.FieldPath == nil {
+		return "", fmt.Errorf("provided field selector is nil or empty")
+	}
+	gvk, err := fieldRefGroupVersionKind(selector)
+	if err != nil {
+		return "", err
+	}
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	namespacedName := types.NamespacedName{
+		Namespace: namespace,
+		Name:      *selector.Name,
+	}
+	if err := apiReader.Get(ctx, namespacedName, obj); err != nil {
+		return "", err
+	}
+	path := strings.Split(strings.TrimPrefix(*selector.FieldPath, "."), ".")
+	field, found, err := unstructured.NestedFieldNoCopy(obj.Object, path...)
+	if err != nil || !found || field == nil {
+		return "", ackerr.ResourceReferenceMissingTargetFieldFor(
+			*selector.Kind,
+			namespace, *selector.Name,
+			*selector.FieldPath)
+	}
+	switch v := field.(type) {
+	case string, bool, int64, float64:
+		return fmt.Sprint(v), nil
+	default:
+		return "", ackerr.NewTerminalError(fmt.Errorf(
+			"field %s of %s %s/%s is not a string, number or boolean",
+			*selector.FieldPath, *selector.Kind, namespace, *selector.Name,
+		))
+	}
+}
+
+// fieldRefGroupVersionKind returns the GroupVersionKind of the object
+// selected by a fieldRef, or a terminal error if it is not one of the
+// fieldRefKinds.
+func fieldRefGroupVersionKind(
+	selector *svcapitypes.DefinitionFieldSelector,
+) (schema.GroupVersionKind, error) {
+	gv, err := schema.ParseGroupVersion(aws.ToString(selector.APIVersion))
+	if err != nil {
+		return schema.GroupVersionKind{}, ackerr.NewTerminalError(err)
+	}
+	gvk := gv.WithKind(aws.ToString(selector.Kind))
+	if _, ok := fieldRefKinds[gvk]; !ok {
+		return gvk, ackerr.NewTerminalError(fmt.Errorf(
+			"fieldRef cannot read %s %s, only ConfigMaps and %s Activities and StateMachines are supported",
+			gvk.GroupVersion(), gvk.Kind, svcapitypes.GroupVersion,
+		))
+	}
+	return gvk, nil
+}
+
+// definitionSourceSelector returns the kind and key selector of the object
+// the definition is read from.
+func definitionSourceSelector(
+	source *svcapitypes.DefinitionSource,
+) (string, *svcapitypes.DefinitionKeySelector) {
+	if source.ConfigMapKeyRef != nil {
+		return definitionSourceConfigMap, source.ConfigMapKeyRef
+	}
+	return definitionSourceSecret, source.SecretKeyRef
+}
+
+// SetupDefinitionSourceWatch makes the supplied StateMachine reconciler run
+// whenever a ConfigMap or Secret referenced from Spec.DefinitionFrom or
+// Spec.DefinitionSubstitutions, an object whose field is substituted in the
+// definition, or the ServiceAccount referenced from
+// Spec.RoleServiceAccountRef, changes, so that edits are applied without
+// touching the StateMachine itself. Only the metadata of these objects is
+// cached, their data is read when references are resolved. The reconciles
+// of this controller run alongside those of the ACK runtime controller, the
+// updates of a StateMachine are serialized by updateLocks.
+func SetupDefinitionSourceWatch(
+	mgr ctrlrt.Manager,
+	reconciler reconcile.Reconciler,
+) error {
+	err := mgr.GetFieldIndexer().IndexField(
+		context.Background(),
+		&svcapitypes.StateMachine{},
+		definitionSourceIndexKey,
+		func(obj client.Object) []string {
+			ko, ok := obj.(*svcapitypes.StateMachine)
+			if !ok {
+				return nil
+			}
+			keys := []string{}
+			if ko.Spec.DefinitionFrom != nil {
+				kind, selector := definitionSourceSelector(ko.Spec.DefinitionFrom)
+				if selector != nil && selector.Name != nil {
+					keys = append(keys, kind+"/"+*selector.Name)
+				}
+			}
+			for _, substitution := range ko.Spec.DefinitionSubstitutions {
+				if substitution == nil {
+					continue
+				}
+				if substitution.ConfigMapKeyRef != nil && substitution.ConfigMapKeyRef.Name != nil {
+					keys = append(keys, definitionSourceConfigMap+"/"+*substitution.ConfigMapKeyRef.Name)
+				}
+				if substitution.FieldRef != nil && substitution.FieldRef.Name != nil {
+					if gvk, err := fieldRefGroupVersionKind(substitution.FieldRef); err == nil {
+						keys = append(keys, fieldRefKinds[gvk]+"/"+*substitution.FieldRef.Name)
+					}
+				}
+			}
+			if ref := ko.Spec.RoleServiceAccountRef; ref != nil && ref.Name != nil {
+				keys = append(keys, definitionSourceServiceAccount+"/"+*ref.Name)
+			}
+			return keys
+		},
+	)
+	if err != nil {
+		return err
+	}
+	return ctrlrt.NewControllerManagedBy(
+		mgr,
+	).Named(
+		"statemachine-definition-source",
+	).Watches(
+		&corev1.ConfigMap{},
+		handler.EnqueueRequestsFromMapFunc(mapDefinitionSource(mgr.GetClient(), definitionSourceConfigMap)),
+		builder.OnlyMetadata,
+	).Watches(
+		&corev1.Secret{},
+		handler.EnqueueRequestsFromMapFunc(mapDefinitionSource(mgr.GetClient(), definitionSourceSecret)),
+		builder.OnlyMetadata,
+	).Watches(
+		&corev1.ServiceAccount{},
+		handler.EnqueueRequestsFromMapFunc(mapDefinitionSource(mgr.GetClient(), definitionSourceServiceAccount)),
+		builder.OnlyMetadata,
+	).Watches(
+		&svcapitypes.Activity{},
+		handler.EnqueueRequestsFromMapFunc(mapDefinitionSource(mgr.GetClient(), definitionSourceActivity)),
+		builder.OnlyMetadata,
+	).Watches(
+		&svcapitypes.StateMachine{},
+		handler.EnqueueRequestsFromMapFunc(mapDefinitionSource(mgr.GetClient(), definitionSourceStateMachine)),
+		builder.OnlyMetadata,
+	).Complete(reconciler)
+}
+
+// mapDefinitionSource returns a handler.MapFunc enqueuing the StateMachines
+// whose definition is read from the changed object.
+func mapDefinitionSource(kc client.Client, kind string) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		list := &svcapitypes.StateMachineList{}
+		err := kc.List(
+			ctx, list,
+			client.InNamespace(obj.GetNamespace()),
+			client.MatchingFields{definitionSourceIndexKey: kind + "/" + obj.GetName()},
+		)
+		if err != nil {
+			return nil
+		}
+		requests := make([]reconcile.Request, 0, len(list.Items))
+		for _, ko := range list.Items {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: ko.Namespace,
+					Name:      ko.Name,
+				},
+			})
+		}
+		return requests
+	}
+}
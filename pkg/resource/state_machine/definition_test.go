@@ -0,0 +1,186 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package state_machine
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+)
+
+func TestSubstituteDefinition(t *testing.T) {
+	values := map[string]*string{
+		"Greeting":  aws.String("Hello"),
+		"Quoted":    aws.String(`Say "Hello"`),
+		"Injection": aws.String(`x", "Next": "Other`),
+		"Backslash": aws.String(`C:\path`),
+		"Unicode":   aws.String("<é>\n"),
+		"Seconds":   aws.String("30"),
+		"Enabled":   aws.String("true"),
+		"String":    aws.String(`"quoted"`),
+		"Object":    aws.String(`{"a": 1}`),
+		"Empty":     aws.String(""),
+		"Nil":       nil,
+	}
+	tests := []struct {
+		name       string
+		definition string
+		want       string
+		// wantErr is a substring of the expected error, or empty if the
+		// definition renders.
+		wantErr string
+	}{
+		{
+			name:       "no placeholders",
+			definition: `{"Result": "Hello"}`,
+			want:       `{"Result": "Hello"}`,
+		},
+		{
+			name:       "placeholder in a string",
+			definition: `{"Result": "${Greeting} world"}`,
+			want:       `{"Result": "Hello world"}`,
+		},
+		{
+			name:       "placeholder in a key",
+			definition: `{"${Greeting}": true}`,
+			want:       `{"Hello": true}`,
+		},
+		{
+			name:       "quotes are escaped",
+			definition: `{"Result": "${Quoted}"}`,
+			want:       `{"Result": "Say \"Hello\""}`,
+		},
+		{
+			name:       "structure cannot be injected",
+			definition: `{"Next": "${Injection}"}`,
+			want:       `{"Next": "x\", \"Next\": \"Other"}`,
+		},
+		{
+			name:       "backslashes are escaped",
+			definition: `{"Result": "${Backslash}"}`,
+			want:       `{"Result": "C:\\path"}`,
+		},
+		{
+			name:       "control characters are escaped, HTML characters are not",
+			definition: `{"Result": "${Unicode}"}`,
+			want:       `{"Result": "<é>\n"}`,
+		},
+		{
+			name:       "empty value",
+			definition: `{"Result": "${Empty}"}`,
+			want:       `{"Result": ""}`,
+		},
+		{
+			name:       "after an escaped quote",
+			definition: `{"Result": "\"${Quoted}\""}`,
+			want:       `{"Result": "\"Say \"Hello\"\""}`,
+		},
+		{
+			name:       "after an escaped backslash",
+			definition: `{"Result": "\\", "Next": ${Seconds}}`,
+			want:       `{"Result": "\\", "Next": 30}`,
+		},
+		{
+			name:       "number outside of a string",
+			definition: `{"Seconds": ${Seconds}}`,
+			want:       `{"Seconds": 30}`,
+		},
+		{
+			name:       "boolean outside of a string",
+			definition: `{"Enabled": ${Enabled}}`,
+			want:       `{"Enabled": true}`,
+		},
+		{
+			name:       "JSON string outside of a string",
+			definition: `{"Result": ${String}}`,
+			want:       `{"Result": "quoted"}`,
+		},
+		{
+			name:       "object outside of a string",
+			definition: `{"Result": ${Object}}`,
+			wantErr:    "must be a JSON number, string, boolean or null: ${Object}",
+		},
+		{
+			name:       "text outside of a string",
+			definition: `{"Result": ${Greeting}}`,
+			wantErr:    "must be a JSON number, string, boolean or null: ${Greeting}",
+		},
+		{
+			name:       "unresolved placeholders",
+			definition: `{"A": "${Missing}", "B": "${Nil}", "C": "${Greeting}"}`,
+			wantErr:    "unresolved definition substitutions: ${Missing}, ${Nil}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := substituteDefinition(tt.definition, values)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("substituteDefinition() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("substituteDefinition() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("substituteDefinition() = %s, want %s", got, tt.want)
+			}
+			if !json.Valid([]byte(got)) {
+				t.Errorf("substituteDefinition() = %s, not valid JSON", got)
+			}
+		})
+	}
+}
+
+func TestFieldRefGroupVersionKind(t *testing.T) {
+	tests := []struct {
+		apiVersion string
+		kind       string
+		wantErr    bool
+	}{
+		{apiVersion: "v1", kind: "ConfigMap"},
+		{apiVersion: "sfn.services.k8s.aws/v1alpha1", kind: "Activity"},
+		{apiVersion: "sfn.services.k8s.aws/v1alpha1", kind: "StateMachine"},
+		{apiVersion: "v1", kind: "Secret", wantErr: true},
+		{apiVersion: "v1", kind: "Pod", wantErr: true},
+		{apiVersion: "sfn.services.k8s.aws/v1alpha1", kind: "Execution", wantErr: true},
+		{apiVersion: "lambda.services.k8s.aws/v1alpha1", kind: "Function", wantErr: true},
+		{apiVersion: "a/b/c", kind: "ConfigMap", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.apiVersion+" "+tt.kind, func(t *testing.T) {
+			gvk, err := fieldRefGroupVersionKind(&svcapitypes.DefinitionFieldSelector{
+				APIVersion: aws.String(tt.apiVersion),
+				Kind:       aws.String(tt.kind),
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("fieldRefGroupVersionKind() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var terminal *ackerr.TerminalError
+			if err != nil && !errors.As(err, &terminal) {
+				t.Errorf("fieldRefGroupVersionKind() error = %v, want a terminal error", err)
+			}
+			if err == nil && gvk.Kind != tt.kind {
+				t.Errorf("fieldRefGroupVersionKind() = %v, want kind %s", gvk, tt.kind)
+			}
+		})
+	}
+}
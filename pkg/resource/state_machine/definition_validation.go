@@ -0,0 +1,231 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package state_machine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/sfn"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/sfn/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+	"github.com/aws-controllers-k8s/sfn-controller/pkg/asl/lint"
+)
+
+// ConditionTypeDefinitionValid reports the outcome of the pre-flight
+// validation of Spec.Definition.
+const ConditionTypeDefinitionValid ackv1alpha1.ConditionType = "DefinitionValid"
+
+// validateDefinition checks the definition of the supplied resource, first
+// offline with the ASL linter, unless Spec.DefinitionValidation disables it,
+// and, if the linter found nothing blocking, with the
+// ValidateStateMachineDefinition API. It records the diagnostics in
+// Status.DefinitionDiagnostics and the DefinitionValid condition, and returns
+// a terminal error if any diagnostic is at or above the severity threshold
+// configured in Spec.DefinitionValidation.
+func (rm *resourceManager) validateDefinition(
+	ctx context.Context,
+	r *resource,
+) (err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.validateDefinition")
+	defer func() {
+		exit(err)
+	}()
+
+	threshold := svcsdktypes.ValidateStateMachineDefinitionSeverityError
+	if r.ko.Spec.DefinitionValidation != nil && r.ko.Spec.DefinitionValidation.SeverityThreshold != nil {
+		threshold = svcsdktypes.ValidateStateMachineDefinitionSeverity(*r.ko.Spec.DefinitionValidation.SeverityThreshold)
+	}
+	if severityRank(threshold) == 0 {
+		return ackerr.NewTerminalError(fmt.Errorf(
+			"invalid definitionValidation.severityThreshold %q, must be one of %s or %s",
+			threshold,
+			svcsdktypes.ValidateStateMachineDefinitionSeverityError,
+			svcsdktypes.ValidateStateMachineDefinitionSeverityWarning,
+		))
+	}
+
+	definition, err := resolveDefinition(r.ko)
+	if err != nil {
+		return err
+	}
+	diagnostics := []svcsdktypes.ValidateStateMachineDefinitionDiagnostic{}
+	lintDiagnostics := []lint.Diagnostic{}
+	if r.ko.Spec.DefinitionValidation == nil || !aws.ToBool(r.ko.Spec.DefinitionValidation.DisableLinter) {
+		lintDiagnostics = lint.Lint(aws.ToString(definition))
+	}
+	for _, d := range lintDiagnostics {
+		diagnostic := svcsdktypes.ValidateStateMachineDefinitionDiagnostic{
+			Code:     aws.String(d.Code),
+			Message:  aws.String(d.Message),
+			Severity: svcsdktypes.ValidateStateMachineDefinitionSeverity(d.Severity),
+		}
+		if d.Location != "" {
+			diagnostic.Location = aws.String(d.Location)
+		}
+		diagnostics = append(diagnostics, diagnostic)
+	}
+	if !blocksDefinition(diagnostics, threshold) {
+		input := &svcsdk.ValidateStateMachineDefinitionInput{
+			Definition: definition,
+			// Always ask for warnings so that they are reported in the status
+			// even when they do not block the write.
+			Severity: svcsdktypes.ValidateStateMachineDefinitionSeverityWarning,
+		}
+		if r.ko.Spec.Type != nil {
+			input.Type = svcsdktypes.StateMachineType(*r.ko.Spec.Type)
+		}
+		resp, err := rm.sdkapi.ValidateStateMachineDefinition(ctx, input)
+		rm.metrics.RecordAPICall("READ_ONE", "ValidateStateMachineDefinition", err)
+		if err != nil {
+			return err
+		}
+		diagnostics = append(diagnostics, resp.Diagnostics...)
+	}
+
+	statusDiagnostics := make([]*svcapitypes.ValidateStateMachineDefinitionDiagnostic, 0, len(diagnostics))
+	for _, d := range diagnostics {
+		statusDiagnostics = append(statusDiagnostics, &svcapitypes.ValidateStateMachineDefinitionDiagnostic{
+			Code:     d.Code,
+			Location: d.Location,
+			Message:  d.Message,
+			Severity: aws.String(string(d.Severity)),
+		})
+	}
+	r.ko.Status.DefinitionDiagnostics = statusDiagnostics
+	return setDefinitionValidCondition(r.ko)
+}
+
+// setDefinitionValidCondition sets the DefinitionValid condition of the
+// supplied StateMachine from Status.DefinitionDiagnostics, and returns a
+// terminal error if any of them is at or above the severity threshold. The
+// runtime clears the conditions at the start of each reconcile, while the
+// diagnostics of the last validated definition are kept in the status, so
+// the condition is set again from them each time the resource is read.
+func setDefinitionValidCondition(ko *svcapitypes.StateMachine) error {
+	threshold := svcsdktypes.ValidateStateMachineDefinitionSeverityError
+	if ko.Spec.DefinitionValidation != nil && ko.Spec.DefinitionValidation.SeverityThreshold != nil {
+		threshold = svcsdktypes.ValidateStateMachineDefinitionSeverity(*ko.Spec.DefinitionValidation.SeverityThreshold)
+	}
+	// validateDefinition rejects invalid thresholds before the diagnostics
+	// are recorded.
+	if severityRank(threshold) == 0 {
+		return nil
+	}
+	blocking := []string{}
+	warnings := 0
+	for _, d := range ko.Status.DefinitionDiagnostics {
+		if d == nil {
+			continue
+		}
+		diagnostic := svcsdktypes.ValidateStateMachineDefinitionDiagnostic{
+			Code:     d.Code,
+			Location: d.Location,
+			Message:  d.Message,
+			Severity: svcsdktypes.ValidateStateMachineDefinitionSeverity(aws.ToString(d.Severity)),
+		}
+		if severityRank(diagnostic.Severity) >= severityRank(threshold) {
+			blocking = append(blocking, formatDiagnostic(diagnostic))
+		} else {
+			warnings++
+		}
+	}
+
+	if len(blocking) > 0 {
+		msg := fmt.Sprintf("definition validation failed: %s", strings.Join(blocking, "; "))
+		setCondition(ko, ConditionTypeDefinitionValid, corev1.ConditionFalse, "ValidationFailed", msg)
+		return ackerr.NewTerminalError(errors.New(msg))
+	}
+	msg := "definition is valid"
+	if warnings > 0 {
+		msg = fmt.Sprintf("definition is valid with %d warning(s)", warnings)
+	}
+	setCondition(ko, ConditionTypeDefinitionValid, corev1.ConditionTrue, "ValidationSucceeded", msg)
+	return nil
+}
+
+// blocksDefinition returns true if any of the supplied diagnostics is at or
+// above the severity threshold.
+func blocksDefinition(
+	diagnostics []svcsdktypes.ValidateStateMachineDefinitionDiagnostic,
+	threshold svcsdktypes.ValidateStateMachineDefinitionSeverity,
+) bool {
+	for _, d := range diagnostics {
+		if severityRank(d.Severity) >= severityRank(threshold) {
+			return true
+		}
+	}
+	return false
+}
+
+// severityRank orders the diagnostic severities, returning 0 for unknown
+// values.
+func severityRank(severity svcsdktypes.ValidateStateMachineDefinitionSeverity) int {
+	switch severity {
+	case svcsdktypes.ValidateStateMachineDefinitionSeverityError:
+		return 2
+	case svcsdktypes.ValidateStateMachineDefinitionSeverityWarning:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// formatDiagnostic returns a single line description of a diagnostic.
+func formatDiagnostic(d svcsdktypes.ValidateStateMachineDefinitionDiagnostic) string {
+	s := fmt.Sprintf("%s %s: %s", d.Severity, aws.ToString(d.Code), aws.ToString(d.Message))
+	if d.Location != nil {
+		s = fmt.Sprintf("%s (at %s)", s, *d.Location)
+	}
+	return s
+}
+
+// setCondition sets the condition of the supplied type on the supplied
+// StateMachine, only moving LastTransitionTime when the status changes.
+func setCondition(
+	ko *svcapitypes.StateMachine,
+	conditionType ackv1alpha1.ConditionType,
+	status corev1.ConditionStatus,
+	reason string,
+	message string,
+) {
+	var cond *ackv1alpha1.Condition
+	for _, c := range ko.Status.Conditions {
+		if c.Type == conditionType {
+			cond = c
+			break
+		}
+	}
+	if cond == nil {
+		cond = &ackv1alpha1.Condition{Type: conditionType}
+		ko.Status.Conditions = append(ko.Status.Conditions, cond)
+	}
+	if cond.Status != status {
+		now := metav1.Now()
+		cond.LastTransitionTime = &now
+	}
+	cond.Status = status
+	cond.Reason = &reason
+	cond.Message = &message
+}
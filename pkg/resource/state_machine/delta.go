@@ -43,11 +43,22 @@ func newResourceDelta(
 	}
 	customPreCompare(delta, a, b)
 
-	if ackcompare.HasNilDifference(a.ko.Spec.Definition, b.ko.Spec.Definition) {
-		delta.Add("Spec.Definition", a.ko.Spec.Definition, b.ko.Spec.Definition)
-	} else if a.ko.Spec.Definition != nil && b.ko.Spec.Definition != nil {
-		if equal, err := ackcompare.DocumentEqual(*a.ko.Spec.Definition, *b.ko.Spec.Definition); err != nil || !equal {
-			delta.Add("Spec.Definition", a.ko.Spec.Definition, b.ko.Spec.Definition)
+	if ackcompare.HasNilDifference(a.ko.Spec.DefinitionValidation, b.ko.Spec.DefinitionValidation) {
+		delta.Add("Spec.DefinitionValidation", a.ko.Spec.DefinitionValidation, b.ko.Spec.DefinitionValidation)
+	} else if a.ko.Spec.DefinitionValidation != nil && b.ko.Spec.DefinitionValidation != nil {
+		if ackcompare.HasNilDifference(a.ko.Spec.DefinitionValidation.DisableLinter, b.ko.Spec.DefinitionValidation.DisableLinter) {
+			delta.Add("Spec.DefinitionValidation.DisableLinter", a.ko.Spec.DefinitionValidation.DisableLinter, b.ko.Spec.DefinitionValidation.DisableLinter)
+		} else if a.ko.Spec.DefinitionValidation.DisableLinter != nil && b.ko.Spec.DefinitionValidation.DisableLinter != nil {
+			if *a.ko.Spec.DefinitionValidation.DisableLinter != *b.ko.Spec.DefinitionValidation.DisableLinter {
+				delta.Add("Spec.DefinitionValidation.DisableLinter", a.ko.Spec.DefinitionValidation.DisableLinter, b.ko.Spec.DefinitionValidation.DisableLinter)
+			}
+		}
+		if ackcompare.HasNilDifference(a.ko.Spec.DefinitionValidation.SeverityThreshold, b.ko.Spec.DefinitionValidation.SeverityThreshold) {
+			delta.Add("Spec.DefinitionValidation.SeverityThreshold", a.ko.Spec.DefinitionValidation.SeverityThreshold, b.ko.Spec.DefinitionValidation.SeverityThreshold)
+		} else if a.ko.Spec.DefinitionValidation.SeverityThreshold != nil && b.ko.Spec.DefinitionValidation.SeverityThreshold != nil {
+			if *a.ko.Spec.DefinitionValidation.SeverityThreshold != *b.ko.Spec.DefinitionValidation.SeverityThreshold {
+				delta.Add("Spec.DefinitionValidation.SeverityThreshold", a.ko.Spec.DefinitionValidation.SeverityThreshold, b.ko.Spec.DefinitionValidation.SeverityThreshold)
+			}
 		}
 	}
 	if ackcompare.HasNilDifference(a.ko.Spec.LoggingConfiguration, b.ko.Spec.LoggingConfiguration) {
@@ -110,6 +121,24 @@ func newResourceDelta(
 			delta.Add("Spec.Type", a.ko.Spec.Type, b.ko.Spec.Type)
 		}
 	}
+	if ackcompare.HasNilDifference(a.ko.Spec.VersionRetention, b.ko.Spec.VersionRetention) {
+		delta.Add("Spec.VersionRetention", a.ko.Spec.VersionRetention, b.ko.Spec.VersionRetention)
+	} else if a.ko.Spec.VersionRetention != nil && b.ko.Spec.VersionRetention != nil {
+		if ackcompare.HasNilDifference(a.ko.Spec.VersionRetention.KeepLast, b.ko.Spec.VersionRetention.KeepLast) {
+			delta.Add("Spec.VersionRetention.KeepLast", a.ko.Spec.VersionRetention.KeepLast, b.ko.Spec.VersionRetention.KeepLast)
+		} else if a.ko.Spec.VersionRetention.KeepLast != nil && b.ko.Spec.VersionRetention.KeepLast != nil {
+			if *a.ko.Spec.VersionRetention.KeepLast != *b.ko.Spec.VersionRetention.KeepLast {
+				delta.Add("Spec.VersionRetention.KeepLast", a.ko.Spec.VersionRetention.KeepLast, b.ko.Spec.VersionRetention.KeepLast)
+			}
+		}
+		if ackcompare.HasNilDifference(a.ko.Spec.VersionRetention.MaxAge, b.ko.Spec.VersionRetention.MaxAge) {
+			delta.Add("Spec.VersionRetention.MaxAge", a.ko.Spec.VersionRetention.MaxAge, b.ko.Spec.VersionRetention.MaxAge)
+		} else if a.ko.Spec.VersionRetention.MaxAge != nil && b.ko.Spec.VersionRetention.MaxAge != nil {
+			if *a.ko.Spec.VersionRetention.MaxAge != *b.ko.Spec.VersionRetention.MaxAge {
+				delta.Add("Spec.VersionRetention.MaxAge", a.ko.Spec.VersionRetention.MaxAge, b.ko.Spec.VersionRetention.MaxAge)
+			}
+		}
+	}
 
 	return delta
 }
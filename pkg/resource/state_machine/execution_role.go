@@ -0,0 +1,347 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package state_machine
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	iamapitypes "github.com/aws-controllers-k8s/iam-controller/apis/v1alpha1"
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+	"github.com/aws-controllers-k8s/sfn-controller/pkg/asl/policy"
+	commonutil "github.com/aws-controllers-k8s/sfn-controller/pkg/util"
+)
+
+// +kubebuilder:rbac:groups=iam.services.k8s.aws,resources=roles;policies,verbs=get;list;create;update;patch;delete
+// +kubebuilder:rbac:groups=iam.services.k8s.aws,resources=roles/status;policies/status,verbs=get;list
+
+const (
+	// executionRoleSuffix is appended to the name of the StateMachine to name
+	// the generated Role and Policy objects.
+	executionRoleSuffix = "-execution-role"
+	// maxRoleNameLength is the maximum length of an IAM role name.
+	maxRoleNameLength = 64
+	// generatedObjectRequeueAfter is how long to wait before checking again
+	// whether the objects generated for a state machine are synced.
+	generatedObjectRequeueAfter = 10 * time.Second
+)
+
+var (
+	// loggingActions are the actions Step Functions needs to deliver
+	// execution logs to CloudWatch Logs. They do not support resource-level
+	// permissions.
+	loggingActions = []string{
+		"logs:CreateLogDelivery",
+		"logs:DeleteLogDelivery",
+		"logs:DescribeLogGroups",
+		"logs:DescribeResourcePolicies",
+		"logs:GetLogDelivery",
+		"logs:ListLogDeliveries",
+		"logs:PutResourcePolicy",
+		"logs:UpdateLogDelivery",
+	}
+	// tracingActions are the actions Step Functions needs to send traces to
+	// X-Ray. They do not support resource-level permissions either.
+	tracingActions = []string{
+		"xray:GetSamplingRules",
+		"xray:GetSamplingTargets",
+		"xray:PutTelemetryRecords",
+		"xray:PutTraceSegments",
+	}
+	// kmsActions are the actions Step Functions needs to encrypt execution
+	// data with a customer managed key.
+	kmsActions = []string{
+		"kms:Decrypt",
+		"kms:GenerateDataKey",
+	}
+)
+
+// resolveReferenceForExecutionRoleGeneration sets Status.ResolvedRoleARN
+// from the iam-controller Role generated for the state machine once it is
+// synced, when ExecutionRoleGeneration is set. The Role is written once the
+// references are resolved, see writeGeneratedExecutionRole, it is not an
+// error if it does not exist or is not synced yet. Returns a
+// boolean indicating whether a reference contains references, or an error
+func (rm *resourceManager) resolveReferenceForExecutionRoleGeneration(
+	ctx context.Context,
+	apiReader client.Reader,
+	ko *svcapitypes.StateMachine,
+) (hasReferences bool, err error) {
+	if ko.Spec.ExecutionRoleGeneration == nil {
+		return false, nil
+	}
+	hasReferences = true
+	role := &iamapitypes.Role{}
+	err = getReferencedResourceState_Role(ctx, apiReader, role, ko.Name+executionRoleSuffix, ko.Namespace)
+	if apierrors.IsNotFound(err) || errors.Is(err, ackerr.ResourceReferenceNotSynced) {
+		return hasReferences, nil
+	}
+	if err != nil {
+		return hasReferences, err
+	}
+	ko.Status.ResolvedRoleARN = (*string)(role.Status.ACKResourceMetadata.ARN)
+	return hasReferences, nil
+}
+
+// writeGeneratedExecutionRole creates or updates the iam-controller Role and
+// Policy generated from the definition when ExecutionRoleGeneration is set.
+// They are owned by the StateMachine and garbage collected with it. It
+// returns a requeue error until the Role is synced and Status.ResolvedRoleARN
+// is set by resolveReferenceForExecutionRoleGeneration.
+func (rm *resourceManager) writeGeneratedExecutionRole(
+	ctx context.Context,
+	ko *svcapitypes.StateMachine,
+) error {
+	if ko.Spec.ExecutionRoleGeneration == nil {
+		return nil
+	}
+	kc, apiReader := commonutil.KubeClient(), commonutil.APIReader()
+	if kc == nil || apiReader == nil {
+		return errors.New("kubernetes client is not set, cannot generate the execution role")
+	}
+
+	permissions, err := rm.executionRolePermissions(ko)
+	if err != nil {
+		return err
+	}
+	name := ko.Name + executionRoleSuffix
+	roleName := generatedRoleName(ko)
+	description := fmt.Sprintf(
+		"Execution role of the %s/%s StateMachine, generated from its definition",
+		ko.Namespace, ko.Name,
+	)
+
+	roleSpec := iamapitypes.RoleSpec{
+		AssumeRolePolicyDocument: aws.String(rm.executionRoleTrustPolicy()),
+		Description:              aws.String(description),
+		Name:                     aws.String(roleName),
+		PermissionsBoundary:      ko.Spec.ExecutionRoleGeneration.PermissionsBoundary,
+		Policies:                 ko.Spec.ExecutionRoleGeneration.AdditionalPolicies,
+	}
+	generated := &iamapitypes.Policy{}
+	if doc := permissions.Document(); len(doc.Statement) > 0 {
+		generated.Spec = iamapitypes.PolicySpec{
+			Description:    aws.String(description),
+			Name:           aws.String(roleName),
+			PolicyDocument: aws.String(doc.String()),
+		}
+		if err := writeOwnedObject(ctx, apiReader, kc, ko, "Policy", name, generated, func() error {
+			generated.Spec.PolicyDocument = aws.String(doc.String())
+			return nil
+		}); err != nil {
+			return err
+		}
+		roleSpec.PolicyRefs = []*ackv1alpha1.AWSResourceReferenceWrapper{{
+			From: &ackv1alpha1.AWSResourceReference{Name: aws.String(name)},
+		}}
+	}
+
+	role := &iamapitypes.Role{Spec: roleSpec}
+	if err := writeOwnedObject(ctx, apiReader, kc, ko, "Role", name, role, func() error {
+		// Only update the fields set by the controller, the others may have
+		// been late initialized by the iam-controller.
+		role.Spec.AssumeRolePolicyDocument = roleSpec.AssumeRolePolicyDocument
+		role.Spec.Description = roleSpec.Description
+		role.Spec.PermissionsBoundary = roleSpec.PermissionsBoundary
+		role.Spec.Policies = roleSpec.Policies
+		role.Spec.PolicyRefs = roleSpec.PolicyRefs
+		return nil
+	}); err != nil {
+		return err
+	}
+	if roleSpec.PolicyRefs == nil {
+		// The definition no longer needs any permission, delete the Policy
+		// generated for a previous definition once it is detached.
+		if err := deleteOwnedObject(ctx, apiReader, kc, ko, name, &iamapitypes.Policy{}); err != nil {
+			return err
+		}
+	}
+	if ko.Status.ResolvedRoleARN == nil {
+		return ackrequeue.NeededAfter(
+			fmt.Errorf("waiting for Role %s/%s to be synced", ko.Namespace, name),
+			generatedObjectRequeueAfter,
+		)
+	}
+	return nil
+}
+
+// executionRolePermissions returns the permissions needed by the rendered
+// definition and by the logging, tracing and encryption configuration of the
+// supplied StateMachine.
+func (rm *resourceManager) executionRolePermissions(
+	ko *svcapitypes.StateMachine,
+) (*policy.Permissions, error) {
+	definition, err := resolveDefinition(ko)
+	if err != nil {
+		return nil, err
+	}
+	if definition == nil {
+		return nil, ackerr.NewTerminalError(errors.New("cannot generate the execution role without a definition"))
+	}
+	permissions, err := policy.Generate(*definition, policy.Environment{
+		Partition: string(rm.awsPartition),
+		Region:    string(rm.awsRegion),
+		AccountID: string(rm.awsAccountID),
+		StateMachineARN: fmt.Sprintf(
+			"arn:%s:states:%s:%s:stateMachine:%s",
+			rm.awsPartition, rm.awsRegion, rm.awsAccountID, aws.ToString(ko.Spec.Name),
+		),
+	})
+	if err != nil {
+		return nil, ackerr.NewTerminalError(fmt.Errorf("cannot generate the execution role: %w", err))
+	}
+	if lc := ko.Spec.LoggingConfiguration; lc != nil && lc.Level != nil && *lc.Level != "OFF" {
+		permissions.Allow(loggingActions, "*")
+	}
+	if tc := ko.Spec.TracingConfiguration; tc != nil && aws.ToBool(tc.Enabled) {
+		permissions.Allow(tracingActions, "*")
+	}
+	if ec := ko.Spec.EncryptionConfiguration; ec != nil && ec.KMSKeyID != nil {
+		key := *ec.KMSKeyID
+		if !strings.HasPrefix(key, "arn:") {
+			key = fmt.Sprintf("arn:%s:kms:%s:%s:key/%s", rm.awsPartition, rm.awsRegion, rm.awsAccountID, key)
+		}
+		permissions.Allow(kmsActions, key)
+	}
+	return permissions, nil
+}
+
+// executionRoleTrustPolicy returns the trust policy of the generated role,
+// which allows Step Functions to assume it on behalf of state machines of
+// the account only.
+func (rm *resourceManager) executionRoleTrustPolicy() string {
+	trust := map[string]interface{}{
+		"Version": "2012-10-17",
+		"Statement": []interface{}{
+			map[string]interface{}{
+				"Effect":    "Allow",
+				"Principal": map[string]string{"Service": "states.amazonaws.com"},
+				"Action":    "sts:AssumeRole",
+				"Condition": map[string]interface{}{
+					"StringEquals": map[string]string{"aws:SourceAccount": string(rm.awsAccountID)},
+				},
+			},
+		},
+	}
+	b, _ := json.Marshal(trust)
+	return string(b)
+}
+
+// generatedRoleName returns the IAM name of the role generated for the
+// supplied StateMachine. Names longer than the IAM limit are truncated and
+// suffixed with a hash so that they stay unique.
+func generatedRoleName(ko *svcapitypes.StateMachine) string {
+	name := fmt.Sprintf("sfn-%s-%s", ko.Namespace, ko.Name)
+	if len(name) <= maxRoleNameLength {
+		return name
+	}
+	sum := sha256.Sum256([]byte(name))
+	suffix := hex.EncodeToString(sum[:])[:8]
+	return name[:maxRoleNameLength-len(suffix)-1] + "-" + suffix
+}
+
+// writeOwnedObject creates the named object in the namespace of the
+// StateMachine, owned by it, or calls mutate on the existing object and
+// updates it if it changed. It fails if the existing object is not owned by
+// the StateMachine.
+func writeOwnedObject(
+	ctx context.Context,
+	apiReader client.Reader,
+	kc client.Client,
+	owner *svcapitypes.StateMachine,
+	kind string,
+	name string,
+	obj client.Object,
+	mutate func() error,
+) error {
+	key := types.NamespacedName{Namespace: owner.Namespace, Name: name}
+	if err := apiReader.Get(ctx, key, obj); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		obj.SetName(name)
+		obj.SetNamespace(owner.Namespace)
+		obj.SetOwnerReferences([]metav1.OwnerReference{ownerReference(owner)})
+		return kc.Create(ctx, obj)
+	}
+	if !isOwnedBy(obj, owner) {
+		return ackerr.NewTerminalError(fmt.Errorf(
+			"%s %s/%s already exists and is not owned by StateMachine %s",
+			kind, owner.Namespace, name, owner.Name,
+		))
+	}
+	before := obj.DeepCopyObject()
+	if err := mutate(); err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(before, obj) {
+		return nil
+	}
+	return kc.Update(ctx, obj)
+}
+
+// deleteOwnedObject deletes the named object if it exists and is owned by
+// the StateMachine.
+func deleteOwnedObject(
+	ctx context.Context,
+	apiReader client.Reader,
+	kc client.Client,
+	owner *svcapitypes.StateMachine,
+	name string,
+	obj client.Object,
+) error {
+	key := types.NamespacedName{Namespace: owner.Namespace, Name: name}
+	if err := apiReader.Get(ctx, key, obj); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !isOwnedBy(obj, owner) {
+		return nil
+	}
+	return client.IgnoreNotFound(kc.Delete(ctx, obj))
+}
+
+// ownerReference returns the controller reference to the StateMachine set
+// on the objects generated for it, so that they are garbage collected with
+// it.
+func ownerReference(owner *svcapitypes.StateMachine) metav1.OwnerReference {
+	return metav1.OwnerReference{
+		APIVersion: svcapitypes.GroupVersion.String(),
+		Kind:       "StateMachine",
+		Name:       owner.Name,
+		UID:        owner.UID,
+		Controller: aws.Bool(true),
+	}
+}
+
+// isOwnedBy returns true if the StateMachine is the controller of obj.
+func isOwnedBy(obj client.Object, owner *svcapitypes.StateMachine) bool {
+	ref := metav1.GetControllerOf(obj)
+	return ref != nil && ref.UID == owner.UID
+}
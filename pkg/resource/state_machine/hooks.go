@@ -15,11 +15,17 @@ package state_machine
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math"
 
 	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
 	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	svcsdk "github.com/aws/aws-sdk-go-v2/service/sfn"
 	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/sfn/types"
+	"k8s.io/apimachinery/pkg/types"
 
 	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
 	commonutil "github.com/aws-controllers-k8s/sfn-controller/pkg/util"
@@ -56,6 +62,26 @@ func (rm *resourceManager) customUpdateStateMachine(
 	latest *resource,
 	delta *ackcompare.Delta,
 ) (*resource, error) {
+	unlock := updateLocks.Lock(types.NamespacedName{
+		Namespace: desired.ko.Namespace,
+		Name:      desired.ko.Name,
+	})
+	defer unlock()
+
+	if delta.DifferentAt("Spec.Definition") || delta.DifferentAt("Spec.DefinitionObject") {
+		// Validate the new definition before anything is mutated, returning
+		// the diagnostics in the status of the desired resource.
+		validated := &resource{desired.ko.DeepCopy()}
+		if err := rm.validateDefinition(ctx, validated); err != nil {
+			return validated, err
+		}
+		desired = validated
+	}
+	if delta.DifferentAt("Spec.LoggingConfiguration") {
+		if err := validateLoggingConfiguration(desired.ko); err != nil {
+			return desired, err
+		}
+	}
 	if delta.DifferentAt("Spec.Tags") {
 		err := commonutil.SyncResourceTags(
 			ctx,
@@ -69,20 +95,69 @@ func (rm *resourceManager) customUpdateStateMachine(
 			return nil, err
 		}
 	}
-	if delta.DifferentExcept("Spec.Tags") {
-		err := rm.updateStateMachine(ctx, desired)
+	if delta.DifferentExcept("Spec.Tags", "Spec.VersionRetention", "Spec.DefinitionValidation", redriveDeltaPath) {
+		if err := rm.checkRevision(ctx, latest.ko); err != nil {
+			return desired, err
+		}
+		updated, err := rm.updateStateMachine(ctx, desired)
 		if err != nil {
 			return nil, err
 		}
+		desired = updated
+	}
+	// The retention policy is applied when the state machine is read, apply
+	// it again once the update published a new version.
+	if aws.ToString(desired.ko.Status.LatestVersionARN) != aws.ToString(latest.ko.Status.LatestVersionARN) {
+		if err := rm.pruneStateMachineVersions(ctx, desired); err != nil {
+			return desired, err
+		}
+	}
+	if delta.DifferentAt(redriveDeltaPath) {
+		if err := rm.redriveFailedExecutions(ctx, desired.ko); err != nil {
+			return desired, err
+		}
 	}
 	return desired, nil
 }
 
+// writeGeneratedObjects creates or updates the objects generated for the
+// supplied StateMachine. It is called whenever the StateMachine is read,
+// once its references are resolved, and only updates the objects that
+// differ from what is generated, see writeOwnedObject. All of them are
+// written before returning the requeue error of any that is not synced yet.
+func (rm *resourceManager) writeGeneratedObjects(
+	ctx context.Context,
+	ko *svcapitypes.StateMachine,
+) error {
+	roleErr := rm.writeGeneratedExecutionRole(ctx, ko)
+	var requeue *ackrequeue.RequeueNeededAfter
+	if roleErr != nil && !errors.As(roleErr, &requeue) {
+		return roleErr
+	}
+	if err := rm.writeGeneratedLogGroup(ctx, ko); err != nil {
+		return err
+	}
+	return roleErr
+}
+
 func customPreCompare(
 	delta *ackcompare.Delta,
 	a *resource,
 	b *resource,
 ) {
+	if !equalRenderedDefinition(a.ko, b.ko) {
+		if a.ko.Spec.DefinitionObject != nil {
+			delta.Add("Spec.DefinitionObject", a.ko.Spec.DefinitionObject, b.ko.Spec.DefinitionObject)
+		} else {
+			delta.Add("Spec.Definition", a.ko.Spec.Definition, b.ko.Spec.Definition)
+		}
+	}
+	if value, ok := pendingRedriveSince(b.ko); ok {
+		delta.Add(redriveDeltaPath, value, b.ko.Status.ObservedRedriveSince)
+	}
+	if !commonutil.EqualEncryptionConfiguration(a.ko.Spec.EncryptionConfiguration, b.ko.Spec.EncryptionConfiguration) {
+		delta.Add("Spec.EncryptionConfiguration", a.ko.Spec.EncryptionConfiguration, b.ko.Spec.EncryptionConfiguration)
+	}
 	if len(a.ko.Spec.Tags) != len(b.ko.Spec.Tags) {
 		delta.Add("Spec.Tags", a.ko.Spec.Tags, b.ko.Spec.Tags)
 	} else if len(a.ko.Spec.Tags) > 0 {
@@ -92,33 +167,43 @@ func customPreCompare(
 	}
 }
 
-// sdkUpdate patches the supplied resource in the backend AWS service API and
-// returns a new resource with updated fields.
+// updateStateMachine patches the supplied resource in the backend AWS service
+// API and returns a new resource with updated fields.
 func (rm *resourceManager) updateStateMachine(
 	ctx context.Context,
 	desired *resource,
-) (err error) {
+) (updated *resource, err error) {
 	rlog := ackrtlog.FromContext(ctx)
-	exit := rlog.Trace("rm.sdkUpdate")
+	exit := rlog.Trace("rm.updateStateMachine")
 	defer func() {
 		exit(err)
 	}()
 	input, err := rm.newUpdateRequestPayload(ctx, desired)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	_, err = rm.sdkapi.UpdateStateMachine(ctx, input)
+	var resp *svcsdk.UpdateStateMachineOutput
+	resp, err = rm.sdkapi.UpdateStateMachine(ctx, input)
 	rm.metrics.RecordAPICall("UPDATE", "UpdateStateMachine", err)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	// Merge in the information we read from the API call above to the copy of
 	// the original Kubernetes object we passed to the function
 	ko := desired.ko.DeepCopy()
 
+	if resp.RevisionId != nil {
+		ko.Status.RevisionID = resp.RevisionId
+	}
+	// StateMachineVersionArn is only returned when the update published a
+	// new version, keep pointing at the previous one otherwise.
+	if resp.StateMachineVersionArn != nil {
+		ko.Status.LatestVersionARN = resp.StateMachineVersionArn
+	}
+
 	rm.setStatusDefaults(ko)
-	return nil
+	return &resource{ko}, nil
 }
 
 // newUpdateRequestPayload returns an SDK-specific struct for the HTTP request
@@ -129,47 +214,71 @@ func (rm *resourceManager) newUpdateRequestPayload(
 ) (*svcsdk.UpdateStateMachineInput, error) {
 	res := &svcsdk.UpdateStateMachineInput{}
 
-	if r.ko.Spec.Definition != nil {
-		res.Definition = r.ko.Spec.Definition
+	definition, err := resolveDefinition(r.ko)
+	if err != nil {
+		return nil, err
+	}
+	res.Definition = definition
+	if r.ko.Spec.EncryptionConfiguration != nil {
+		f1 := &svcsdktypes.EncryptionConfiguration{}
+		if r.ko.Spec.EncryptionConfiguration.KMSDataKeyReusePeriodSeconds != nil {
+			kmsDataKeyReusePeriodSecondsCopy0 := *r.ko.Spec.EncryptionConfiguration.KMSDataKeyReusePeriodSeconds
+			if kmsDataKeyReusePeriodSecondsCopy0 > math.MaxInt32 || kmsDataKeyReusePeriodSecondsCopy0 < math.MinInt32 {
+				return nil, fmt.Errorf("error: field KmsDataKeyReusePeriodSeconds is of type int32")
+			}
+			kmsDataKeyReusePeriodSecondsCopy := int32(kmsDataKeyReusePeriodSecondsCopy0)
+			f1.KmsDataKeyReusePeriodSeconds = &kmsDataKeyReusePeriodSecondsCopy
+		}
+		if r.ko.Spec.EncryptionConfiguration.KMSKeyID != nil {
+			f1.KmsKeyId = r.ko.Spec.EncryptionConfiguration.KMSKeyID
+		}
+		if r.ko.Spec.EncryptionConfiguration.Type != nil {
+			f1.Type = svcsdktypes.EncryptionType(*r.ko.Spec.EncryptionConfiguration.Type)
+		}
+		res.EncryptionConfiguration = f1
 	}
 	if r.ko.Spec.LoggingConfiguration != nil {
-		f1 := &svcsdktypes.LoggingConfiguration{}
+		f2 := &svcsdktypes.LoggingConfiguration{}
 		if r.ko.Spec.LoggingConfiguration.Destinations != nil {
-			f1f0 := []svcsdktypes.LogDestination{}
-			for _, f1f0iter := range r.ko.Spec.LoggingConfiguration.Destinations {
-				f1f0elem := svcsdktypes.LogDestination{}
-				if f1f0iter.CloudWatchLogsLogGroup != nil {
-					f1f0elemf0 := &svcsdktypes.CloudWatchLogsLogGroup{}
-					if f1f0iter.CloudWatchLogsLogGroup.LogGroupARN != nil {
-						f1f0elemf0.LogGroupArn = f1f0iter.CloudWatchLogsLogGroup.LogGroupARN
+			f2f0 := []svcsdktypes.LogDestination{}
+			for _, f2f0iter := range r.ko.Spec.LoggingConfiguration.Destinations {
+				f2f0elem := svcsdktypes.LogDestination{}
+				if f2f0iter.CloudWatchLogsLogGroup != nil {
+					f2f0elemf0 := &svcsdktypes.CloudWatchLogsLogGroup{}
+					if f2f0iter.CloudWatchLogsLogGroup.LogGroupARN != nil {
+						f2f0elemf0.LogGroupArn = f2f0iter.CloudWatchLogsLogGroup.LogGroupARN
 					}
-					f1f0elem.CloudWatchLogsLogGroup = f1f0elemf0
+					f2f0elem.CloudWatchLogsLogGroup = f2f0elemf0
 				}
-				f1f0 = append(f1f0, f1f0elem)
+				f2f0 = append(f2f0, f2f0elem)
 			}
-			f1.Destinations = f1f0
+			f2.Destinations = f2f0
 		}
 		if r.ko.Spec.LoggingConfiguration.IncludeExecutionData != nil {
-			f1.IncludeExecutionData = *r.ko.Spec.LoggingConfiguration.IncludeExecutionData
+			f2.IncludeExecutionData = *r.ko.Spec.LoggingConfiguration.IncludeExecutionData
 		}
 		if r.ko.Spec.LoggingConfiguration.Level != nil {
-			f1.Level = svcsdktypes.LogLevel(*r.ko.Spec.LoggingConfiguration.Level)
+			f2.Level = svcsdktypes.LogLevel(*r.ko.Spec.LoggingConfiguration.Level)
 		}
-		res.LoggingConfiguration = f1
+		res.LoggingConfiguration = f2
 	}
-	if r.ko.Spec.RoleARN != nil {
-		res.RoleArn = r.ko.Spec.RoleARN
+	if r.ko.Spec.Publish != nil {
+		res.Publish = *r.ko.Spec.Publish
 	}
+	res.RoleArn = executionRoleARN(r.ko)
 	if r.ko.Status.ACKResourceMetadata != nil && r.ko.Status.ACKResourceMetadata.ARN != nil {
 		arnCopy := string(*r.ko.Status.ACKResourceMetadata.ARN)
 		res.StateMachineArn = &arnCopy
 	}
 	if r.ko.Spec.TracingConfiguration != nil {
-		f4 := &svcsdktypes.TracingConfiguration{}
+		f6 := &svcsdktypes.TracingConfiguration{}
 		if r.ko.Spec.TracingConfiguration.Enabled != nil {
-			f4.Enabled = *r.ko.Spec.TracingConfiguration.Enabled
+			f6.Enabled = *r.ko.Spec.TracingConfiguration.Enabled
 		}
-		res.TracingConfiguration = f4
+		res.TracingConfiguration = f6
+	}
+	if r.ko.Spec.VersionDescription != nil {
+		res.VersionDescription = r.ko.Spec.VersionDescription
 	}
 
 	return res, nil
@@ -0,0 +1,65 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package state_machine
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+)
+
+func TestNewResourceDeltaUnchanged(t *testing.T) {
+	tests := []struct {
+		name string
+		spec svcapitypes.StateMachineSpec
+	}{
+		{
+			name: "generated log group",
+			spec: svcapitypes.StateMachineSpec{
+				LogGroupGeneration: &svcapitypes.LogGroupGeneration{RetentionDays: aws.Int64(7)},
+			},
+		},
+		{
+			name: "generated execution role",
+			spec: svcapitypes.StateMachineSpec{
+				ExecutionRoleGeneration: &svcapitypes.ExecutionRoleGeneration{},
+			},
+		},
+		{
+			name: "version retention",
+			spec: svcapitypes.StateMachineSpec{
+				VersionRetention: &svcapitypes.VersionRetentionPolicy{KeepLast: aws.Int64(2)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ko := &svcapitypes.StateMachine{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "hello"},
+				Spec:       tt.spec,
+			}
+			ko.Spec.Definition = aws.String(`{"StartAt": "Pass", "States": {"Pass": {"Type": "Pass", "End": true}}}`)
+			// The generated objects are written and the versions pruned
+			// when the StateMachine is read, an unchanged StateMachine must
+			// not be updated for them.
+			delta := newResourceDelta(&resource{ko}, &resource{ko.DeepCopy()})
+			if diffs := delta.Differences; len(diffs) != 0 {
+				t.Errorf("newResourceDelta() differences = %v, want none", diffs)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package state_machine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/sfn/types"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+	commonutil "github.com/aws-controllers-k8s/sfn-controller/pkg/util"
+)
+
+// +kubebuilder:rbac:groups=cloudwatchlogs.services.k8s.aws,resources=loggroups,verbs=get;list;create;update;patch
+
+const (
+	// logGroupSuffix is appended to the name of the StateMachine to name the
+	// generated LogGroup object.
+	logGroupSuffix = "-logs"
+	// logGroupNamePrefix is the prefix of the names of the generated log
+	// groups. Log groups under /aws/vendedlogs/ don't count against the size
+	// of the CloudWatch Logs resource policy Step Functions updates.
+	logGroupNamePrefix = "/aws/vendedlogs/states/"
+)
+
+// resolveReferenceForLogGroupGeneration sets the cloudwatchlogs-controller
+// LogGroup generated for the state machine as the only destination of
+// LoggingConfiguration once it is synced, when LogGroupGeneration is set and
+// logging needs a destination. The LogGroup is written once the references
+// are resolved, see writeGeneratedLogGroup, it is not an error if it does
+// not exist yet. Returns a boolean indicating whether a reference
+// contains references, or an error
+func (rm *resourceManager) resolveReferenceForLogGroupGeneration(
+	ctx context.Context,
+	apiReader client.Reader,
+	ko *svcapitypes.StateMachine,
+) (hasReferences bool, err error) {
+	if !needsGeneratedLogGroup(ko) || !needsLogDestination(ko) {
+		return false, nil
+	}
+	hasReferences = true
+	logGroupARN, err := commonutil.GetReferencedResourceARN(
+		ctx, apiReader, commonutil.CloudWatchLogsLogGroupGVK, ko.Name+logGroupSuffix, ko.Namespace,
+	)
+	if apierrors.IsNotFound(err) {
+		return hasReferences, nil
+	}
+	if err != nil {
+		return hasReferences, err
+	}
+	if ko.Spec.LoggingConfiguration == nil {
+		ko.Spec.LoggingConfiguration = &svcapitypes.LoggingConfiguration{}
+	}
+	ko.Spec.LoggingConfiguration.Destinations = []*svcapitypes.LogDestination{{
+		CloudWatchLogsLogGroup: &svcapitypes.CloudWatchLogsLogGroup{
+			LogGroupARN: aws.String(logGroupDestinationARN(logGroupARN)),
+		},
+	}}
+	return hasReferences, nil
+}
+
+// writeGeneratedLogGroup creates or updates the cloudwatchlogs-controller
+// LogGroup generated for the state machine when LogGroupGeneration is set and
+// logging needs a destination. The LogGroup is owned by the StateMachine and
+// garbage collected with it. It returns a requeue error until the LogGroup is
+// synced and set as the logging destination by
+// resolveReferenceForLogGroupGeneration.
+func (rm *resourceManager) writeGeneratedLogGroup(
+	ctx context.Context,
+	ko *svcapitypes.StateMachine,
+) error {
+	if !needsGeneratedLogGroup(ko) {
+		return nil
+	}
+	kc, apiReader := commonutil.KubeClient(), commonutil.APIReader()
+	if kc == nil || apiReader == nil {
+		return errors.New("kubernetes client is not set, cannot generate the log group")
+	}
+
+	name := ko.Name + logGroupSuffix
+	retentionDays := ko.Spec.LogGroupGeneration.RetentionDays
+	logGroup := &unstructured.Unstructured{}
+	logGroup.SetGroupVersionKind(commonutil.CloudWatchLogsLogGroupGVK)
+	setSpec := func() error {
+		if retentionDays != nil {
+			return unstructured.SetNestedField(logGroup.Object, *retentionDays, "spec", "retentionDays")
+		}
+		unstructured.RemoveNestedField(logGroup.Object, "spec", "retentionDays")
+		return nil
+	}
+	if err := unstructured.SetNestedField(
+		logGroup.Object, logGroupNamePrefix+aws.ToString(ko.Spec.Name), "spec", "name",
+	); err != nil {
+		return err
+	}
+	if err := setSpec(); err != nil {
+		return err
+	}
+	if err := writeOwnedObject(ctx, apiReader, kc, ko, "LogGroup", name, logGroup, setSpec); err != nil {
+		return err
+	}
+	if needsLogDestination(ko) {
+		return ackrequeue.NeededAfter(
+			fmt.Errorf("waiting for LogGroup %s/%s to be synced", ko.Namespace, name),
+			generatedObjectRequeueAfter,
+		)
+	}
+	return nil
+}
+
+// needsGeneratedLogGroup returns true if LogGroupGeneration is set and the
+// state machine logs execution history or is an EXPRESS state machine.
+func needsGeneratedLogGroup(ko *svcapitypes.StateMachine) bool {
+	return ko.Spec.LogGroupGeneration != nil && logsExecutions(ko)
+}
+
+// needsLogDestination returns true if the state machine has no logging
+// destination but needs one, because it logs execution history or is an
+// EXPRESS state machine.
+func needsLogDestination(ko *svcapitypes.StateMachine) bool {
+	lc := ko.Spec.LoggingConfiguration
+	if lc != nil && len(lc.Destinations) > 0 {
+		return false
+	}
+	return logsExecutions(ko)
+}
+
+// logsExecutions returns true if the state machine logs execution history or
+// is an EXPRESS state machine, which always needs a logging destination.
+func logsExecutions(ko *svcapitypes.StateMachine) bool {
+	if aws.ToString(ko.Spec.Type) == string(svcsdktypes.StateMachineTypeExpress) {
+		return true
+	}
+	lc := ko.Spec.LoggingConfiguration
+	return lc != nil && lc.Level != nil && *lc.Level != string(svcsdktypes.LogLevelOff)
+}
+
+// logGroupDestinationARN returns the ARN of a log group as expected in a
+// logging destination, that is ending with ":*".
+func logGroupDestinationARN(logGroupARN string) string {
+	if strings.HasSuffix(logGroupARN, ":*") {
+		return logGroupARN
+	}
+	return logGroupARN + ":*"
+}
+
+// validateLoggingConfiguration returns a terminal error if the logging
+// destinations of the supplied StateMachine are not valid log group ARNs, or
+// if it is an EXPRESS state machine without a logging destination.
+func validateLoggingConfiguration(ko *svcapitypes.StateMachine) error {
+	var destinations int
+	if lc := ko.Spec.LoggingConfiguration; lc != nil {
+		for _, destination := range lc.Destinations {
+			if destination == nil || destination.CloudWatchLogsLogGroup == nil || destination.CloudWatchLogsLogGroup.LogGroupARN == nil {
+				continue
+			}
+			logGroupARN := *destination.CloudWatchLogsLogGroup.LogGroupARN
+			if !strings.HasPrefix(logGroupARN, "arn:") || !strings.HasSuffix(logGroupARN, ":*") {
+				return ackerr.NewTerminalError(fmt.Errorf(
+					"invalid logging destination %q: the ARN of a log group must end with \":*\", or use logGroupRef",
+					logGroupARN,
+				))
+			}
+			destinations++
+		}
+	}
+	if destinations == 0 && aws.ToString(ko.Spec.Type) == string(svcsdktypes.StateMachineTypeExpress) {
+		return ackerr.NewTerminalError(errors.New(
+			"EXPRESS state machines must have a logging destination: " +
+				"set loggingConfiguration.destinations or logGroupGeneration",
+		))
+	}
+	return nil
+}
+
+// setLatestLogGroupRefs copies the LogGroupRef of the logging destinations
+// of desired to the destinations read from Step Functions, which only have
+// the resolved LogGroupARN, so that they compare equal.
+func setLatestLogGroupRefs(desired, latest *svcapitypes.StateMachine) {
+	if desired.Spec.LoggingConfiguration == nil || latest.Spec.LoggingConfiguration == nil {
+		return
+	}
+	desiredDestinations := desired.Spec.LoggingConfiguration.Destinations
+	for i, destination := range latest.Spec.LoggingConfiguration.Destinations {
+		if i >= len(desiredDestinations) {
+			return
+		}
+		if destination == nil || destination.CloudWatchLogsLogGroup == nil ||
+			desiredDestinations[i] == nil || desiredDestinations[i].CloudWatchLogsLogGroup == nil {
+			continue
+		}
+		destination.CloudWatchLogsLogGroup.LogGroupRef = desiredDestinations[i].CloudWatchLogsLogGroup.LogGroupRef
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package state_machine
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/sfn"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/sfn/types"
+	smithy "github.com/aws/smithy-go"
+	corev1 "k8s.io/api/core/v1"
+	ctrlrt "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+	commonutil "github.com/aws-controllers-k8s/sfn-controller/pkg/util"
+)
+
+// redriveFailedExecutionsSinceAnnotation is the StateMachine annotation
+// requesting to redrive the executions of the state machine that failed at
+// or after the RFC 3339 timestamp it is set to, e.g. after a downstream
+// outage. Each failed execution is redriven once per timestamp: executions
+// redriven after the timestamp are left alone if they fail again.
+const redriveFailedExecutionsSinceAnnotation = "sfn.services.k8s.aws/redrive-failed-executions-since"
+
+// redriveDeltaPath is the path of the difference customPreCompare reports
+// while the redriveFailedExecutionsSinceAnnotation annotation has not been
+// handled, so that the update redrives the failed executions. The executions
+// are never redriven when the resource is only read.
+const redriveDeltaPath = "Metadata.Annotations"
+
+// pendingRedriveSince returns the value of the
+// redriveFailedExecutionsSinceAnnotation annotation of the supplied
+// StateMachine, and true if it differs from Status.ObservedRedriveSince.
+func pendingRedriveSince(ko *svcapitypes.StateMachine) (string, bool) {
+	value, ok := ko.Annotations[redriveFailedExecutionsSinceAnnotation]
+	if !ok || (ko.Status.ObservedRedriveSince != nil && *ko.Status.ObservedRedriveSince == value) {
+		return "", false
+	}
+	return value, true
+}
+
+// redriveFailedExecutions redrives the FAILED executions of the state machine
+// requested by the redriveFailedExecutionsSinceAnnotation annotation, and
+// records the handled annotation value in Status.ObservedRedriveSince. An
+// invalid annotation is reported with a Warning event rather than an error,
+// as it does not affect the state machine itself. It must be called with the
+// updateLocks mutex of the StateMachine held: a reconcile that read the
+// StateMachine before Status.ObservedRedriveSince was recorded lists the
+// executions again once the mutex is released, and skips those already
+// redriven since they are no longer FAILED or have a later RedriveDate.
+func (rm *resourceManager) redriveFailedExecutions(
+	ctx context.Context,
+	ko *svcapitypes.StateMachine,
+) (err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.redriveFailedExecutions")
+	defer func() {
+		exit(err)
+	}()
+
+	value, ok := pendingRedriveSince(ko)
+	if !ok {
+		return nil
+	}
+	since, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		commonutil.RecordEvent(ko, corev1.EventTypeWarning, "InvalidRedriveAnnotation",
+			"Invalid %s annotation %q, expected an RFC 3339 timestamp",
+			redriveFailedExecutionsSinceAnnotation, value)
+		ko.Status.ObservedRedriveSince = &value
+		return nil
+	}
+	// EXPRESS executions cannot be listed nor redriven.
+	if ko.Spec.Type != nil && *ko.Spec.Type == string(svcsdktypes.StateMachineTypeExpress) {
+		ko.Status.ObservedRedriveSince = &value
+		return nil
+	}
+
+	stateMachineARN := string(*ko.Status.ACKResourceMetadata.ARN)
+	input := &svcsdk.ListExecutionsInput{
+		StateMachineArn: &stateMachineARN,
+		StatusFilter:    svcsdktypes.ExecutionStatusFailed,
+	}
+	redriven := 0
+	for {
+		var resp *svcsdk.ListExecutionsOutput
+		resp, err = rm.sdkapi.ListExecutions(ctx, input)
+		rm.metrics.RecordAPICall("READ_MANY", "ListExecutions", err)
+		if err != nil {
+			return err
+		}
+		for _, execution := range resp.Executions {
+			if !redriveCandidate(execution, since) {
+				continue
+			}
+			_, err = rm.sdkapi.RedriveExecution(
+				ctx,
+				&svcsdk.RedriveExecutionInput{
+					ExecutionArn: execution.ExecutionArn,
+				},
+			)
+			rm.metrics.RecordAPICall("UPDATE", "RedriveExecution", err)
+			if err != nil {
+				var awsErr smithy.APIError
+				if errors.As(err, &awsErr) && awsErr.ErrorCode() == "ExecutionNotRedrivable" {
+					rlog.Debug("not redriving execution", "execution", *execution.ExecutionArn, "reason", awsErr.ErrorMessage())
+					continue
+				}
+				return err
+			}
+			redriven++
+		}
+		if resp.NextToken == nil {
+			break
+		}
+		input.NextToken = resp.NextToken
+	}
+	if redriven > 0 {
+		commonutil.RecordEvent(ko, corev1.EventTypeNormal, "RedrivenExecutions",
+			"Redrove %d executions that failed since %s", redriven, value)
+	}
+	ko.Status.ObservedRedriveSince = &value
+	return nil
+}
+
+// redriveCandidate returns true if the supplied FAILED execution stopped at
+// or after since and was not redriven since then.
+func redriveCandidate(execution svcsdktypes.ExecutionListItem, since time.Time) bool {
+	if execution.StopDate == nil || execution.StopDate.Before(since) {
+		return false
+	}
+	return execution.RedriveDate == nil || execution.RedriveDate.Before(since)
+}
+
+// SetupRedriveAnnotationWatch makes the supplied StateMachine reconciler run
+// when the redriveFailedExecutionsSinceAnnotation annotation of a
+// StateMachine is set or changed. The ACK runtime only reconciles
+// StateMachines when their generation changes, which annotations do not
+// affect. The executions are redriven by customUpdateStateMachine while it
+// holds the updateLocks mutex of the StateMachine, so a reconcile of this
+// controller and one of the ACK runtime controller do not redrive them
+// concurrently.
+func SetupRedriveAnnotationWatch(
+	mgr ctrlrt.Manager,
+	reconciler reconcile.Reconciler,
+) error {
+	return ctrlrt.NewControllerManagedBy(
+		mgr,
+	).Named(
+		"statemachine-redrive",
+	).For(
+		&svcapitypes.StateMachine{},
+		builder.WithPredicates(predicate.Funcs{
+			CreateFunc: func(event.CreateEvent) bool { return false },
+			DeleteFunc: func(event.DeleteEvent) bool { return false },
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				oldValue, oldOk := e.ObjectOld.GetAnnotations()[redriveFailedExecutionsSinceAnnotation]
+				newValue, newOk := e.ObjectNew.GetAnnotations()[redriveFailedExecutionsSinceAnnotation]
+				return newOk && (!oldOk || oldValue != newValue)
+			},
+			GenericFunc: func(event.GenericEvent) bool { return false },
+		}),
+	).Complete(reconciler)
+}
@@ -0,0 +1,138 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package state_machine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/sfn/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+)
+
+func TestPendingRedriveSince(t *testing.T) {
+	const since = "2024-03-01T12:00:00Z"
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		observed    *string
+		want        string
+		wantOk      bool
+	}{
+		{
+			name: "no annotation",
+		},
+		{
+			name:        "other annotations",
+			annotations: map[string]string{"team": "payments"},
+		},
+		{
+			name:        "new annotation",
+			annotations: map[string]string{redriveFailedExecutionsSinceAnnotation: since},
+			want:        since,
+			wantOk:      true,
+		},
+		{
+			name:        "changed annotation",
+			annotations: map[string]string{redriveFailedExecutionsSinceAnnotation: since},
+			observed:    aws.String("2024-02-01T00:00:00Z"),
+			want:        since,
+			wantOk:      true,
+		},
+		{
+			name:        "handled annotation",
+			annotations: map[string]string{redriveFailedExecutionsSinceAnnotation: since},
+			observed:    aws.String(since),
+		},
+		{
+			name:        "invalid annotation",
+			annotations: map[string]string{redriveFailedExecutionsSinceAnnotation: "yesterday"},
+			want:        "yesterday",
+			wantOk:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ko := &svcapitypes.StateMachine{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations},
+			}
+			ko.Status.ObservedRedriveSince = tt.observed
+			got, ok := pendingRedriveSince(ko)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("pendingRedriveSince() = (%q, %v), want (%q, %v)", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestRedriveCandidate(t *testing.T) {
+	since := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	before := since.Add(-time.Minute)
+	after := since.Add(time.Minute)
+
+	tests := []struct {
+		name        string
+		stopDate    *time.Time
+		redriveDate *time.Time
+		want        bool
+	}{
+		{
+			name: "no stop date",
+		},
+		{
+			name:     "failed before since",
+			stopDate: &before,
+		},
+		{
+			name:     "failed at since",
+			stopDate: &since,
+			want:     true,
+		},
+		{
+			name:     "failed after since",
+			stopDate: &after,
+			want:     true,
+		},
+		{
+			name:        "redriven before since",
+			stopDate:    &after,
+			redriveDate: &before,
+			want:        true,
+		},
+		{
+			name:        "redriven at since",
+			stopDate:    &after,
+			redriveDate: &since,
+		},
+		{
+			name:        "redriven after since and failed again",
+			stopDate:    &after,
+			redriveDate: &after,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			execution := svcsdktypes.ExecutionListItem{
+				StopDate:    tt.stopDate,
+				RedriveDate: tt.redriveDate,
+			}
+			if got := redriveCandidate(execution, since); got != tt.want {
+				t.Errorf("redriveCandidate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
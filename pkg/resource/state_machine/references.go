@@ -28,12 +28,18 @@ import (
 	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
 	ackrt "github.com/aws-controllers-k8s/runtime/pkg/runtime"
 	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	"github.com/aws/aws-sdk-go-v2/aws"
 
 	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+	commonutil "github.com/aws-controllers-k8s/sfn-controller/pkg/util"
 )
 
+// +kubebuilder:rbac:groups=cloudwatchlogs.services.k8s.aws,resources=loggroups,verbs=get;list
+// +kubebuilder:rbac:groups=cloudwatchlogs.services.k8s.aws,resources=loggroups/status,verbs=get;list
 // +kubebuilder:rbac:groups=iam.services.k8s.aws,resources=roles,verbs=get;list
 // +kubebuilder:rbac:groups=iam.services.k8s.aws,resources=roles/status,verbs=get;list
+// +kubebuilder:rbac:groups=kms.services.k8s.aws,resources=keys,verbs=get;list
+// +kubebuilder:rbac:groups=kms.services.k8s.aws,resources=keys/status,verbs=get;list
 
 // ClearResolvedReferences removes any reference values that were made
 // concrete in the spec. It returns a copy of the input AWSResource which
@@ -42,6 +48,22 @@ import (
 func (rm *resourceManager) ClearResolvedReferences(res acktypes.AWSResource) acktypes.AWSResource {
 	ko := rm.concreteResource(res).ko.DeepCopy()
 
+	if ko.Spec.EncryptionConfiguration != nil {
+		if ko.Spec.EncryptionConfiguration.KMSKeyRef != nil {
+			ko.Spec.EncryptionConfiguration.KMSKeyID = nil
+		}
+	}
+
+	if ko.Spec.LoggingConfiguration != nil {
+		for _, f0iter := range ko.Spec.LoggingConfiguration.Destinations {
+			if f0iter != nil && f0iter.CloudWatchLogsLogGroup != nil {
+				if f0iter.CloudWatchLogsLogGroup.LogGroupRef != nil {
+					f0iter.CloudWatchLogsLogGroup.LogGroupARN = nil
+				}
+			}
+		}
+	}
+
 	if ko.Spec.RoleRef != nil {
 		ko.Spec.RoleARN = nil
 	}
@@ -65,6 +87,18 @@ func (rm *resourceManager) ResolveReferences(
 
 	resourceHasReferences := false
 	err := validateReferenceFields(ko)
+	if fieldHasReferences, err := rm.resolveReferenceForEncryptionConfiguration_KMSKeyID(ctx, apiReader, ko); err != nil {
+		return &resource{ko}, (resourceHasReferences || fieldHasReferences), err
+	} else {
+		resourceHasReferences = resourceHasReferences || fieldHasReferences
+	}
+
+	if fieldHasReferences, err := rm.resolveReferenceForLoggingConfiguration_Destinations_CloudWatchLogsLogGroup_LogGroupARN(ctx, apiReader, ko); err != nil {
+		return &resource{ko}, (resourceHasReferences || fieldHasReferences), err
+	} else {
+		resourceHasReferences = resourceHasReferences || fieldHasReferences
+	}
+
 	if fieldHasReferences, err := rm.resolveReferenceForRoleARN(ctx, apiReader, ko); err != nil {
 		return &resource{ko}, (resourceHasReferences || fieldHasReferences), err
 	} else {
@@ -78,15 +112,116 @@ func (rm *resourceManager) ResolveReferences(
 // identifier field.
 func validateReferenceFields(ko *svcapitypes.StateMachine) error {
 
+	if ko.Spec.EncryptionConfiguration != nil {
+		if ko.Spec.EncryptionConfiguration.KMSKeyRef != nil && ko.Spec.EncryptionConfiguration.KMSKeyID != nil {
+			return ackerr.ResourceReferenceAndIDNotSupportedFor("EncryptionConfiguration.KMSKeyID", "EncryptionConfiguration.KMSKeyRef")
+		}
+	}
+
 	if ko.Spec.RoleRef != nil && ko.Spec.RoleARN != nil {
 		return ackerr.ResourceReferenceAndIDNotSupportedFor("RoleARN", "RoleRef")
 	}
-	if ko.Spec.RoleRef == nil && ko.Spec.RoleARN == nil {
-		return ackerr.ResourceReferenceOrIDRequiredFor("RoleARN", "RoleRef")
+	if ko.Spec.LoggingConfiguration != nil {
+		for _, f0iter := range ko.Spec.LoggingConfiguration.Destinations {
+			if f0iter != nil && f0iter.CloudWatchLogsLogGroup != nil {
+				if f0iter.CloudWatchLogsLogGroup.LogGroupRef != nil && f0iter.CloudWatchLogsLogGroup.LogGroupARN != nil {
+					return ackerr.ResourceReferenceAndIDNotSupportedFor("LoggingConfiguration.Destinations.CloudWatchLogsLogGroup.LogGroupARN", "LoggingConfiguration.Destinations.CloudWatchLogsLogGroup.LogGroupRef")
+				}
+			}
+		}
 	}
+
 	return nil
 }
 
+// resolveReferenceForEncryptionConfiguration_KMSKeyID reads the resource
+// referenced from EncryptionConfiguration.KMSKeyRef field and sets the
+// EncryptionConfiguration.KMSKeyID from referenced resource. Returns a
+// boolean indicating whether a reference contains references, or an error
+func (rm *resourceManager) resolveReferenceForEncryptionConfiguration_KMSKeyID(
+	ctx context.Context,
+	apiReader client.Reader,
+	ko *svcapitypes.StateMachine,
+) (hasReferences bool, err error) {
+	if ko.Spec.EncryptionConfiguration == nil {
+		return false, nil
+	}
+	if ko.Spec.EncryptionConfiguration.KMSKeyRef != nil && ko.Spec.EncryptionConfiguration.KMSKeyRef.From != nil {
+		hasReferences = true
+		arr := ko.Spec.EncryptionConfiguration.KMSKeyRef.From
+		if arr.Name == nil || *arr.Name == "" {
+			return hasReferences, fmt.Errorf("provided resource reference is nil or empty: EncryptionConfiguration.KMSKeyRef")
+		}
+		namespace, err := ackrt.ResolveCrossNamespaceReference(
+			ctx,
+			rm.cfg.EnableCrossNamespace,
+			&ko.Status.Conditions,
+			ackrt.CrossNamespaceRefKindResource,
+			ko.ObjectMeta.GetNamespace(),
+			arr.Namespace,
+			*arr.Name,
+		)
+		if err != nil {
+			return hasReferences, err
+		}
+		keyARN, err := commonutil.GetReferencedResourceARN(ctx, apiReader, commonutil.KMSKeyGVK, *arr.Name, namespace)
+		if err != nil {
+			return hasReferences, err
+		}
+		ko.Spec.EncryptionConfiguration.KMSKeyID = &keyARN
+	}
+
+	return hasReferences, nil
+}
+
+// resolveReferenceForLoggingConfiguration_Destinations_CloudWatchLogsLogGroup_LogGroupARN
+// reads the resources referenced from the
+// LoggingConfiguration.Destinations.CloudWatchLogsLogGroup.LogGroupRef fields
+// and sets the LoggingConfiguration.Destinations.CloudWatchLogsLogGroup.LogGroupARN
+// from the referenced resources, with the :* suffix Step Functions expects.
+// Returns a boolean indicating whether a reference contains references, or
+// an error
+func (rm *resourceManager) resolveReferenceForLoggingConfiguration_Destinations_CloudWatchLogsLogGroup_LogGroupARN(
+	ctx context.Context,
+	apiReader client.Reader,
+	ko *svcapitypes.StateMachine,
+) (hasReferences bool, err error) {
+	if ko.Spec.LoggingConfiguration == nil {
+		return false, nil
+	}
+	for _, f0iter := range ko.Spec.LoggingConfiguration.Destinations {
+		if f0iter == nil || f0iter.CloudWatchLogsLogGroup == nil {
+			continue
+		}
+		if f0iter.CloudWatchLogsLogGroup.LogGroupRef != nil && f0iter.CloudWatchLogsLogGroup.LogGroupRef.From != nil {
+			hasReferences = true
+			arr := f0iter.CloudWatchLogsLogGroup.LogGroupRef.From
+			if arr.Name == nil || *arr.Name == "" {
+				return hasReferences, fmt.Errorf("provided resource reference is nil or empty: LoggingConfiguration.Destinations.CloudWatchLogsLogGroup.LogGroupRef")
+			}
+			namespace, err := ackrt.ResolveCrossNamespaceReference(
+				ctx,
+				rm.cfg.EnableCrossNamespace,
+				&ko.Status.Conditions,
+				ackrt.CrossNamespaceRefKindResource,
+				ko.ObjectMeta.GetNamespace(),
+				arr.Namespace,
+				*arr.Name,
+			)
+			if err != nil {
+				return hasReferences, err
+			}
+			logGroupARN, err := commonutil.GetReferencedResourceARN(ctx, apiReader, commonutil.CloudWatchLogsLogGroupGVK, *arr.Name, namespace)
+			if err != nil {
+				return hasReferences, err
+			}
+			f0iter.CloudWatchLogsLogGroup.LogGroupARN = aws.String(logGroupDestinationARN(logGroupARN))
+		}
+	}
+
+	return hasReferences, nil
+}
+
 // resolveReferenceForRoleARN reads the resource referenced
 // from RoleRef field and sets the RoleARN
 // from referenced resource. Returns a boolean indicating whether a reference
@@ -0,0 +1,135 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package state_machine
+
+import (
+	"context"
+	"testing"
+
+	iamapitypes "github.com/aws-controllers-k8s/iam-controller/apis/v1alpha1"
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+	commonutil "github.com/aws-controllers-k8s/sfn-controller/pkg/util"
+)
+
+const testRoleARN = "arn:aws:iam::111111111111:role/sfn-default-hello"
+
+// syncedRole returns the iam-controller Role generated for the StateMachine
+// default/hello, synced with the supplied trust policy.
+func syncedRole(trustPolicy string) *iamapitypes.Role {
+	roleARN := ackv1alpha1.AWSResourceName(testRoleARN)
+	return &iamapitypes.Role{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "hello" + executionRoleSuffix},
+		Spec:       iamapitypes.RoleSpec{AssumeRolePolicyDocument: aws.String(trustPolicy)},
+		Status: iamapitypes.RoleStatus{
+			ACKResourceMetadata: &ackv1alpha1.ResourceMetadata{ARN: &roleARN},
+			Conditions: []*ackv1alpha1.Condition{{
+				Type:   ackv1alpha1.ConditionTypeResourceSynced,
+				Status: corev1.ConditionTrue,
+			}},
+		},
+	}
+}
+
+func TestClearResolvedReferencesExecutionRole(t *testing.T) {
+	rm := &resourceManager{
+		awsAccountID: testAccountID,
+		awsRegion:    testRegion,
+	}
+	scheme := runtime.NewScheme()
+	if err := iamapitypes.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	serviceAccount := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "default",
+			Name:        "workload",
+			Annotations: map[string]string{serviceAccountRoleAnnotation: testRoleARN},
+		},
+	}
+	commonutil.SetAPIReader(fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(syncedRole(rm.executionRoleTrustPolicy()), serviceAccount).
+		Build())
+	defer commonutil.SetAPIReader(nil)
+
+	tests := []struct {
+		name string
+		spec svcapitypes.StateMachineSpec
+	}{
+		{
+			name: "generated role",
+			spec: svcapitypes.StateMachineSpec{
+				ExecutionRoleGeneration: &svcapitypes.ExecutionRoleGeneration{},
+			},
+		},
+		{
+			name: "service account role",
+			spec: svcapitypes.StateMachineSpec{
+				RoleServiceAccountRef: &svcapitypes.ServiceAccountReference{Name: aws.String("workload")},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ko := &svcapitypes.StateMachine{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "hello"},
+				Spec:       tt.spec,
+			}
+			ko.Spec.Definition = aws.String(`{"StartAt": "Pass", "States": {"Pass": {"Type": "Pass", "End": true}}}`)
+			desired := &resource{ko.DeepCopy()}
+			resolved, _, err := rm.ResolveReferences(context.TODO(), commonutil.APIReader(), desired)
+			if err != nil {
+				t.Fatalf("ResolveReferences() error = %v", err)
+			}
+			resolvedKo := rm.concreteResource(resolved).ko
+			if err := rm.resolveCustomReferences(context.TODO(), resolvedKo); err != nil {
+				t.Fatalf("resolveCustomReferences() error = %v", err)
+			}
+			if got := aws.ToString(executionRoleARN(resolvedKo)); got != testRoleARN {
+				t.Fatalf("executionRoleARN() = %q, want %q", got, testRoleARN)
+			}
+
+			cleared := rm.concreteResource(rm.ClearResolvedReferences(resolved)).ko
+			if cleared.Spec.RoleARN != nil {
+				t.Errorf("ClearResolvedReferences() RoleARN = %q, want nil", *cleared.Spec.RoleARN)
+			}
+			if cleared.Spec.RoleRef != nil {
+				t.Errorf("ClearResolvedReferences() RoleRef = %v, want nil", cleared.Spec.RoleRef)
+			}
+			if !equality.Semantic.DeepEqual(cleared.Spec, ko.Spec) {
+				t.Errorf("ClearResolvedReferences() Spec = %+v, want %+v", cleared.Spec, ko.Spec)
+			}
+
+			// The role read from Step Functions compares equal to the
+			// resolved role.
+			latest := resolvedKo.DeepCopy()
+			latest.Spec.RoleARN = aws.String(testRoleARN)
+			setLatestRoleARN(resolvedKo, latest)
+			if latest.Spec.RoleARN != nil {
+				t.Errorf("setLatestRoleARN() RoleARN = %q, want nil", *latest.Spec.RoleARN)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package state_machine
+
+import (
+	"context"
+	"fmt"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	ackrt "github.com/aws-controllers-k8s/runtime/pkg/runtime"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+	commonutil "github.com/aws-controllers-k8s/sfn-controller/pkg/util"
+)
+
+// +kubebuilder:rbac:groups=dynamodb.services.k8s.aws,resources=tables,verbs=get;list
+// +kubebuilder:rbac:groups=dynamodb.services.k8s.aws,resources=tables/status,verbs=get;list
+// +kubebuilder:rbac:groups=lambda.services.k8s.aws,resources=functions,verbs=get;list
+// +kubebuilder:rbac:groups=lambda.services.k8s.aws,resources=functions/status,verbs=get;list
+// +kubebuilder:rbac:groups=sns.services.k8s.aws,resources=topics,verbs=get;list
+// +kubebuilder:rbac:groups=sns.services.k8s.aws,resources=topics/status,verbs=get;list
+// +kubebuilder:rbac:groups=sqs.services.k8s.aws,resources=queues,verbs=get;list
+// +kubebuilder:rbac:groups=sqs.services.k8s.aws,resources=queues/status,verbs=get;list
+
+// resolveReferenceForResourceRefs reads the resources referenced from the
+// ResourceRefs field and adds their ARNs to
+// Status.DefinitionSubstitutionValues under their placeholder. Returns a
+// boolean indicating whether a reference contains references, or an error
+func (rm *resourceManager) resolveReferenceForResourceRefs(
+	ctx context.Context,
+	apiReader client.Reader,
+	ko *svcapitypes.StateMachine,
+) (hasReferences bool, err error) {
+	for _, ref := range ko.Spec.ResourceRefs {
+		if ref == nil {
+			continue
+		}
+		hasReferences = true
+		if ref.Kind == nil || ref.Placeholder == nil || ref.From == nil || ref.From.Name == nil || *ref.From.Name == "" {
+			return hasReferences, fmt.Errorf("provided resource reference is nil or empty: ResourceRefs")
+		}
+		if _, ok := ko.Spec.DefinitionSubstitutions[*ref.Placeholder]; ok {
+			return hasReferences, ackerr.NewTerminalError(fmt.Errorf(
+				"placeholder %s is set in both resourceRefs and definitionSubstitutions", *ref.Placeholder,
+			))
+		}
+		namespace, err := ackrt.ResolveCrossNamespaceReference(
+			ctx,
+			rm.cfg.EnableCrossNamespace,
+			&ko.Status.Conditions,
+			ackrt.CrossNamespaceRefKindResource,
+			ko.ObjectMeta.GetNamespace(),
+			ref.From.Namespace,
+			*ref.From.Name,
+		)
+		if err != nil {
+			return hasReferences, err
+		}
+		arn, err := rm.getResourceRefARN(ctx, apiReader, ko, *ref.Kind, *ref.From.Name, namespace)
+		if err != nil {
+			return hasReferences, err
+		}
+		if ko.Status.DefinitionSubstitutionValues == nil {
+			ko.Status.DefinitionSubstitutionValues = map[string]*string{}
+		}
+		ko.Status.DefinitionSubstitutionValues[*ref.Placeholder] = &arn
+	}
+	return hasReferences, nil
+}
+
+// getResourceRefARN returns the ARN of a resource referenced from
+// ResourceRefs once it is synced.
+func (rm *resourceManager) getResourceRefARN(
+	ctx context.Context,
+	apiReader client.Reader,
+	ko *svcapitypes.StateMachine,
+	kind string,
+	name string, // the Kubernetes name of the referenced resource
+	namespace string, // the Kubernetes namespace of the referenced resource
+) (string, error) {
+	switch kind {
+	case "Activity":
+		obj := &svcapitypes.Activity{}
+		if err := getReferencedResourceState_Activity(ctx, apiReader, obj, name, namespace); err != nil {
+			return "", err
+		}
+		return string(*obj.Status.ACKResourceMetadata.ARN), nil
+	case "StateMachine":
+		if name == ko.ObjectMeta.GetName() && namespace == ko.ObjectMeta.GetNamespace() {
+			return "", ackerr.NewTerminalError(fmt.Errorf("state machine %s/%s cannot reference itself", namespace, name))
+		}
+		obj := &svcapitypes.StateMachine{}
+		if err := getReferencedResourceState_StateMachine(ctx, apiReader, obj, name, namespace); err != nil {
+			return "", err
+		}
+		return string(*obj.Status.ACKResourceMetadata.ARN), nil
+	case "Function":
+		return commonutil.GetReferencedResourceARN(ctx, apiReader, commonutil.LambdaFunctionGVK, name, namespace)
+	case "Queue":
+		return commonutil.GetReferencedResourceARN(ctx, apiReader, commonutil.SQSQueueGVK, name, namespace)
+	case "Table":
+		return commonutil.GetReferencedResourceARN(ctx, apiReader, commonutil.DynamoDBTableGVK, name, namespace)
+	case "Topic":
+		return commonutil.GetReferencedResourceARN(ctx, apiReader, commonutil.SNSTopicGVK, name, namespace)
+	default:
+		return "", ackerr.NewTerminalError(fmt.Errorf("unsupported resource reference kind %q", kind))
+	}
+}
+
+// getReferencedResourceState_Activity looks up whether a referenced resource
+// exists and is in a ACK.ResourceSynced=True state. If the referenced resource does exist and is
+// in a Synced state, returns nil, otherwise returns `ackerr.ResourceReferenceTerminalFor` or
+// `ResourceReferenceNotSyncedFor` depending on if the resource is in a Terminal state.
+func getReferencedResourceState_Activity(
+	ctx context.Context,
+	apiReader client.Reader,
+	obj *svcapitypes.Activity,
+	name string, // the Kubernetes name of the referenced resource
+	namespace string, // the Kubernetes namespace of the referenced resource
+) error {
+	namespacedName := types.NamespacedName{
+		Namespace: namespace,
+		Name:      name,
+	}
+	err := apiReader.Get(ctx, namespacedName, obj)
+	if err != nil {
+		return err
+	}
+	return checkReferencedResourceState("Activity", obj.Status.Conditions, obj.Status.ACKResourceMetadata, name, namespace)
+}
+
+// getReferencedResourceState_StateMachine looks up whether a referenced resource
+// exists and is in a ACK.ResourceSynced=True state. If the referenced resource does exist and is
+// in a Synced state, returns nil, otherwise returns `ackerr.ResourceReferenceTerminalFor` or
+// `ResourceReferenceNotSyncedFor` depending on if the resource is in a Terminal state.
+func getReferencedResourceState_StateMachine(
+	ctx context.Context,
+	apiReader client.Reader,
+	obj *svcapitypes.StateMachine,
+	name string, // the Kubernetes name of the referenced resource
+	namespace string, // the Kubernetes namespace of the referenced resource
+) error {
+	namespacedName := types.NamespacedName{
+		Namespace: namespace,
+		Name:      name,
+	}
+	err := apiReader.Get(ctx, namespacedName, obj)
+	if err != nil {
+		return err
+	}
+	return checkReferencedResourceState("StateMachine", obj.Status.Conditions, obj.Status.ACKResourceMetadata, name, namespace)
+}
+
+// checkReferencedResourceState returns an error unless the referenced
+// resource is synced, not terminal and has an ARN.
+func checkReferencedResourceState(
+	kind string,
+	conditions []*ackv1alpha1.Condition,
+	metadata *ackv1alpha1.ResourceMetadata,
+	name string,
+	namespace string,
+) error {
+	for _, cond := range conditions {
+		if cond.Type == ackv1alpha1.ConditionTypeTerminal &&
+			cond.Status == corev1.ConditionTrue {
+			return ackerr.ResourceReferenceTerminalFor(
+				kind,
+				namespace, name)
+		}
+	}
+	var refResourceSynced bool
+	for _, cond := range conditions {
+		if cond.Type == ackv1alpha1.ConditionTypeResourceSynced &&
+			cond.Status == corev1.ConditionTrue {
+			refResourceSynced = true
+		}
+	}
+	if !refResourceSynced {
+		return ackerr.ResourceReferenceNotSyncedFor(
+			kind,
+			namespace, name)
+	}
+	if metadata == nil || metadata.ARN == nil {
+		return ackerr.ResourceReferenceMissingTargetFieldFor(
+			kind,
+			namespace, name,
+			"Status.ACKResourceMetadata.ARN")
+	}
+	return nil
+}
@@ -19,6 +19,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
 	"strings"
 
@@ -63,6 +64,16 @@ func (rm *resourceManager) sdkFind(
 	defer func() {
 		exit(err)
 	}()
+	// The objects referenced by a StateMachine that is being deleted may
+	// already be gone, they are only needed to create or update it.
+	if !r.IsBeingDeleted() {
+		if err := rm.resolveCustomReferences(ctx, r.ko); err != nil {
+			return r, err
+		}
+		if err := rm.writeGeneratedObjects(ctx, r.ko); err != nil {
+			return r, err
+		}
+	}
 	// If any required fields in the input shape are missing, AWS resource is
 	// not created yet. Return NotFound here to indicate to callers that the
 	// resource isn't yet created.
@@ -100,6 +111,25 @@ func (rm *resourceManager) sdkFind(
 	} else {
 		ko.Spec.Definition = nil
 	}
+	if resp.EncryptionConfiguration != nil {
+		f3 := &svcapitypes.EncryptionConfiguration{}
+		if resp.EncryptionConfiguration.KmsDataKeyReusePeriodSeconds != nil {
+			kmsDataKeyReusePeriodSecondsCopy := int64(*resp.EncryptionConfiguration.KmsDataKeyReusePeriodSeconds)
+			f3.KMSDataKeyReusePeriodSeconds = &kmsDataKeyReusePeriodSecondsCopy
+		}
+		if resp.EncryptionConfiguration.KmsKeyId != nil {
+			f3.KMSKeyID = resp.EncryptionConfiguration.KmsKeyId
+		}
+		if resp.EncryptionConfiguration.Type != "" {
+			f3.Type = aws.String(string(resp.EncryptionConfiguration.Type))
+		}
+		if r.ko.Spec.EncryptionConfiguration != nil {
+			f3.KMSKeyRef = r.ko.Spec.EncryptionConfiguration.KMSKeyRef
+		}
+		ko.Spec.EncryptionConfiguration = f3
+	} else {
+		ko.Spec.EncryptionConfiguration = nil
+	}
 	if resp.LoggingConfiguration != nil {
 		f5 := &svcapitypes.LoggingConfiguration{}
 		if resp.LoggingConfiguration.Destinations != nil {
@@ -130,6 +160,11 @@ func (rm *resourceManager) sdkFind(
 	} else {
 		ko.Spec.Name = nil
 	}
+	if resp.RevisionId != nil {
+		ko.Status.RevisionID = resp.RevisionId
+	} else {
+		ko.Status.RevisionID = nil
+	}
 	if resp.RoleArn != nil {
 		ko.Spec.RoleARN = resp.RoleArn
 	} else {
@@ -159,6 +194,19 @@ func (rm *resourceManager) sdkFind(
 	if err := rm.setResourceAdditionalFields(ctx, ko); err != nil {
 		return nil, err
 	}
+	setLatestDefinition(r.ko, ko)
+	setLatestLogGroupRefs(r.ko, ko)
+	setLatestRoleARN(r.ko, ko)
+	// Versions expire without the state machine changing, the retention
+	// policy is applied whenever the state machine is read.
+	if !r.IsBeingDeleted() {
+		if err := rm.pruneStateMachineVersions(ctx, &resource{ko}); err != nil {
+			return nil, err
+		}
+	}
+	// The condition is only reported, a blocking diagnostic is returned
+	// by the update that validates the definition.
+	_ = setDefinitionValidCondition(ko)
 	return &resource{ko}, nil
 }
 
@@ -198,10 +246,21 @@ func (rm *resourceManager) sdkCreate(
 	defer func() {
 		exit(err)
 	}()
+	if err := validateLoggingConfiguration(desired.ko); err != nil {
+		return desired, err
+	}
+	if err := rm.validateDefinition(ctx, desired); err != nil {
+		return desired, err
+	}
 	input, err := rm.newCreateRequestPayload(ctx, desired)
 	if err != nil {
 		return nil, err
 	}
+	input.Definition, err = resolveDefinition(desired.ko)
+	if err != nil {
+		return nil, err
+	}
+	input.RoleArn = executionRoleARN(desired.ko)
 
 	var resp *svcsdk.CreateStateMachineOutput
 	_ = resp
@@ -226,6 +285,11 @@ func (rm *resourceManager) sdkCreate(
 		arn := ackv1alpha1.AWSResourceName(*resp.StateMachineArn)
 		ko.Status.ACKResourceMetadata.ARN = &arn
 	}
+	if resp.StateMachineVersionArn != nil {
+		ko.Status.LatestVersionARN = resp.StateMachineVersionArn
+	} else {
+		ko.Status.LatestVersionARN = nil
+	}
 
 	rm.setStatusDefaults(ko)
 	return &resource{ko}, nil
@@ -242,61 +306,85 @@ func (rm *resourceManager) newCreateRequestPayload(
 	if r.ko.Spec.Definition != nil {
 		res.Definition = r.ko.Spec.Definition
 	}
+	if r.ko.Spec.EncryptionConfiguration != nil {
+		f1 := &svcsdktypes.EncryptionConfiguration{}
+		if r.ko.Spec.EncryptionConfiguration.KMSDataKeyReusePeriodSeconds != nil {
+			kmsDataKeyReusePeriodSecondsCopy0 := *r.ko.Spec.EncryptionConfiguration.KMSDataKeyReusePeriodSeconds
+			if kmsDataKeyReusePeriodSecondsCopy0 > math.MaxInt32 || kmsDataKeyReusePeriodSecondsCopy0 < math.MinInt32 {
+				return nil, fmt.Errorf("error: field KmsDataKeyReusePeriodSeconds is of type int32")
+			}
+			kmsDataKeyReusePeriodSecondsCopy := int32(kmsDataKeyReusePeriodSecondsCopy0)
+			f1.KmsDataKeyReusePeriodSeconds = &kmsDataKeyReusePeriodSecondsCopy
+		}
+		if r.ko.Spec.EncryptionConfiguration.KMSKeyID != nil {
+			f1.KmsKeyId = r.ko.Spec.EncryptionConfiguration.KMSKeyID
+		}
+		if r.ko.Spec.EncryptionConfiguration.Type != nil {
+			f1.Type = svcsdktypes.EncryptionType(*r.ko.Spec.EncryptionConfiguration.Type)
+		}
+		res.EncryptionConfiguration = f1
+	}
 	if r.ko.Spec.LoggingConfiguration != nil {
-		f1 := &svcsdktypes.LoggingConfiguration{}
+		f2 := &svcsdktypes.LoggingConfiguration{}
 		if r.ko.Spec.LoggingConfiguration.Destinations != nil {
-			f1f0 := []svcsdktypes.LogDestination{}
-			for _, f1f0iter := range r.ko.Spec.LoggingConfiguration.Destinations {
-				f1f0elem := &svcsdktypes.LogDestination{}
-				if f1f0iter.CloudWatchLogsLogGroup != nil {
-					f1f0elemf0 := &svcsdktypes.CloudWatchLogsLogGroup{}
-					if f1f0iter.CloudWatchLogsLogGroup.LogGroupARN != nil {
-						f1f0elemf0.LogGroupArn = f1f0iter.CloudWatchLogsLogGroup.LogGroupARN
+			f2f0 := []svcsdktypes.LogDestination{}
+			for _, f2f0iter := range r.ko.Spec.LoggingConfiguration.Destinations {
+				f2f0elem := &svcsdktypes.LogDestination{}
+				if f2f0iter.CloudWatchLogsLogGroup != nil {
+					f2f0elemf0 := &svcsdktypes.CloudWatchLogsLogGroup{}
+					if f2f0iter.CloudWatchLogsLogGroup.LogGroupARN != nil {
+						f2f0elemf0.LogGroupArn = f2f0iter.CloudWatchLogsLogGroup.LogGroupARN
 					}
-					f1f0elem.CloudWatchLogsLogGroup = f1f0elemf0
+					f2f0elem.CloudWatchLogsLogGroup = f2f0elemf0
 				}
-				f1f0 = append(f1f0, *f1f0elem)
+				f2f0 = append(f2f0, *f2f0elem)
 			}
-			f1.Destinations = f1f0
+			f2.Destinations = f2f0
 		}
 		if r.ko.Spec.LoggingConfiguration.IncludeExecutionData != nil {
-			f1.IncludeExecutionData = *r.ko.Spec.LoggingConfiguration.IncludeExecutionData
+			f2.IncludeExecutionData = *r.ko.Spec.LoggingConfiguration.IncludeExecutionData
 		}
 		if r.ko.Spec.LoggingConfiguration.Level != nil {
-			f1.Level = svcsdktypes.LogLevel(*r.ko.Spec.LoggingConfiguration.Level)
+			f2.Level = svcsdktypes.LogLevel(*r.ko.Spec.LoggingConfiguration.Level)
 		}
-		res.LoggingConfiguration = f1
+		res.LoggingConfiguration = f2
 	}
 	if r.ko.Spec.Name != nil {
 		res.Name = r.ko.Spec.Name
 	}
+	if r.ko.Spec.Publish != nil {
+		res.Publish = *r.ko.Spec.Publish
+	}
 	if r.ko.Spec.RoleARN != nil {
 		res.RoleArn = r.ko.Spec.RoleARN
 	}
 	if r.ko.Spec.Tags != nil {
-		f4 := []svcsdktypes.Tag{}
-		for _, f4iter := range r.ko.Spec.Tags {
-			f4elem := &svcsdktypes.Tag{}
-			if f4iter.Key != nil {
-				f4elem.Key = f4iter.Key
+		f6 := []svcsdktypes.Tag{}
+		for _, f6iter := range r.ko.Spec.Tags {
+			f6elem := &svcsdktypes.Tag{}
+			if f6iter.Key != nil {
+				f6elem.Key = f6iter.Key
 			}
-			if f4iter.Value != nil {
-				f4elem.Value = f4iter.Value
+			if f6iter.Value != nil {
+				f6elem.Value = f6iter.Value
 			}
-			f4 = append(f4, *f4elem)
+			f6 = append(f6, *f6elem)
 		}
-		res.Tags = f4
+		res.Tags = f6
 	}
 	if r.ko.Spec.TracingConfiguration != nil {
-		f5 := &svcsdktypes.TracingConfiguration{}
+		f7 := &svcsdktypes.TracingConfiguration{}
 		if r.ko.Spec.TracingConfiguration.Enabled != nil {
-			f5.Enabled = *r.ko.Spec.TracingConfiguration.Enabled
+			f7.Enabled = *r.ko.Spec.TracingConfiguration.Enabled
 		}
-		res.TracingConfiguration = f5
+		res.TracingConfiguration = f7
 	}
 	if r.ko.Spec.Type != nil {
 		res.Type = svcsdktypes.StateMachineType(*r.ko.Spec.Type)
 	}
+	if r.ko.Spec.VersionDescription != nil {
+		res.VersionDescription = r.ko.Spec.VersionDescription
+	}
 
 	return res, nil
 }
@@ -449,6 +537,26 @@ func (rm *resourceManager) updateConditions(
 // and if the exception indicates that it is a Terminal exception
 // 'Terminal' exception are specified in generator configuration
 func (rm *resourceManager) terminalAWSError(err error) bool {
-	// No terminal_errors specified for this resource in generator config
-	return false
+	if err == nil {
+		return false
+	}
+
+	var terminalErr smithy.APIError
+	if !errors.As(err, &terminalErr) {
+		return false
+	}
+	switch terminalErr.ErrorCode() {
+	case "InvalidArn",
+		"InvalidDefinition",
+		"InvalidEncryptionConfiguration",
+		"InvalidLoggingConfiguration",
+		"InvalidName",
+		"InvalidTracingConfiguration",
+		"StateMachineTypeNotSupported",
+		"TooManyTags",
+		"ValidationException":
+		return true
+	default:
+		return false
+	}
 }
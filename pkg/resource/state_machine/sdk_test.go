@@ -0,0 +1,100 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package state_machine
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/sfn/types"
+	smithy "github.com/aws/smithy-go"
+	corev1 "k8s.io/api/core/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+)
+
+func TestTerminalAWSError(t *testing.T) {
+	rm := &resourceManager{}
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{"InvalidArn", true},
+		{"InvalidDefinition", true},
+		{"InvalidEncryptionConfiguration", true},
+		{"InvalidLoggingConfiguration", true},
+		{"InvalidName", true},
+		{"InvalidTracingConfiguration", true},
+		{"StateMachineTypeNotSupported", true},
+		{"TooManyTags", true},
+		{"ValidationException", true},
+		{"StateMachineDeleting", false},
+		{"StateMachineLimitExceeded", false},
+		{"ThrottlingException", false},
+		{"AccessDeniedException", false},
+	}
+	for _, tt := range tests {
+		err := fmt.Errorf("operation error: %w", &smithy.GenericAPIError{Code: tt.code, Message: "failed"})
+		if got := rm.terminalAWSError(err); got != tt.want {
+			t.Errorf("terminalAWSError(%s) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+	if rm.terminalAWSError(errors.New("connection reset by peer")) {
+		t.Error("terminalAWSError() = true for an error that is not an AWS error")
+	}
+	if rm.terminalAWSError(nil) {
+		t.Error("terminalAWSError(nil) = true")
+	}
+}
+
+func TestUpdateConditionsTerminal(t *testing.T) {
+	rm := &resourceManager{}
+	tests := []struct {
+		name         string
+		err          error
+		wantCondType ackv1alpha1.ConditionType
+	}{
+		{
+			name:         "invalid definition",
+			err:          &svcsdktypes.InvalidDefinition{Message: aws.String("Invalid State Machine Definition")},
+			wantCondType: ackv1alpha1.ConditionTypeTerminal,
+		},
+		{
+			name:         "throttling",
+			err:          &smithy.GenericAPIError{Code: "ThrottlingException", Message: "Rate exceeded"},
+			wantCondType: ackv1alpha1.ConditionTypeRecoverable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &resource{&svcapitypes.StateMachine{}}
+			updated, ok := rm.updateConditions(r, false, tt.err)
+			if !ok {
+				t.Fatal("updateConditions() did not update the conditions")
+			}
+			var cond *ackv1alpha1.Condition
+			for _, c := range rm.concreteResource(updated).ko.Status.Conditions {
+				if c.Type == tt.wantCondType {
+					cond = c
+				}
+			}
+			if cond == nil || cond.Status != corev1.ConditionTrue {
+				t.Errorf("updateConditions() condition %s = %+v, want True", tt.wantCondType, cond)
+			}
+		})
+	}
+}
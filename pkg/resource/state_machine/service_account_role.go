@@ -0,0 +1,80 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package state_machine
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+)
+
+// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch
+
+const (
+	// serviceAccountRoleAnnotation is the annotation EKS reads the IAM role
+	// for service accounts from.
+	serviceAccountRoleAnnotation = "eks.amazonaws.com/role-arn"
+
+	definitionSourceServiceAccount = "ServiceAccount"
+)
+
+// resolveReferenceForRoleServiceAccountRef reads the ServiceAccount
+// referenced from the RoleServiceAccountRef field and sets
+// Status.ResolvedRoleARN from its eks.amazonaws.com/role-arn annotation. A
+// missing or invalid annotation is a terminal error, the StateMachine is
+// reconciled again when the ServiceAccount changes, see
+// SetupDefinitionSourceWatch. Returns a boolean indicating whether a
+// reference contains references, or an error
+func (rm *resourceManager) resolveReferenceForRoleServiceAccountRef(
+	ctx context.Context,
+	apiReader client.Reader,
+	ko *svcapitypes.StateMachine,
+) (hasReferences bool, err error) {
+	ref := ko.Spec.RoleServiceAccountRef
+	if ref == nil {
+		return false, nil
+	}
+	hasReferences = true
+	if ref.Name == nil || *ref.Name == "" {
+		return hasReferences, fmt.Errorf("provided resource reference is nil or empty: RoleServiceAccountRef")
+	}
+	sa := &corev1.ServiceAccount{}
+	key := types.NamespacedName{Namespace: ko.Namespace, Name: *ref.Name}
+	if err := apiReader.Get(ctx, key, sa); err != nil {
+		return hasReferences, err
+	}
+	roleARN := sa.Annotations[serviceAccountRoleAnnotation]
+	if roleARN == "" {
+		err := fmt.Errorf(
+			"ServiceAccount %s/%s has no %s annotation",
+			ko.Namespace, *ref.Name, serviceAccountRoleAnnotation,
+		)
+		return hasReferences, executionRoleTerminalError(ko, "ServiceAccountRoleNotFound", err)
+	}
+	if parsed, err := arn.Parse(roleARN); err != nil || parsed.Service != "iam" {
+		err := fmt.Errorf(
+			"%s annotation of ServiceAccount %s/%s is not an IAM role ARN: %q",
+			serviceAccountRoleAnnotation, ko.Namespace, *ref.Name, roleARN,
+		)
+		return hasReferences, executionRoleTerminalError(ko, "ServiceAccountRoleNotFound", err)
+	}
+	ko.Status.ResolvedRoleARN = &roleARN
+	return hasReferences, nil
+}
@@ -0,0 +1,192 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package state_machine
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	iamapitypes "github.com/aws-controllers-k8s/iam-controller/apis/v1alpha1"
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+)
+
+// stepFunctionsPrincipal is the service principal that assumes the execution
+// role of a state machine.
+const stepFunctionsPrincipal = "states.amazonaws.com"
+
+// trustStatement is a statement of an assume-role policy document. Fields
+// that accept a string or a list of strings are decoded into stringList.
+type trustStatement struct {
+	Effect    string                           `json:"Effect"`
+	Action    stringList                       `json:"Action"`
+	Principal json.RawMessage                  `json:"Principal"`
+	Condition map[string]map[string]stringList `json:"Condition"`
+}
+
+// stringList decodes an IAM policy element that is either a string or a
+// list of strings.
+type stringList []string
+
+func (l *stringList) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*l = []string{s}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(b, &list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+// checkRoleTrust returns an error describing why the supplied Role cannot be
+// used as the execution role of a state machine in the supplied region and
+// account, or nil if its assumeRolePolicyDocument allows Step Functions to
+// assume it.
+func checkRoleTrust(role *iamapitypes.Role, region string, accountID string) error {
+	name := role.Namespace + "/" + role.Name
+	if role.Spec.AssumeRolePolicyDocument == nil || *role.Spec.AssumeRolePolicyDocument == "" {
+		return fmt.Errorf("role %s has no assumeRolePolicyDocument", name)
+	}
+	document := strings.TrimSpace(*role.Spec.AssumeRolePolicyDocument)
+	if !strings.HasPrefix(document, "{") {
+		// IAM returns URL encoded policy documents.
+		if decoded, err := url.QueryUnescape(document); err == nil {
+			document = decoded
+		}
+	}
+	var trust struct {
+		Statement json.RawMessage `json:"Statement"`
+	}
+	if err := json.Unmarshal([]byte(document), &trust); err != nil {
+		return fmt.Errorf("assumeRolePolicyDocument of role %s is not valid JSON: %v", name, err)
+	}
+	var statements []trustStatement
+	if err := json.Unmarshal(trust.Statement, &statements); err != nil {
+		var statement trustStatement
+		if err := json.Unmarshal(trust.Statement, &statement); err != nil {
+			return fmt.Errorf("assumeRolePolicyDocument of role %s has invalid statements: %v", name, err)
+		}
+		statements = []trustStatement{statement}
+	}
+
+	var allowed bool
+	var sourceAccounts []string
+	for _, statement := range statements {
+		if !allowsAssumeRole(statement.Action) || !trustsStepFunctions(statement.Principal, region) {
+			continue
+		}
+		if strings.EqualFold(statement.Effect, "Deny") {
+			return fmt.Errorf(
+				"assumeRolePolicyDocument of role %s denies sts:AssumeRole to %s",
+				name, stepFunctionsPrincipal,
+			)
+		}
+		if !strings.EqualFold(statement.Effect, "Allow") {
+			continue
+		}
+		accounts := statementSourceAccounts(statement.Condition)
+		if accounts == nil || containsString(accounts, accountID) {
+			allowed = true
+		} else {
+			sourceAccounts = append(sourceAccounts, accounts...)
+		}
+	}
+	if allowed {
+		return nil
+	}
+	if len(sourceAccounts) > 0 {
+		return fmt.Errorf(
+			"assumeRolePolicyDocument of role %s only allows %s to assume it for account(s) %s, not %s",
+			name, stepFunctionsPrincipal, strings.Join(sourceAccounts, ", "), accountID,
+		)
+	}
+	return fmt.Errorf(
+		"assumeRolePolicyDocument of role %s does not allow %s to call sts:AssumeRole",
+		name, stepFunctionsPrincipal,
+	)
+}
+
+// allowsAssumeRole returns true if the actions of a statement include
+// sts:AssumeRole.
+func allowsAssumeRole(actions stringList) bool {
+	for _, action := range actions {
+		switch strings.ToLower(action) {
+		case "sts:assumerole", "sts:*", "*":
+			return true
+		}
+	}
+	return false
+}
+
+// trustsStepFunctions returns true if the principal of a statement includes
+// the global or regional Step Functions service principal.
+func trustsStepFunctions(raw json.RawMessage, region string) bool {
+	var wildcard string
+	if err := json.Unmarshal(raw, &wildcard); err == nil {
+		return wildcard == "*"
+	}
+	var principal struct {
+		Service stringList `json:"Service"`
+	}
+	if err := json.Unmarshal(raw, &principal); err != nil {
+		return false
+	}
+	regional := "states." + region + ".amazonaws.com"
+	for _, service := range principal.Service {
+		if service == stepFunctionsPrincipal || service == regional {
+			return true
+		}
+	}
+	return false
+}
+
+// statementSourceAccounts returns the accounts a statement is restricted to
+// with a StringEquals aws:SourceAccount condition, or nil.
+func statementSourceAccounts(conditions map[string]map[string]stringList) []string {
+	for key, values := range conditions["StringEquals"] {
+		if strings.EqualFold(key, "aws:SourceAccount") {
+			return values
+		}
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// executionRoleTerminalError sets a Terminal condition with the supplied
+// reason explaining why the referenced execution role cannot be used, and
+// returns err as a terminal error. The StateMachine is reconciled again when
+// it changes, or when the ServiceAccount of RoleServiceAccountRef changes,
+// see SetupDefinitionSourceWatch.
+func executionRoleTerminalError(ko *svcapitypes.StateMachine, reason string, err error) error {
+	message := err.Error()
+	ackcondition.SetTerminal(&resource{ko}, corev1.ConditionTrue, &message, &reason)
+	return ackerr.NewTerminalError(err)
+}
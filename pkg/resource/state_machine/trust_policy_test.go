@@ -0,0 +1,206 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package state_machine
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	iamapitypes "github.com/aws-controllers-k8s/iam-controller/apis/v1alpha1"
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+)
+
+const (
+	testRegion    = "us-west-2"
+	testAccountID = "111111111111"
+)
+
+func roleWithTrustPolicy(document *string) *iamapitypes.Role {
+	return &iamapitypes.Role{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "role"},
+		Spec:       iamapitypes.RoleSpec{AssumeRolePolicyDocument: document},
+	}
+}
+
+func TestCheckRoleTrust(t *testing.T) {
+	allowStepFunctions := `{"Version": "2012-10-17", "Statement": [{
+		"Effect": "Allow",
+		"Principal": {"Service": "states.amazonaws.com"},
+		"Action": "sts:AssumeRole"}]}`
+	tests := []struct {
+		name     string
+		document *string
+		// wantErr is a substring of the expected error, or empty if the
+		// role is trusted.
+		wantErr string
+	}{
+		{
+			name:    "no document",
+			wantErr: "has no assumeRolePolicyDocument",
+		},
+		{
+			name:     "empty document",
+			document: aws.String(""),
+			wantErr:  "has no assumeRolePolicyDocument",
+		},
+		{
+			name:     "invalid JSON",
+			document: aws.String(`{"Statement": [`),
+			wantErr:  "is not valid JSON",
+		},
+		{
+			name:     "invalid statements",
+			document: aws.String(`{"Statement": "sts:AssumeRole"}`),
+			wantErr:  "has invalid statements",
+		},
+		{
+			name:     "string Action and Service",
+			document: aws.String(allowStepFunctions),
+		},
+		{
+			name: "list Action and Service",
+			document: aws.String(`{"Statement": [{
+				"Effect": "Allow",
+				"Principal": {"Service": ["lambda.amazonaws.com", "states.amazonaws.com"]},
+				"Action": ["sts:TagSession", "sts:AssumeRole"]}]}`),
+		},
+		{
+			name: "single statement object",
+			document: aws.String(`{"Statement": {
+				"Effect": "Allow",
+				"Principal": {"Service": "states.amazonaws.com"},
+				"Action": "sts:AssumeRole"}}`),
+		},
+		{
+			name: "wildcard Action and Principal",
+			document: aws.String(`{"Statement": [{
+				"Effect": "Allow",
+				"Principal": "*",
+				"Action": "sts:*"}]}`),
+		},
+		{
+			name:     "URL encoded document",
+			document: aws.String(url.QueryEscape(allowStepFunctions)),
+		},
+		{
+			name: "regional principal",
+			document: aws.String(`{"Statement": [{
+				"Effect": "Allow",
+				"Principal": {"Service": "states.us-west-2.amazonaws.com"},
+				"Action": "sts:AssumeRole"}]}`),
+		},
+		{
+			name: "principal of another region",
+			document: aws.String(`{"Statement": [{
+				"Effect": "Allow",
+				"Principal": {"Service": "states.eu-west-1.amazonaws.com"},
+				"Action": "sts:AssumeRole"}]}`),
+			wantErr: "does not allow states.amazonaws.com to call sts:AssumeRole",
+		},
+		{
+			name: "other service principal",
+			document: aws.String(`{"Statement": [{
+				"Effect": "Allow",
+				"Principal": {"Service": "lambda.amazonaws.com"},
+				"Action": "sts:AssumeRole"}]}`),
+			wantErr: "does not allow states.amazonaws.com to call sts:AssumeRole",
+		},
+		{
+			name: "other action",
+			document: aws.String(`{"Statement": [{
+				"Effect": "Allow",
+				"Principal": {"Service": "states.amazonaws.com"},
+				"Action": "sts:TagSession"}]}`),
+			wantErr: "does not allow states.amazonaws.com to call sts:AssumeRole",
+		},
+		{
+			name: "Deny statement",
+			document: aws.String(`{"Statement": [
+				{"Effect": "Allow", "Principal": {"Service": "states.amazonaws.com"}, "Action": "sts:AssumeRole"},
+				{"Effect": "Deny", "Principal": {"Service": "states.amazonaws.com"}, "Action": "sts:AssumeRole"}]}`),
+			wantErr: "denies sts:AssumeRole to states.amazonaws.com",
+		},
+		{
+			name: "SourceAccount condition matches",
+			document: aws.String(`{"Statement": [{
+				"Effect": "Allow",
+				"Principal": {"Service": "states.amazonaws.com"},
+				"Action": "sts:AssumeRole",
+				"Condition": {"StringEquals": {"aws:SourceAccount": ["222222222222", "111111111111"]}}}]}`),
+		},
+		{
+			name: "SourceAccount condition does not match",
+			document: aws.String(`{"Statement": [{
+				"Effect": "Allow",
+				"Principal": {"Service": "states.amazonaws.com"},
+				"Action": "sts:AssumeRole",
+				"Condition": {"StringEquals": {"aws:SourceAccount": "222222222222"}}}]}`),
+			wantErr: "only allows states.amazonaws.com to assume it for account(s) 222222222222, not 111111111111",
+		},
+		{
+			name: "other statement without SourceAccount condition",
+			document: aws.String(`{"Statement": [
+				{"Effect": "Allow", "Principal": {"Service": "states.amazonaws.com"}, "Action": "sts:AssumeRole",
+				 "Condition": {"StringEquals": {"aws:SourceAccount": "222222222222"}}},
+				{"Effect": "Allow", "Principal": {"Service": "states.amazonaws.com"}, "Action": "sts:AssumeRole"}]}`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkRoleTrust(roleWithTrustPolicy(tt.document), testRegion, testAccountID)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("checkRoleTrust() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("checkRoleTrust() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExecutionRoleTerminalError(t *testing.T) {
+	ko := &svcapitypes.StateMachine{}
+	cause := errors.New("role default/role is not trusted")
+	err := executionRoleTerminalError(ko, "ExecutionRoleNotTrusted", cause)
+
+	var terminal *ackerr.TerminalError
+	if !errors.As(err, &terminal) || !errors.Is(err, cause) {
+		t.Fatalf("executionRoleTerminalError() = %#v, want a terminal error wrapping %v", err, cause)
+	}
+	var condition *ackv1alpha1.Condition
+	for _, c := range ko.Status.Conditions {
+		if c.Type == ackv1alpha1.ConditionTypeTerminal {
+			condition = c
+		}
+	}
+	if condition == nil {
+		t.Fatal("no Terminal condition set")
+	}
+	if condition.Status != corev1.ConditionTrue ||
+		aws.ToString(condition.Reason) != "ExecutionRoleNotTrusted" ||
+		aws.ToString(condition.Message) != cause.Error() {
+		t.Errorf("unexpected Terminal condition %+v", condition)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package state_machine
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/sfn"
+	"k8s.io/apimachinery/pkg/types"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+)
+
+// updateLocks serializes the updates of each StateMachine. A StateMachine is
+// reconciled by the controller of the ACK runtime and by the controllers of
+// SetupDefinitionSourceWatch and SetupRedriveAnnotationWatch, each with its
+// own work queue, so the same StateMachine can be reconciled by two of them
+// at once.
+var updateLocks = &keyedMutex{locks: map[types.NamespacedName]*keyedLock{}}
+
+// keyedMutex is a set of mutexes indexed by the name of the object they
+// serialize the changes of. A mutex is dropped once it is not held nor
+// waited for.
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[types.NamespacedName]*keyedLock
+}
+
+type keyedLock struct {
+	sync.Mutex
+	refs int
+}
+
+// Lock locks the mutex of key and returns the function unlocking it.
+func (m *keyedMutex) Lock(key types.NamespacedName) func() {
+	m.mu.Lock()
+	lock, ok := m.locks[key]
+	if !ok {
+		lock = &keyedLock{}
+		m.locks[key] = lock
+	}
+	lock.refs++
+	m.mu.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+		m.mu.Lock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(m.locks, key)
+		}
+		m.mu.Unlock()
+	}
+}
+
+// checkRevision returns a requeue error if the state machine was updated
+// since latest was read, by a concurrent reconcile of the StateMachine, so
+// that the update is computed again from the new revision rather than
+// applied twice.
+func (rm *resourceManager) checkRevision(
+	ctx context.Context,
+	latest *svcapitypes.StateMachine,
+) error {
+	resp, err := rm.sdkapi.DescribeStateMachine(ctx, &svcsdk.DescribeStateMachineInput{
+		StateMachineArn: (*string)(latest.Status.ACKResourceMetadata.ARN),
+	})
+	rm.metrics.RecordAPICall("READ_ONE", "DescribeStateMachine", err)
+	if err != nil {
+		return err
+	}
+	if aws.ToString(resp.RevisionId) != aws.ToString(latest.Status.RevisionID) {
+		return ackrequeue.Needed(fmt.Errorf(
+			"state machine %s/%s was updated concurrently", latest.Namespace, latest.Name,
+		))
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package state_machine
+
+import (
+	"sync"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestKeyedMutex(t *testing.T) {
+	m := &keyedMutex{locks: map[types.NamespacedName]*keyedLock{}}
+	key := types.NamespacedName{Namespace: "default", Name: "hello"}
+
+	var wg sync.WaitGroup
+	inside, maxInside := 0, 0
+	var mu sync.Mutex
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock := m.Lock(key)
+			defer unlock()
+			mu.Lock()
+			inside++
+			if inside > maxInside {
+				maxInside = inside
+			}
+			mu.Unlock()
+			mu.Lock()
+			inside--
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	if maxInside != 1 {
+		t.Errorf("Lock() let %d holders in at once, want 1", maxInside)
+	}
+
+	// Different keys do not block each other.
+	unlock := m.Lock(key)
+	m.Lock(types.NamespacedName{Namespace: "default", Name: "world"})()
+	unlock()
+
+	if len(m.locks) != 0 {
+		t.Errorf("len(locks) = %d after unlocking, want 0", len(m.locks))
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package state_machine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/sfn"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/sfn/types"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+	commonutil "github.com/aws-controllers-k8s/sfn-controller/pkg/util"
+)
+
+// pruneStateMachineVersions deletes the published versions of the state
+// machine that are not retained by Spec.VersionRetention. A version is kept
+// if it is one of the KeepLast most recent versions, if it is younger than
+// MaxAge, if it is the latest published version, if any alias of the state
+// machine routes traffic to it or if a StateMachineVersion resource manages
+// it.
+func (rm *resourceManager) pruneStateMachineVersions(
+	ctx context.Context,
+	r *resource,
+) (err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.pruneStateMachineVersions")
+	defer func() {
+		exit(err)
+	}()
+
+	policy := r.ko.Spec.VersionRetention
+	if policy == nil || (policy.KeepLast == nil && policy.MaxAge == nil) {
+		return nil
+	}
+	if policy.KeepLast != nil && *policy.KeepLast < 0 {
+		return ackerr.NewTerminalError(
+			fmt.Errorf("versionRetention.keepLast must not be negative, got %d", *policy.KeepLast),
+		)
+	}
+	var maxAge time.Duration
+	if policy.MaxAge != nil {
+		maxAge, err = time.ParseDuration(*policy.MaxAge)
+		if err != nil {
+			return ackerr.NewTerminalError(
+				fmt.Errorf("invalid versionRetention.maxAge %q: %v", *policy.MaxAge, err),
+			)
+		}
+	}
+
+	stateMachineARN := string(*r.ko.Status.ACKResourceMetadata.ARN)
+	versions, err := rm.listStateMachineVersions(ctx, stateMachineARN)
+	if err != nil {
+		return err
+	}
+	// ListStateMachineVersions already returns the most recent versions
+	// first, sort anyway so KeepLast never depends on the API ordering.
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].CreationDate.After(*versions[j].CreationDate)
+	})
+
+	now := time.Now()
+	expired := []string{}
+	for i, version := range versions {
+		if policy.KeepLast != nil && int64(i) < *policy.KeepLast {
+			continue
+		}
+		if policy.MaxAge != nil && now.Sub(*version.CreationDate) < maxAge {
+			continue
+		}
+		if r.ko.Status.LatestVersionARN != nil && *r.ko.Status.LatestVersionARN == *version.StateMachineVersionArn {
+			continue
+		}
+		expired = append(expired, *version.StateMachineVersionArn)
+	}
+	if len(expired) == 0 {
+		return nil
+	}
+
+	aliasedVersions, err := rm.getAliasedVersionARNs(ctx, stateMachineARN)
+	if err != nil {
+		return err
+	}
+	managedVersions, err := getManagedVersionARNs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, versionARN := range expired {
+		if _, ok := aliasedVersions[versionARN]; ok {
+			rlog.Debug("not deleting state machine version referenced by an alias", "version", versionARN)
+			continue
+		}
+		if _, ok := managedVersions[versionARN]; ok {
+			rlog.Debug("not deleting state machine version managed by a StateMachineVersion", "version", versionARN)
+			continue
+		}
+		_, err = rm.sdkapi.DeleteStateMachineVersion(
+			ctx,
+			&svcsdk.DeleteStateMachineVersionInput{
+				StateMachineVersionArn: &versionARN,
+			},
+		)
+		rm.metrics.RecordAPICall("DELETE", "DeleteStateMachineVersion", err)
+		if err != nil {
+			return err
+		}
+		rlog.Debug("deleted state machine version", "version", versionARN)
+	}
+	return nil
+}
+
+// listStateMachineVersions returns all the published versions of a state
+// machine.
+func (rm *resourceManager) listStateMachineVersions(
+	ctx context.Context,
+	stateMachineARN string,
+) ([]svcsdktypes.StateMachineVersionListItem, error) {
+	versions := []svcsdktypes.StateMachineVersionListItem{}
+	input := &svcsdk.ListStateMachineVersionsInput{
+		StateMachineArn: &stateMachineARN,
+	}
+	for {
+		resp, err := rm.sdkapi.ListStateMachineVersions(ctx, input)
+		rm.metrics.RecordAPICall("READ_MANY", "ListStateMachineVersions", err)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, resp.StateMachineVersions...)
+		if resp.NextToken == nil {
+			return versions, nil
+		}
+		input.NextToken = resp.NextToken
+	}
+}
+
+// getAliasedVersionARNs returns the set of version ARNs that any alias of the
+// state machine routes traffic to.
+func (rm *resourceManager) getAliasedVersionARNs(
+	ctx context.Context,
+	stateMachineARN string,
+) (map[string]struct{}, error) {
+	aliasedVersions := map[string]struct{}{}
+	input := &svcsdk.ListStateMachineAliasesInput{
+		StateMachineArn: &stateMachineARN,
+	}
+	for {
+		resp, err := rm.sdkapi.ListStateMachineAliases(ctx, input)
+		rm.metrics.RecordAPICall("READ_MANY", "ListStateMachineAliases", err)
+		if err != nil {
+			return nil, err
+		}
+		for _, alias := range resp.StateMachineAliases {
+			aliasResp, err := rm.sdkapi.DescribeStateMachineAlias(
+				ctx,
+				&svcsdk.DescribeStateMachineAliasInput{
+					StateMachineAliasArn: alias.StateMachineAliasArn,
+				},
+			)
+			rm.metrics.RecordAPICall("READ_ONE", "DescribeStateMachineAlias", err)
+			if err != nil {
+				return nil, err
+			}
+			for _, route := range aliasResp.RoutingConfiguration {
+				if route.StateMachineVersionArn != nil {
+					aliasedVersions[*route.StateMachineVersionArn] = struct{}{}
+				}
+			}
+		}
+		if resp.NextToken == nil {
+			return aliasedVersions, nil
+		}
+		input.NextToken = resp.NextToken
+	}
+}
+
+// getManagedVersionARNs returns the set of version ARNs managed by
+// StateMachineVersion resources, which must not be deleted from under them.
+func getManagedVersionARNs(ctx context.Context) (map[string]struct{}, error) {
+	managedVersions := map[string]struct{}{}
+	kc := commonutil.KubeClient()
+	if kc == nil {
+		return nil, fmt.Errorf("kubernetes client is not set, cannot list StateMachineVersions")
+	}
+	list := &svcapitypes.StateMachineVersionList{}
+	if err := kc.List(ctx, list); err != nil {
+		return nil, err
+	}
+	for _, version := range list.Items {
+		metadata := version.Status.ACKResourceMetadata
+		if metadata != nil && metadata.ARN != nil {
+			managedVersions[string(*metadata.ARN)] = struct{}{}
+		}
+	}
+	return managedVersions, nil
+}
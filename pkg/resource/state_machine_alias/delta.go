@@ -56,6 +56,49 @@ func newResourceDelta(
 			delta.Add("Spec.Name", a.ko.Spec.Name, b.ko.Spec.Name)
 		}
 	}
+	if ackcompare.HasNilDifference(a.ko.Spec.Rollout, b.ko.Spec.Rollout) {
+		delta.Add("Spec.Rollout", a.ko.Spec.Rollout, b.ko.Spec.Rollout)
+	} else if a.ko.Spec.Rollout != nil && b.ko.Spec.Rollout != nil {
+		if ackcompare.HasNilDifference(a.ko.Spec.Rollout.Interval, b.ko.Spec.Rollout.Interval) {
+			delta.Add("Spec.Rollout.Interval", a.ko.Spec.Rollout.Interval, b.ko.Spec.Rollout.Interval)
+		} else if a.ko.Spec.Rollout.Interval != nil && b.ko.Spec.Rollout.Interval != nil {
+			if *a.ko.Spec.Rollout.Interval != *b.ko.Spec.Rollout.Interval {
+				delta.Add("Spec.Rollout.Interval", a.ko.Spec.Rollout.Interval, b.ko.Spec.Rollout.Interval)
+			}
+		}
+		if ackcompare.HasNilDifference(a.ko.Spec.Rollout.Rollback, b.ko.Spec.Rollout.Rollback) {
+			delta.Add("Spec.Rollout.Rollback", a.ko.Spec.Rollout.Rollback, b.ko.Spec.Rollout.Rollback)
+		} else if a.ko.Spec.Rollout.Rollback != nil && b.ko.Spec.Rollout.Rollback != nil {
+			if ackcompare.HasNilDifference(a.ko.Spec.Rollout.Rollback.FailureThresholdPercentage, b.ko.Spec.Rollout.Rollback.FailureThresholdPercentage) {
+				delta.Add("Spec.Rollout.Rollback.FailureThresholdPercentage", a.ko.Spec.Rollout.Rollback.FailureThresholdPercentage, b.ko.Spec.Rollout.Rollback.FailureThresholdPercentage)
+			} else if a.ko.Spec.Rollout.Rollback.FailureThresholdPercentage != nil && b.ko.Spec.Rollout.Rollback.FailureThresholdPercentage != nil {
+				if *a.ko.Spec.Rollout.Rollback.FailureThresholdPercentage != *b.ko.Spec.Rollout.Rollback.FailureThresholdPercentage {
+					delta.Add("Spec.Rollout.Rollback.FailureThresholdPercentage", a.ko.Spec.Rollout.Rollback.FailureThresholdPercentage, b.ko.Spec.Rollout.Rollback.FailureThresholdPercentage)
+				}
+			}
+			if ackcompare.HasNilDifference(a.ko.Spec.Rollout.Rollback.MinimumExecutions, b.ko.Spec.Rollout.Rollback.MinimumExecutions) {
+				delta.Add("Spec.Rollout.Rollback.MinimumExecutions", a.ko.Spec.Rollout.Rollback.MinimumExecutions, b.ko.Spec.Rollout.Rollback.MinimumExecutions)
+			} else if a.ko.Spec.Rollout.Rollback.MinimumExecutions != nil && b.ko.Spec.Rollout.Rollback.MinimumExecutions != nil {
+				if *a.ko.Spec.Rollout.Rollback.MinimumExecutions != *b.ko.Spec.Rollout.Rollback.MinimumExecutions {
+					delta.Add("Spec.Rollout.Rollback.MinimumExecutions", a.ko.Spec.Rollout.Rollback.MinimumExecutions, b.ko.Spec.Rollout.Rollback.MinimumExecutions)
+				}
+			}
+		}
+		if ackcompare.HasNilDifference(a.ko.Spec.Rollout.StepPercentage, b.ko.Spec.Rollout.StepPercentage) {
+			delta.Add("Spec.Rollout.StepPercentage", a.ko.Spec.Rollout.StepPercentage, b.ko.Spec.Rollout.StepPercentage)
+		} else if a.ko.Spec.Rollout.StepPercentage != nil && b.ko.Spec.Rollout.StepPercentage != nil {
+			if *a.ko.Spec.Rollout.StepPercentage != *b.ko.Spec.Rollout.StepPercentage {
+				delta.Add("Spec.Rollout.StepPercentage", a.ko.Spec.Rollout.StepPercentage, b.ko.Spec.Rollout.StepPercentage)
+			}
+		}
+		if ackcompare.HasNilDifference(a.ko.Spec.Rollout.Type, b.ko.Spec.Rollout.Type) {
+			delta.Add("Spec.Rollout.Type", a.ko.Spec.Rollout.Type, b.ko.Spec.Rollout.Type)
+		} else if a.ko.Spec.Rollout.Type != nil && b.ko.Spec.Rollout.Type != nil {
+			if *a.ko.Spec.Rollout.Type != *b.ko.Spec.Rollout.Type {
+				delta.Add("Spec.Rollout.Type", a.ko.Spec.Rollout.Type, b.ko.Spec.Rollout.Type)
+			}
+		}
+	}
 	if len(a.ko.Spec.RoutingConfiguration) != len(b.ko.Spec.RoutingConfiguration) {
 		delta.Add("Spec.RoutingConfiguration", a.ko.Spec.RoutingConfiguration, b.ko.Spec.RoutingConfiguration)
 	} else if len(a.ko.Spec.RoutingConfiguration) > 0 {
@@ -0,0 +1,197 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package state_machine_alias
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	ackrt "github.com/aws-controllers-k8s/runtime/pkg/runtime"
+	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/sfn"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/sfn/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+)
+
+// validateRoutingConfiguration validates the routing configuration once the
+// version references are resolved. It covers the checks that can't be done
+// by the CRD validation rules because the versions are referenced through
+// StateMachineVersionRef or StateMachineRef fields. The validating webhook
+// runs the same checks at admission when it is enabled, this covers the
+// references it could not resolve yet.
+func validateRoutingConfiguration(
+	ko *svcapitypes.StateMachineAlias,
+) error {
+	stateMachineARNs := map[string]struct{}{}
+	versionARNs := map[string]struct{}{}
+	for _, item := range ko.Spec.RoutingConfiguration {
+		if item.StateMachineVersionARN == nil {
+			continue
+		}
+		versionARN := *item.StateMachineVersionARN
+		if _, ok := versionARNs[versionARN]; ok {
+			return fmt.Errorf("routing configuration entries must route to different versions, %s is used twice", versionARN)
+		}
+		versionARNs[versionARN] = struct{}{}
+		stateMachineARN, err := stateMachineARNFromVersionARN(versionARN)
+		if err != nil {
+			return err
+		}
+		stateMachineARNs[stateMachineARN] = struct{}{}
+	}
+	if len(stateMachineARNs) > 1 {
+		return fmt.Errorf("routing configuration entries must route to versions of the same state machine")
+	}
+	return nil
+}
+
+// setRoutingConfigurationReferences copies the StateMachineVersionRef,
+// StateMachineRef and VersionNumber fields of the desired routing
+// configuration onto the routing entries returned by DescribeStateMachineAlias
+// that route to the same version, so that they don't show up in the delta.
+func setRoutingConfigurationReferences(
+	desired *svcapitypes.StateMachineAlias,
+	latest *svcapitypes.StateMachineAlias,
+) {
+	for _, latestItem := range latest.Spec.RoutingConfiguration {
+		if latestItem.StateMachineVersionARN == nil {
+			continue
+		}
+		for _, desiredItem := range desired.Spec.RoutingConfiguration {
+			if desiredItem == nil || desiredItem.StateMachineVersionARN == nil {
+				continue
+			}
+			if *desiredItem.StateMachineVersionARN == *latestItem.StateMachineVersionARN {
+				latestItem.StateMachineRef = desiredItem.StateMachineRef
+				latestItem.StateMachineVersionRef = desiredItem.StateMachineVersionRef
+				latestItem.VersionNumber = desiredItem.VersionNumber
+				break
+			}
+		}
+	}
+}
+
+// resolveReferenceForRoutingConfiguration_StateMachineRef reads the
+// StateMachine referenced from the RoutingConfiguration.StateMachineRef
+// fields and sets RoutingConfiguration.StateMachineVersionARN to the ARN of
+// version VersionNumber of that state machine. Returns a boolean indicating
+// whether a reference contains references, or an error
+func (rm *resourceManager) resolveReferenceForRoutingConfiguration_StateMachineRef(
+	ctx context.Context,
+	apiReader client.Reader,
+	ko *svcapitypes.StateMachineAlias,
+) (hasReferences bool, err error) {
+	for f0idx, f0iter := range ko.Spec.RoutingConfiguration {
+		if f0iter == nil || f0iter.StateMachineRef == nil || f0iter.StateMachineRef.From == nil {
+			continue
+		}
+		hasReferences = true
+		arr := f0iter.StateMachineRef.From
+		if arr.Name == nil || *arr.Name == "" {
+			return hasReferences, fmt.Errorf("provided resource reference is nil or empty: RoutingConfiguration.StateMachineRef")
+		}
+		namespace, err := ackrt.ResolveCrossNamespaceReference(
+			ctx,
+			rm.cfg.EnableCrossNamespace,
+			&ko.Status.Conditions,
+			ackrt.CrossNamespaceRefKindResource,
+			ko.ObjectMeta.GetNamespace(),
+			arr.Namespace,
+			*arr.Name,
+		)
+		if err != nil {
+			return hasReferences, err
+		}
+		if f0iter.VersionNumber == nil {
+			return hasReferences, fmt.Errorf("provided version number is nil: RoutingConfiguration.VersionNumber")
+		}
+		obj := &svcapitypes.StateMachine{}
+		if err := getReferencedResourceState_StateMachine(ctx, apiReader, obj, *arr.Name, namespace); err != nil {
+			return hasReferences, err
+		}
+		versionARN := fmt.Sprintf("%s:%d", *obj.Status.ACKResourceMetadata.ARN, *f0iter.VersionNumber)
+		ko.Spec.RoutingConfiguration[f0idx].StateMachineVersionARN = &versionARN
+	}
+
+	return hasReferences, nil
+}
+
+// updateStateMachineAlias patches the supplied resource in the backend AWS
+// service API and returns a new resource with updated fields.
+func (rm *resourceManager) updateStateMachineAlias(
+	ctx context.Context,
+	desired *resource,
+) (updated *resource, err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.updateStateMachineAlias")
+	defer func() {
+		exit(err)
+	}()
+	input, err := rm.newUpdateRequestPayload(ctx, desired)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = rm.sdkapi.UpdateStateMachineAlias(ctx, input)
+	rm.metrics.RecordAPICall("UPDATE", "UpdateStateMachineAlias", err)
+	if err != nil {
+		return nil, err
+	}
+	// Merge in the information we read from the API call above to the copy of
+	// the original Kubernetes object we passed to the function
+	ko := desired.ko.DeepCopy()
+
+	rm.setStatusDefaults(ko)
+	return &resource{ko}, nil
+}
+
+// newUpdateRequestPayload returns an SDK-specific struct for the HTTP request
+// payload of the Update API call for the resource
+func (rm *resourceManager) newUpdateRequestPayload(
+	ctx context.Context,
+	r *resource,
+) (*svcsdk.UpdateStateMachineAliasInput, error) {
+	res := &svcsdk.UpdateStateMachineAliasInput{}
+
+	if r.ko.Spec.Description != nil {
+		res.Description = r.ko.Spec.Description
+	}
+	if r.ko.Spec.RoutingConfiguration != nil {
+		f1 := []svcsdktypes.RoutingConfigurationListItem{}
+		for _, f1iter := range r.ko.Spec.RoutingConfiguration {
+			f1elem := &svcsdktypes.RoutingConfigurationListItem{}
+			if f1iter.StateMachineVersionARN != nil {
+				f1elem.StateMachineVersionArn = f1iter.StateMachineVersionARN
+			}
+			if f1iter.Weight != nil {
+				weightCopy0 := *f1iter.Weight
+				if weightCopy0 > math.MaxInt32 || weightCopy0 < math.MinInt32 {
+					return nil, fmt.Errorf("error: field weight is of type int32")
+				}
+				weightCopy := int32(weightCopy0)
+				f1elem.Weight = weightCopy
+			}
+			f1 = append(f1, *f1elem)
+		}
+		res.RoutingConfiguration = f1
+	}
+	if r.ko.Status.ACKResourceMetadata != nil && r.ko.Status.ACKResourceMetadata.ARN != nil {
+		res.StateMachineAliasArn = (*string)(r.ko.Status.ACKResourceMetadata.ARN)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,125 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package state_machine_alias
+
+import (
+	"testing"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+)
+
+func TestValidateRoutingConfiguration(t *testing.T) {
+	tests := []struct {
+		name    string
+		routing []*svcapitypes.RoutingConfigurationListItem
+		wantErr bool
+	}{
+		{
+			name:    "one version",
+			routing: []*svcapitypes.RoutingConfigurationListItem{routingTo(testVersion1ARN, 100)},
+		},
+		{
+			name: "two versions of the same state machine",
+			routing: []*svcapitypes.RoutingConfigurationListItem{
+				routingTo(testVersion1ARN, 90), routingTo(testVersion2ARN, 10),
+			},
+		},
+		{
+			name: "the same version twice",
+			routing: []*svcapitypes.RoutingConfigurationListItem{
+				routingTo(testVersion1ARN, 50), routingTo(testVersion1ARN, 50),
+			},
+			wantErr: true,
+		},
+		{
+			name: "versions of different state machines",
+			routing: []*svcapitypes.RoutingConfigurationListItem{
+				routingTo(testVersion1ARN, 50),
+				routingTo("arn:aws:states:us-west-2:111111111111:stateMachine:world:1", 50),
+			},
+			wantErr: true,
+		},
+		{
+			name: "alias ARN",
+			routing: []*svcapitypes.RoutingConfigurationListItem{
+				routingTo("arn:aws:states:us-west-2:111111111111:stateMachine:hello:live", 100),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ko := &svcapitypes.StateMachineAlias{}
+			ko.Spec.RoutingConfiguration = tt.routing
+			if err := validateRoutingConfiguration(ko); (err != nil) != tt.wantErr {
+				t.Errorf("validateRoutingConfiguration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetRoutingConfigurationReferences(t *testing.T) {
+	stateMachineRef := &ackv1alpha1.AWSResourceReferenceWrapper{
+		From: &ackv1alpha1.AWSResourceReference{Name: aws.String("hello")},
+	}
+	versionRef := &ackv1alpha1.AWSResourceReferenceWrapper{
+		From: &ackv1alpha1.AWSResourceReference{Name: aws.String("hello-v2")},
+	}
+
+	desired := &svcapitypes.StateMachineAlias{}
+	desired.Spec.RoutingConfiguration = []*svcapitypes.RoutingConfigurationListItem{
+		{
+			StateMachineRef:        stateMachineRef,
+			StateMachineVersionARN: aws.String(testVersion1ARN),
+			VersionNumber:          aws.Int64(1),
+			Weight:                 aws.Int64(90),
+		},
+		{
+			StateMachineVersionARN: aws.String(testVersion2ARN),
+			StateMachineVersionRef: versionRef,
+			Weight:                 aws.Int64(10),
+		},
+	}
+	// DescribeStateMachineAlias returns the entries in any order and
+	// without the references.
+	latest := &svcapitypes.StateMachineAlias{}
+	latest.Spec.RoutingConfiguration = []*svcapitypes.RoutingConfigurationListItem{
+		routingTo(testVersion2ARN, 10),
+		routingTo(testVersion1ARN, 90),
+	}
+	setRoutingConfigurationReferences(desired, latest)
+
+	want := []*svcapitypes.RoutingConfigurationListItem{
+		desired.Spec.RoutingConfiguration[1],
+		desired.Spec.RoutingConfiguration[0],
+	}
+	if !equality.Semantic.DeepEqual(latest.Spec.RoutingConfiguration, want) {
+		t.Errorf("setRoutingConfigurationReferences() routing = %+v, want %+v",
+			latest.Spec.RoutingConfiguration, want)
+	}
+
+	// Entries routing to versions that are not desired keep no references.
+	latest.Spec.RoutingConfiguration = []*svcapitypes.RoutingConfigurationListItem{
+		routingTo(testVersion3ARN, 100),
+	}
+	setRoutingConfigurationReferences(desired, latest)
+	if item := latest.Spec.RoutingConfiguration[0]; item.StateMachineRef != nil ||
+		item.StateMachineVersionRef != nil || item.VersionNumber != nil {
+		t.Errorf("setRoutingConfigurationReferences() set references on %+v", item)
+	}
+}
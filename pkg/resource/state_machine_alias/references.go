@@ -17,9 +17,15 @@ package state_machine_alias
 
 import (
 	"context"
+	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	ackrt "github.com/aws-controllers-k8s/runtime/pkg/runtime"
 	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
 
 	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
@@ -32,6 +38,15 @@ import (
 func (rm *resourceManager) ClearResolvedReferences(res acktypes.AWSResource) acktypes.AWSResource {
 	ko := rm.concreteResource(res).ko.DeepCopy()
 
+	for f0idx, f0iter := range ko.Spec.RoutingConfiguration {
+		if f0iter.StateMachineRef != nil {
+			ko.Spec.RoutingConfiguration[f0idx].StateMachineVersionARN = nil
+		}
+		if f0iter.StateMachineVersionRef != nil {
+			ko.Spec.RoutingConfiguration[f0idx].StateMachineVersionARN = nil
+		}
+	}
+
 	return &resource{ko}
 }
 
@@ -47,11 +62,192 @@ func (rm *resourceManager) ResolveReferences(
 	apiReader client.Reader,
 	res acktypes.AWSResource,
 ) (acktypes.AWSResource, bool, error) {
-	return res, false, nil
+	ko := rm.concreteResource(res).ko
+
+	resourceHasReferences := false
+	err := validateReferenceFields(ko)
+	if fieldHasReferences, err := rm.resolveReferenceForRoutingConfiguration_StateMachineRef(ctx, apiReader, ko); err != nil {
+		return &resource{ko}, (resourceHasReferences || fieldHasReferences), err
+	} else {
+		resourceHasReferences = resourceHasReferences || fieldHasReferences
+	}
+
+	if fieldHasReferences, err := rm.resolveReferenceForRoutingConfiguration_StateMachineVersionARN(ctx, apiReader, ko); err != nil {
+		return &resource{ko}, (resourceHasReferences || fieldHasReferences), err
+	} else {
+		resourceHasReferences = resourceHasReferences || fieldHasReferences
+	}
+
+	return &resource{ko}, resourceHasReferences, err
 }
 
 // validateReferenceFields validates the reference field and corresponding
 // identifier field.
 func validateReferenceFields(ko *svcapitypes.StateMachineAlias) error {
+
+	for _, f0iter := range ko.Spec.RoutingConfiguration {
+		if f0iter.StateMachineVersionRef != nil && f0iter.StateMachineVersionARN != nil {
+			return ackerr.ResourceReferenceAndIDNotSupportedFor("RoutingConfiguration.StateMachineVersionARN", "RoutingConfiguration.StateMachineVersionRef")
+		}
+		if f0iter.StateMachineRef != nil && f0iter.StateMachineVersionARN != nil {
+			return ackerr.ResourceReferenceAndIDNotSupportedFor("RoutingConfiguration.StateMachineVersionARN", "RoutingConfiguration.StateMachineRef")
+		}
+		if f0iter.StateMachineRef != nil && f0iter.StateMachineVersionRef != nil {
+			return ackerr.ResourceReferenceAndIDNotSupportedFor("RoutingConfiguration.StateMachineVersionRef", "RoutingConfiguration.StateMachineRef")
+		}
+		if f0iter.StateMachineRef == nil && f0iter.StateMachineVersionRef == nil && f0iter.StateMachineVersionARN == nil {
+			return ackerr.ResourceReferenceOrIDRequiredFor("RoutingConfiguration.StateMachineVersionARN", "RoutingConfiguration.StateMachineVersionRef", "RoutingConfiguration.StateMachineRef")
+		}
+		if (f0iter.StateMachineRef == nil) != (f0iter.VersionNumber == nil) {
+			return fmt.Errorf("RoutingConfiguration.StateMachineRef and RoutingConfiguration.VersionNumber must be specified together")
+		}
+	}
+	return nil
+}
+
+// resolveReferenceForRoutingConfiguration_StateMachineVersionARN reads the resource
+// referenced from RoutingConfiguration.StateMachineVersionRef field and sets the
+// RoutingConfiguration.StateMachineVersionARN from referenced resource. Returns a
+// boolean indicating whether a reference contains references, or an error
+func (rm *resourceManager) resolveReferenceForRoutingConfiguration_StateMachineVersionARN(
+	ctx context.Context,
+	apiReader client.Reader,
+	ko *svcapitypes.StateMachineAlias,
+) (hasReferences bool, err error) {
+	for f0idx, f0iter := range ko.Spec.RoutingConfiguration {
+		if f0iter.StateMachineVersionRef != nil && f0iter.StateMachineVersionRef.From != nil {
+			hasReferences = true
+			arr := f0iter.StateMachineVersionRef.From
+			if arr.Name == nil || *arr.Name == "" {
+				return hasReferences, fmt.Errorf("provided resource reference is nil or empty: RoutingConfiguration.StateMachineVersionRef")
+			}
+			namespace, err := ackrt.ResolveCrossNamespaceReference(
+				ctx,
+				rm.cfg.EnableCrossNamespace,
+				&ko.Status.Conditions,
+				ackrt.CrossNamespaceRefKindResource,
+				ko.ObjectMeta.GetNamespace(),
+				arr.Namespace,
+				*arr.Name,
+			)
+			if err != nil {
+				return hasReferences, err
+			}
+			obj := &svcapitypes.StateMachineVersion{}
+			if err := getReferencedResourceState_StateMachineVersion(ctx, apiReader, obj, *arr.Name, namespace); err != nil {
+				return hasReferences, err
+			}
+			ko.Spec.RoutingConfiguration[f0idx].StateMachineVersionARN = (*string)(obj.Status.ACKResourceMetadata.ARN)
+		}
+	}
+
+	return hasReferences, nil
+}
+
+// getReferencedResourceState_StateMachine looks up whether a referenced resource
+// exists and is in a ACK.ResourceSynced=True state. If the referenced resource does exist and is
+// in a Synced state, returns nil, otherwise returns `ackerr.ResourceReferenceTerminalFor` or
+// `ResourceReferenceNotSyncedFor` depending on if the resource is in a Terminal state.
+func getReferencedResourceState_StateMachine(
+	ctx context.Context,
+	apiReader client.Reader,
+	obj *svcapitypes.StateMachine,
+	name string, // the Kubernetes name of the referenced resource
+	namespace string, // the Kubernetes namespace of the referenced resource
+) error {
+	namespacedName := types.NamespacedName{
+		Namespace: namespace,
+		Name:      name,
+	}
+	err := apiReader.Get(ctx, namespacedName, obj)
+	if err != nil {
+		return err
+	}
+	var refResourceTerminal bool
+	for _, cond := range obj.Status.Conditions {
+		if cond.Type == ackv1alpha1.ConditionTypeTerminal &&
+			cond.Status == corev1.ConditionTrue {
+			return ackerr.ResourceReferenceTerminalFor(
+				"StateMachine",
+				namespace, name)
+		}
+	}
+	if refResourceTerminal {
+		return ackerr.ResourceReferenceTerminalFor(
+			"StateMachine",
+			namespace, name)
+	}
+	var refResourceSynced bool
+	for _, cond := range obj.Status.Conditions {
+		if cond.Type == ackv1alpha1.ConditionTypeResourceSynced &&
+			cond.Status == corev1.ConditionTrue {
+			refResourceSynced = true
+		}
+	}
+	if !refResourceSynced {
+		return ackerr.ResourceReferenceNotSyncedFor(
+			"StateMachine",
+			namespace, name)
+	}
+	if obj.Status.ACKResourceMetadata == nil || obj.Status.ACKResourceMetadata.ARN == nil {
+		return ackerr.ResourceReferenceMissingTargetFieldFor(
+			"StateMachine",
+			namespace, name,
+			"Status.ACKResourceMetadata.ARN")
+	}
+	return nil
+}
+
+// getReferencedResourceState_StateMachineVersion looks up whether a referenced resource
+// exists and is in a ACK.ResourceSynced=True state. If the referenced resource does exist and is
+// in a Synced state, returns nil, otherwise returns `ackerr.ResourceReferenceTerminalFor` or
+// `ResourceReferenceNotSyncedFor` depending on if the resource is in a Terminal state.
+func getReferencedResourceState_StateMachineVersion(
+	ctx context.Context,
+	apiReader client.Reader,
+	obj *svcapitypes.StateMachineVersion,
+	name string, // the Kubernetes name of the referenced resource
+	namespace string, // the Kubernetes namespace of the referenced resource
+) error {
+	namespacedName := types.NamespacedName{
+		Namespace: namespace,
+		Name:      name,
+	}
+	err := apiReader.Get(ctx, namespacedName, obj)
+	if err != nil {
+		return err
+	}
+	var refResourceTerminal bool
+	for _, cond := range obj.Status.Conditions {
+		if cond.Type == ackv1alpha1.ConditionTypeTerminal &&
+			cond.Status == corev1.ConditionTrue {
+			return ackerr.ResourceReferenceTerminalFor(
+				"StateMachineVersion",
+				namespace, name)
+		}
+	}
+	if refResourceTerminal {
+		return ackerr.ResourceReferenceTerminalFor(
+			"StateMachineVersion",
+			namespace, name)
+	}
+	var refResourceSynced bool
+	for _, cond := range obj.Status.Conditions {
+		if cond.Type == ackv1alpha1.ConditionTypeResourceSynced &&
+			cond.Status == corev1.ConditionTrue {
+			refResourceSynced = true
+		}
+	}
+	if !refResourceSynced {
+		return ackerr.ResourceReferenceNotSyncedFor(
+			"StateMachineVersion",
+			namespace, name)
+	}
+	if obj.Status.ACKResourceMetadata == nil || obj.Status.ACKResourceMetadata.ARN == nil {
+		return ackerr.ResourceReferenceMissingTargetFieldFor(
+			"StateMachineVersion",
+			namespace, name,
+			"Status.ACKResourceMetadata.ARN")
+	}
 	return nil
 }
@@ -0,0 +1,363 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package state_machine_alias
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/sfn"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/sfn/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+	commonutil "github.com/aws-controllers-k8s/sfn-controller/pkg/util"
+)
+
+const (
+	// rolloutSamplePeriod is how often the executions of the new version are
+	// sampled while waiting for the next rollout step, when a rollback policy
+	// is configured.
+	rolloutSamplePeriod = 30 * time.Second
+	// defaultRollbackMinimumExecutions is the number of completed executions
+	// required before evaluating the failure rate of the new version.
+	defaultRollbackMinimumExecutions = int64(10)
+)
+
+const (
+	// rolloutTypeCanary shifts StepPercentage of the traffic to the new
+	// version, then the remaining traffic after one interval.
+	rolloutTypeCanary = "Canary"
+	// rolloutTypeLinear shifts StepPercentage more traffic to the new
+	// version every interval.
+	rolloutTypeLinear = "Linear"
+)
+
+// customUpdateStateMachineAlias updates the state machine alias. When
+// Spec.Rollout is set and the routing configuration is changed to route all
+// traffic to a new version, the traffic is shifted to that version over
+// multiple reconciles instead, using Status.Rollout to keep track of the
+// progress.
+func (rm *resourceManager) customUpdateStateMachineAlias(
+	ctx context.Context,
+	desired *resource,
+	latest *resource,
+	delta *ackcompare.Delta,
+) (*resource, error) {
+	if err := validateRoutingConfiguration(desired.ko); err != nil {
+		return nil, ackerr.NewTerminalError(err)
+	}
+	targetVersionARN := rolloutTargetVersionARN(desired)
+	if desired.ko.Spec.Rollout == nil || targetVersionARN == nil {
+		return rm.updateStateMachineAlias(ctx, desired)
+	}
+	stableVersionARN := rolloutStableVersionARN(desired, latest, *targetVersionARN)
+	if stableVersionARN == nil {
+		return rm.updateStateMachineAlias(ctx, desired)
+	}
+	return rm.rolloutStateMachineAlias(ctx, desired, *stableVersionARN, *targetVersionARN)
+}
+
+// rolloutStateMachineAlias applies the next traffic shifting step of a
+// rollout from stableVersionARN to targetVersionARN, once the transition
+// time recorded in Status.Rollout is reached. It requeues the resource until
+// all of the traffic is routed to targetVersionARN.
+func (rm *resourceManager) rolloutStateMachineAlias(
+	ctx context.Context,
+	desired *resource,
+	stableVersionARN string,
+	targetVersionARN string,
+) (updated *resource, err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.rolloutStateMachineAlias")
+	defer func() {
+		exit(err)
+	}()
+
+	strategy := desired.ko.Spec.Rollout
+	interval, err := validateRolloutStrategy(strategy)
+	if err != nil {
+		return nil, ackerr.NewTerminalError(err)
+	}
+
+	ko := desired.ko.DeepCopy()
+	status := ko.Status.Rollout
+	if status == nil || status.TargetVersionARN == nil || *status.TargetVersionARN != targetVersionARN {
+		step := int64(0)
+		weight := int64(0)
+		status = &svcapitypes.RolloutStatus{
+			CurrentStep:      &step,
+			StableVersionARN: &stableVersionARN,
+			StartTime:        &metav1.Time{Time: time.Now()},
+			TargetVersionARN: &targetVersionARN,
+			TargetWeight:     &weight,
+		}
+	}
+	if status.RolledBack != nil && *status.RolledBack {
+		return &resource{ko}, ackerr.NewTerminalError(fmt.Errorf(
+			"rollout to %s was rolled back, route the alias to another version to start a new rollout",
+			targetVersionARN,
+		))
+	}
+	if strategy.Rollback != nil && *status.TargetWeight > 0 {
+		failed, completed, err := rm.countRolloutExecutions(ctx, desired, status)
+		if err != nil {
+			return nil, err
+		}
+		if rollbackThresholdExceeded(strategy.Rollback, failed, completed) {
+			return rm.rollbackStateMachineAlias(ctx, desired, status, failed, completed)
+		}
+	}
+	now := time.Now()
+	if status.NextTransitionTime != nil && now.Before(status.NextTransitionTime.Time) {
+		rlog.Debug("waiting for next rollout step", "next_transition_time", status.NextTransitionTime)
+		requeueAfter := status.NextTransitionTime.Sub(now)
+		if strategy.Rollback != nil && requeueAfter > rolloutSamplePeriod {
+			requeueAfter = rolloutSamplePeriod
+		}
+		return &resource{ko}, ackrequeue.NeededAfter(nil, requeueAfter)
+	}
+
+	step := *status.CurrentStep + 1
+	weight := rolloutWeight(*strategy.Type, *strategy.StepPercentage, step)
+	routing := []*svcapitypes.RoutingConfigurationListItem{{
+		StateMachineVersionARN: &targetVersionARN,
+		Weight:                 &weight,
+	}}
+	if weight < 100 {
+		stableWeight := 100 - weight
+		routing = append(routing, &svcapitypes.RoutingConfigurationListItem{
+			StateMachineVersionARN: status.StableVersionARN,
+			Weight:                 &stableWeight,
+		})
+	}
+	stepResource := &resource{desired.ko.DeepCopy()}
+	stepResource.ko.Spec.RoutingConfiguration = routing
+	if _, err = rm.updateStateMachineAlias(ctx, stepResource); err != nil {
+		return nil, err
+	}
+	rlog.Info("applied rollout step", "step", step, "target_version", targetVersionARN, "weight", weight)
+
+	status = status.DeepCopy()
+	status.CurrentStep = &step
+	status.TargetWeight = &weight
+	status.NextTransitionTime = nil
+	if weight < 100 {
+		status.NextTransitionTime = &metav1.Time{Time: now.Add(interval)}
+	}
+	ko.Status.Rollout = status
+	rm.setStatusDefaults(ko)
+	if weight < 100 {
+		return &resource{ko}, ackrequeue.NeededAfter(nil, interval)
+	}
+	return &resource{ko}, nil
+}
+
+// rollbackStateMachineAlias routes all of the alias traffic back to the
+// stable version of the rollout and returns a terminal error explaining why
+// the rollout was rolled back. A Warning Event is emitted with the same
+// message.
+func (rm *resourceManager) rollbackStateMachineAlias(
+	ctx context.Context,
+	desired *resource,
+	status *svcapitypes.RolloutStatus,
+	failed int64,
+	completed int64,
+) (*resource, error) {
+	stableWeight := int64(100)
+	stepResource := &resource{desired.ko.DeepCopy()}
+	stepResource.ko.Spec.RoutingConfiguration = []*svcapitypes.RoutingConfigurationListItem{{
+		StateMachineVersionARN: status.StableVersionARN,
+		Weight:                 &stableWeight,
+	}}
+	if _, err := rm.updateStateMachineAlias(ctx, stepResource); err != nil {
+		return nil, err
+	}
+
+	rolledBack := true
+	weight := int64(0)
+	status = status.DeepCopy()
+	status.NextTransitionTime = nil
+	status.RolledBack = &rolledBack
+	status.TargetWeight = &weight
+	ko := desired.ko.DeepCopy()
+	ko.Status.Rollout = status
+	rm.setStatusDefaults(ko)
+
+	msg := fmt.Sprintf(
+		"rolled back rollout to %s: %d of %d completed executions failed, above the %d%% threshold",
+		*status.TargetVersionARN, failed, completed,
+		*desired.ko.Spec.Rollout.Rollback.FailureThresholdPercentage,
+	)
+	ackrtlog.FromContext(ctx).Info(msg)
+	commonutil.RecordEvent(desired.ko, corev1.EventTypeWarning, "RolloutRolledBack", msg)
+	return &resource{ko}, ackerr.NewTerminalError(errors.New(msg))
+}
+
+// countRolloutExecutions returns the number of failed, timed out or aborted
+// executions and the number of completed executions of the rollout target
+// version that were started through the alias since the rollout started.
+func (rm *resourceManager) countRolloutExecutions(
+	ctx context.Context,
+	desired *resource,
+	status *svcapitypes.RolloutStatus,
+) (failed int64, completed int64, err error) {
+	aliasARN := string(*desired.ko.Status.ACKResourceMetadata.ARN)
+	input := &svcsdk.ListExecutionsInput{
+		StateMachineArn: &aliasARN,
+	}
+	for {
+		resp, err := rm.sdkapi.ListExecutions(ctx, input)
+		rm.metrics.RecordAPICall("READ_MANY", "ListExecutions", err)
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, execution := range resp.Executions {
+			// Executions are listed from the most recent one.
+			if execution.StartDate != nil && status.StartTime != nil && execution.StartDate.Before(status.StartTime.Time) {
+				return failed, completed, nil
+			}
+			if execution.StateMachineVersionArn == nil || *execution.StateMachineVersionArn != *status.TargetVersionARN {
+				continue
+			}
+			switch execution.Status {
+			case svcsdktypes.ExecutionStatusFailed,
+				svcsdktypes.ExecutionStatusTimedOut,
+				svcsdktypes.ExecutionStatusAborted:
+				failed++
+				completed++
+			case svcsdktypes.ExecutionStatusSucceeded:
+				completed++
+			}
+		}
+		if resp.NextToken == nil {
+			return failed, completed, nil
+		}
+		input.NextToken = resp.NextToken
+	}
+}
+
+// rollbackThresholdExceeded returns true if enough executions completed and
+// the percentage of failed executions is above the rollback threshold.
+func rollbackThresholdExceeded(
+	policy *svcapitypes.RolloutRollbackPolicy,
+	failed int64,
+	completed int64,
+) bool {
+	minimumExecutions := defaultRollbackMinimumExecutions
+	if policy.MinimumExecutions != nil {
+		minimumExecutions = *policy.MinimumExecutions
+	}
+	if completed == 0 || completed < minimumExecutions {
+		return false
+	}
+	return failed*100 > *policy.FailureThresholdPercentage*completed
+}
+
+// validateRolloutStrategy validates the supplied rollout strategy and returns
+// its interval.
+func validateRolloutStrategy(
+	strategy *svcapitypes.RolloutStrategy,
+) (time.Duration, error) {
+	if strategy.Type == nil || (*strategy.Type != rolloutTypeCanary && *strategy.Type != rolloutTypeLinear) {
+		return 0, fmt.Errorf("rollout.type must be one of %s, %s", rolloutTypeCanary, rolloutTypeLinear)
+	}
+	if strategy.StepPercentage == nil || *strategy.StepPercentage < 1 || *strategy.StepPercentage > 100 {
+		return 0, fmt.Errorf("rollout.stepPercentage must be between 1 and 100")
+	}
+	if strategy.Interval == nil {
+		return 0, fmt.Errorf("rollout.interval is required")
+	}
+	if strategy.Rollback != nil {
+		threshold := strategy.Rollback.FailureThresholdPercentage
+		if threshold == nil || *threshold < 0 || *threshold > 100 {
+			return 0, fmt.Errorf("rollout.rollback.failureThresholdPercentage must be between 0 and 100")
+		}
+	}
+	interval, err := time.ParseDuration(*strategy.Interval)
+	if err != nil {
+		return 0, fmt.Errorf("invalid rollout.interval %q: %v", *strategy.Interval, err)
+	}
+	if interval <= 0 {
+		return 0, fmt.Errorf("rollout.interval must be positive")
+	}
+	return interval, nil
+}
+
+// rolloutWeight returns the percentage of traffic routed to the new version
+// at the supplied rollout step.
+func rolloutWeight(
+	rolloutType string,
+	stepPercentage int64,
+	step int64,
+) int64 {
+	if rolloutType == rolloutTypeCanary {
+		if step <= 1 {
+			return stepPercentage
+		}
+		return 100
+	}
+	if weight := step * stepPercentage; weight < 100 {
+		return weight
+	}
+	return 100
+}
+
+// rolloutTargetVersionARN returns the version a rollout shifts traffic to,
+// which is the only version of the desired routing configuration. It returns
+// nil if the desired routing configuration already splits traffic between
+// two versions, in which case it is applied as is.
+func rolloutTargetVersionARN(desired *resource) *string {
+	routing := desired.ko.Spec.RoutingConfiguration
+	if len(routing) != 1 || routing[0].Weight == nil || *routing[0].Weight != 100 {
+		return nil
+	}
+	return routing[0].StateMachineVersionARN
+}
+
+// rolloutStableVersionARN returns the version a rollout to targetVersionARN
+// shifts traffic from. It is the stable version of the rollout in progress
+// if there is one, the version currently receiving most of the alias traffic
+// otherwise. It returns nil if the alias already routes all of its traffic
+// to targetVersionARN.
+func rolloutStableVersionARN(
+	desired *resource,
+	latest *resource,
+	targetVersionARN string,
+) *string {
+	status := desired.ko.Status.Rollout
+	if status != nil && status.TargetVersionARN != nil && *status.TargetVersionARN == targetVersionARN &&
+		status.StableVersionARN != nil && status.TargetWeight != nil && *status.TargetWeight < 100 {
+		return status.StableVersionARN
+	}
+	var stableVersionARN *string
+	var stableWeight int64 = -1
+	for _, item := range latest.ko.Spec.RoutingConfiguration {
+		if item.StateMachineVersionARN == nil || *item.StateMachineVersionARN == targetVersionARN {
+			continue
+		}
+		if item.Weight != nil && *item.Weight > stableWeight {
+			stableVersionARN = item.StateMachineVersionARN
+			stableWeight = *item.Weight
+		}
+	}
+	return stableVersionARN
+}
@@ -0,0 +1,327 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package state_machine_alias
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+)
+
+const (
+	testVersion1ARN = "arn:aws:states:us-west-2:111111111111:stateMachine:hello:1"
+	testVersion2ARN = "arn:aws:states:us-west-2:111111111111:stateMachine:hello:2"
+	testVersion3ARN = "arn:aws:states:us-west-2:111111111111:stateMachine:hello:3"
+)
+
+// routingTo returns a routing configuration entry routing weight percent of
+// the traffic to versionARN.
+func routingTo(versionARN string, weight int64) *svcapitypes.RoutingConfigurationListItem {
+	return &svcapitypes.RoutingConfigurationListItem{
+		StateMachineVersionARN: aws.String(versionARN),
+		Weight:                 aws.Int64(weight),
+	}
+}
+
+func TestRolloutWeight(t *testing.T) {
+	tests := []struct {
+		rolloutType    string
+		stepPercentage int64
+		step           int64
+		want           int64
+	}{
+		{rolloutTypeCanary, 10, 1, 10},
+		{rolloutTypeCanary, 10, 2, 100},
+		{rolloutTypeCanary, 10, 3, 100},
+		{rolloutTypeCanary, 100, 1, 100},
+		{rolloutTypeLinear, 25, 1, 25},
+		{rolloutTypeLinear, 25, 3, 75},
+		{rolloutTypeLinear, 25, 4, 100},
+		{rolloutTypeLinear, 30, 3, 90},
+		{rolloutTypeLinear, 30, 4, 100},
+		{rolloutTypeLinear, 1, 99, 99},
+		{rolloutTypeLinear, 1, 100, 100},
+	}
+	for _, tt := range tests {
+		if got := rolloutWeight(tt.rolloutType, tt.stepPercentage, tt.step); got != tt.want {
+			t.Errorf("rolloutWeight(%s, %d, %d) = %d, want %d",
+				tt.rolloutType, tt.stepPercentage, tt.step, got, tt.want)
+		}
+	}
+}
+
+func TestValidateRolloutStrategy(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy svcapitypes.RolloutStrategy
+		want     time.Duration
+		wantErr  bool
+	}{
+		{
+			name: "canary",
+			strategy: svcapitypes.RolloutStrategy{
+				Type: aws.String(rolloutTypeCanary), StepPercentage: aws.Int64(10), Interval: aws.String("10m"),
+			},
+			want: 10 * time.Minute,
+		},
+		{
+			name: "linear with rollback",
+			strategy: svcapitypes.RolloutStrategy{
+				Type: aws.String(rolloutTypeLinear), StepPercentage: aws.Int64(100), Interval: aws.String("90s"),
+				Rollback: &svcapitypes.RolloutRollbackPolicy{FailureThresholdPercentage: aws.Int64(5)},
+			},
+			want: 90 * time.Second,
+		},
+		{
+			name: "unknown type",
+			strategy: svcapitypes.RolloutStrategy{
+				Type: aws.String("BlueGreen"), StepPercentage: aws.Int64(10), Interval: aws.String("10m"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "zero step percentage",
+			strategy: svcapitypes.RolloutStrategy{
+				Type: aws.String(rolloutTypeLinear), StepPercentage: aws.Int64(0), Interval: aws.String("10m"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "step percentage above 100",
+			strategy: svcapitypes.RolloutStrategy{
+				Type: aws.String(rolloutTypeLinear), StepPercentage: aws.Int64(101), Interval: aws.String("10m"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing interval",
+			strategy: svcapitypes.RolloutStrategy{
+				Type: aws.String(rolloutTypeCanary), StepPercentage: aws.Int64(10),
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid interval",
+			strategy: svcapitypes.RolloutStrategy{
+				Type: aws.String(rolloutTypeCanary), StepPercentage: aws.Int64(10), Interval: aws.String("10 minutes"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative interval",
+			strategy: svcapitypes.RolloutStrategy{
+				Type: aws.String(rolloutTypeCanary), StepPercentage: aws.Int64(10), Interval: aws.String("-1m"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "rollback without threshold",
+			strategy: svcapitypes.RolloutStrategy{
+				Type: aws.String(rolloutTypeCanary), StepPercentage: aws.Int64(10), Interval: aws.String("10m"),
+				Rollback: &svcapitypes.RolloutRollbackPolicy{},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := validateRolloutStrategy(&tt.strategy)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateRolloutStrategy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("validateRolloutStrategy() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRolloutTargetVersionARN(t *testing.T) {
+	tests := []struct {
+		name    string
+		routing []*svcapitypes.RoutingConfigurationListItem
+		want    *string
+	}{
+		{
+			name:    "all traffic to one version",
+			routing: []*svcapitypes.RoutingConfigurationListItem{routingTo(testVersion2ARN, 100)},
+			want:    aws.String(testVersion2ARN),
+		},
+		{
+			name: "traffic split between two versions",
+			routing: []*svcapitypes.RoutingConfigurationListItem{
+				routingTo(testVersion2ARN, 90), routingTo(testVersion1ARN, 10),
+			},
+		},
+		{
+			name: "no routing",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desired := &resource{&svcapitypes.StateMachineAlias{}}
+			desired.ko.Spec.RoutingConfiguration = tt.routing
+			if got := rolloutTargetVersionARN(desired); aws.ToString(got) != aws.ToString(tt.want) {
+				t.Errorf("rolloutTargetVersionARN() = %v, want %v", aws.ToString(got), aws.ToString(tt.want))
+			}
+		})
+	}
+}
+
+func TestRolloutStableVersionARN(t *testing.T) {
+	tests := []struct {
+		name   string
+		status *svcapitypes.RolloutStatus
+		latest []*svcapitypes.RoutingConfigurationListItem
+		want   *string
+	}{
+		{
+			name:   "version routed to by the alias",
+			latest: []*svcapitypes.RoutingConfigurationListItem{routingTo(testVersion1ARN, 100)},
+			want:   aws.String(testVersion1ARN),
+		},
+		{
+			name: "version receiving most of the traffic",
+			latest: []*svcapitypes.RoutingConfigurationListItem{
+				routingTo(testVersion1ARN, 30), routingTo(testVersion3ARN, 70),
+			},
+			want: aws.String(testVersion3ARN),
+		},
+		{
+			name:   "already routed to the target",
+			latest: []*svcapitypes.RoutingConfigurationListItem{routingTo(testVersion2ARN, 100)},
+		},
+		{
+			name: "rollout in progress",
+			status: &svcapitypes.RolloutStatus{
+				StableVersionARN: aws.String(testVersion1ARN),
+				TargetVersionARN: aws.String(testVersion2ARN),
+				TargetWeight:     aws.Int64(60),
+			},
+			latest: []*svcapitypes.RoutingConfigurationListItem{
+				routingTo(testVersion2ARN, 60), routingTo(testVersion1ARN, 40),
+			},
+			want: aws.String(testVersion1ARN),
+		},
+		{
+			name: "rollout to another target",
+			status: &svcapitypes.RolloutStatus{
+				StableVersionARN: aws.String(testVersion1ARN),
+				TargetVersionARN: aws.String(testVersion3ARN),
+				TargetWeight:     aws.Int64(60),
+			},
+			latest: []*svcapitypes.RoutingConfigurationListItem{
+				routingTo(testVersion3ARN, 60), routingTo(testVersion1ARN, 40),
+			},
+			want: aws.String(testVersion3ARN),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desired := &resource{&svcapitypes.StateMachineAlias{}}
+			desired.ko.Spec.RoutingConfiguration = []*svcapitypes.RoutingConfigurationListItem{
+				routingTo(testVersion2ARN, 100),
+			}
+			desired.ko.Status.Rollout = tt.status
+			latest := &resource{&svcapitypes.StateMachineAlias{}}
+			latest.ko.Spec.RoutingConfiguration = tt.latest
+			got := rolloutStableVersionARN(desired, latest, testVersion2ARN)
+			if aws.ToString(got) != aws.ToString(tt.want) {
+				t.Errorf("rolloutStableVersionARN() = %v, want %v", aws.ToString(got), aws.ToString(tt.want))
+			}
+		})
+	}
+}
+
+func TestRollbackThresholdExceeded(t *testing.T) {
+	tests := []struct {
+		name              string
+		threshold         int64
+		minimumExecutions *int64
+		failed            int64
+		completed         int64
+		want              bool
+	}{
+		{
+			name:      "no executions",
+			threshold: 10,
+		},
+		{
+			name:      "below the default minimum executions",
+			threshold: 10,
+			failed:    9,
+			completed: 9,
+		},
+		{
+			name:      "above the threshold",
+			threshold: 10,
+			failed:    2,
+			completed: 10,
+			want:      true,
+		},
+		{
+			name:      "at the threshold",
+			threshold: 10,
+			failed:    1,
+			completed: 10,
+		},
+		{
+			name:      "below the threshold",
+			threshold: 25,
+			failed:    2,
+			completed: 10,
+		},
+		{
+			name:              "minimum executions reached",
+			threshold:         50,
+			minimumExecutions: aws.Int64(2),
+			failed:            2,
+			completed:         2,
+			want:              true,
+		},
+		{
+			name:              "minimum executions not reached",
+			threshold:         50,
+			minimumExecutions: aws.Int64(20),
+			failed:            19,
+			completed:         19,
+		},
+		{
+			name:      "zero threshold",
+			threshold: 0,
+			failed:    1,
+			completed: 100,
+			want:      true,
+		},
+		{
+			name:      "no failures with a zero threshold",
+			threshold: 0,
+			completed: 100,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &svcapitypes.RolloutRollbackPolicy{
+				FailureThresholdPercentage: aws.Int64(tt.threshold),
+				MinimumExecutions:          tt.minimumExecutions,
+			}
+			if got := rollbackThresholdExceeded(policy, tt.failed, tt.completed); got != tt.want {
+				t.Errorf("rollbackThresholdExceeded(%d, %d) = %v, want %v", tt.failed, tt.completed, got, tt.want)
+			}
+		})
+	}
+}
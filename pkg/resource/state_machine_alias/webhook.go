@@ -0,0 +1,260 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package state_machine_alias
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackrtwebhook "github.com/aws-controllers-k8s/runtime/pkg/webhook"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlrt "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+)
+
+// +kubebuilder:webhook:path=/validate-sfn-services-k8s-aws-v1alpha1-statemachinealias,mutating=false,failurePolicy=fail,sideEffects=None,groups=sfn.services.k8s.aws,resources=statemachinealiases,verbs=create;update,versions=v1alpha1,name=vstatemachinealias.sfn.services.k8s.aws,admissionReviewVersions=v1
+
+func init() {
+	if err := ackrtwebhook.RegisterWebhook(ackrtwebhook.New(
+		svcapitypes.GroupVersion.Version,
+		"StateMachineAlias",
+		"validating",
+		setupValidatingWebhook,
+	)); err != nil {
+		panic(err)
+	}
+}
+
+// setupValidatingWebhook registers the StateMachineAlias validating webhook
+// with the webhook server of the supplied manager. It is only called when the
+// webhook server is enabled.
+func setupValidatingWebhook(mgr ctrlrt.Manager) error {
+	return ctrlrt.NewWebhookManagedBy(
+		mgr, &svcapitypes.StateMachineAlias{},
+	).WithValidator(
+		&validator{apiReader: mgr.GetAPIReader()},
+	).Complete()
+}
+
+// validator rejects StateMachineAliases whose routing configuration entries
+// route to versions of different state machines, or twice to the same
+// version, once the StateMachineVersionRef and StateMachineRef fields are
+// resolved. The CRD validation rules only compare entries that use the same
+// field.
+type validator struct {
+	apiReader client.Reader
+}
+
+var _ admission.Validator[*svcapitypes.StateMachineAlias] = &validator{}
+
+// routeTarget is the state machine and version a routing configuration entry
+// routes to, as far as they are known at admission. Referenced resources that
+// do not exist yet, or were not created in Step Functions yet, only have a
+// key identifying the resource.
+type routeTarget struct {
+	stateMachineARN string
+	stateMachineKey string
+	versionARN      string
+}
+
+// ValidateCreate validates the routing configuration of the supplied
+// StateMachineAlias.
+func (v *validator) ValidateCreate(
+	ctx context.Context,
+	ko *svcapitypes.StateMachineAlias,
+) (admission.Warnings, error) {
+	return nil, v.validateRoutingConfiguration(ctx, ko)
+}
+
+// ValidateUpdate validates the routing configuration of the updated
+// StateMachineAlias.
+func (v *validator) ValidateUpdate(
+	ctx context.Context,
+	oldKo *svcapitypes.StateMachineAlias,
+	newKo *svcapitypes.StateMachineAlias,
+) (admission.Warnings, error) {
+	return nil, v.validateRoutingConfiguration(ctx, newKo)
+}
+
+// ValidateDelete admits all deletions.
+func (v *validator) ValidateDelete(
+	ctx context.Context,
+	ko *svcapitypes.StateMachineAlias,
+) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateRoutingConfiguration returns an error if the routing configuration
+// entries are known to route to versions of different state machines, or to
+// the same version. Entries whose targets cannot be determined yet are
+// validated once the alias is reconciled.
+func (v *validator) validateRoutingConfiguration(
+	ctx context.Context,
+	ko *svcapitypes.StateMachineAlias,
+) error {
+	targets := []routeTarget{}
+	for _, item := range ko.Spec.RoutingConfiguration {
+		if item == nil {
+			continue
+		}
+		target, err := v.getRouteTarget(ctx, ko.Namespace, item)
+		if err != nil {
+			return err
+		}
+		targets = append(targets, target)
+	}
+	for i := range targets {
+		for j := i + 1; j < len(targets); j++ {
+			a, b := targets[i], targets[j]
+			if a.versionARN != "" && a.versionARN == b.versionARN {
+				return fmt.Errorf("routing configuration entries must route to different versions, %s is used twice", a.versionARN)
+			}
+			if differentStateMachines(a, b) {
+				return fmt.Errorf("routing configuration entries must route to versions of the same state machine")
+			}
+		}
+	}
+	return nil
+}
+
+// differentStateMachines returns true if the supplied targets are known to
+// route to versions of different state machines.
+func differentStateMachines(a, b routeTarget) bool {
+	if a.stateMachineARN != "" && b.stateMachineARN != "" {
+		return a.stateMachineARN != b.stateMachineARN
+	}
+	return a.stateMachineKey != "" && b.stateMachineKey != "" && a.stateMachineKey != b.stateMachineKey
+}
+
+// getRouteTarget returns the state machine and version the supplied routing
+// configuration entry routes to.
+func (v *validator) getRouteTarget(
+	ctx context.Context,
+	namespace string,
+	item *svcapitypes.RoutingConfigurationListItem,
+) (routeTarget, error) {
+	target := routeTarget{}
+	switch {
+	case item.StateMachineVersionRef != nil && item.StateMachineVersionRef.From != nil:
+		version := &svcapitypes.StateMachineVersion{}
+		found, err := v.get(ctx, namespace, item.StateMachineVersionRef.From, version)
+		if !found || err != nil {
+			return target, err
+		}
+		if metadata := version.Status.ACKResourceMetadata; metadata != nil && metadata.ARN != nil {
+			target.versionARN = string(*metadata.ARN)
+			target.stateMachineARN, _ = stateMachineARNFromVersionARN(target.versionARN)
+		}
+		if ref := version.Spec.StateMachineRef; ref != nil && ref.From != nil {
+			return v.setStateMachineTarget(ctx, version.Namespace, ref.From, target)
+		}
+		if version.Spec.StateMachineARN != nil {
+			target.stateMachineARN = *version.Spec.StateMachineARN
+		}
+	case item.StateMachineRef != nil && item.StateMachineRef.From != nil:
+		var err error
+		target, err = v.setStateMachineTarget(ctx, namespace, item.StateMachineRef.From, target)
+		if err != nil {
+			return target, err
+		}
+		if target.stateMachineARN != "" && item.VersionNumber != nil {
+			target.versionARN = fmt.Sprintf("%s:%d", target.stateMachineARN, *item.VersionNumber)
+		}
+	case item.StateMachineVersionARN != nil:
+		stateMachineARN, err := stateMachineARNFromVersionARN(*item.StateMachineVersionARN)
+		if err != nil {
+			return target, err
+		}
+		target.versionARN = *item.StateMachineVersionARN
+		target.stateMachineARN = stateMachineARN
+	}
+	return target, nil
+}
+
+// setStateMachineTarget sets the state machine of the supplied target to the
+// StateMachine referenced from ref.
+func (v *validator) setStateMachineTarget(
+	ctx context.Context,
+	namespace string,
+	ref *ackv1alpha1.AWSResourceReference,
+	target routeTarget,
+) (routeTarget, error) {
+	if ref.Name == nil || *ref.Name == "" {
+		return target, nil
+	}
+	if ref.Namespace != nil && *ref.Namespace != "" {
+		namespace = *ref.Namespace
+	}
+	target.stateMachineKey = namespace + "/" + *ref.Name
+	sm := &svcapitypes.StateMachine{}
+	found, err := v.get(ctx, namespace, ref, sm)
+	if !found || err != nil {
+		return target, err
+	}
+	if metadata := sm.Status.ACKResourceMetadata; metadata != nil && metadata.ARN != nil {
+		target.stateMachineARN = string(*metadata.ARN)
+	}
+	return target, nil
+}
+
+// get reads the object referenced from the supplied reference into obj, and
+// returns whether it was found.
+func (v *validator) get(
+	ctx context.Context,
+	namespace string,
+	ref *ackv1alpha1.AWSResourceReference,
+	obj client.Object,
+) (bool, error) {
+	if ref.Name == nil || *ref.Name == "" {
+		return false, nil
+	}
+	if ref.Namespace != nil && *ref.Namespace != "" {
+		namespace = *ref.Namespace
+	}
+	err := v.apiReader.Get(ctx, types.NamespacedName{
+		Namespace: namespace,
+		Name:      *ref.Name,
+	}, obj)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// stateMachineARNFromVersionARN strips the version number from a state
+// machine version ARN, and returns an error if it is not one: the ARN of a
+// state machine or of an alias must not be mistaken for the ARN of a version.
+func stateMachineARNFromVersionARN(arn string) (string, error) {
+	// arn:partition:states:region:account:stateMachine:name:version
+	parts := strings.Split(arn, ":")
+	if len(parts) != 8 || parts[0] != "arn" || parts[2] != "states" || parts[5] != "stateMachine" ||
+		parts[6] == "" || !isVersionNumber(parts[7]) {
+		return "", fmt.Errorf("%q is not a state machine version ARN", arn)
+	}
+	return strings.Join(parts[:7], ":"), nil
+}
+
+// isVersionNumber returns true if the supplied ARN qualifier is a version
+// number rather than an alias name.
+func isVersionNumber(qualifier string) bool {
+	version, err := strconv.ParseUint(qualifier, 10, 64)
+	return err == nil && version > 0
+}
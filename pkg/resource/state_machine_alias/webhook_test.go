@@ -0,0 +1,185 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package state_machine_alias
+
+import (
+	"context"
+	"testing"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+)
+
+func TestStateMachineARNFromVersionARN(t *testing.T) {
+	tests := []struct {
+		name    string
+		arn     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "version",
+			arn:  "arn:aws:states:us-west-2:111111111111:stateMachine:hello:3",
+			want: "arn:aws:states:us-west-2:111111111111:stateMachine:hello",
+		},
+		{
+			name: "other partition",
+			arn:  "arn:aws-us-gov:states:us-gov-west-1:111111111111:stateMachine:hello:12",
+			want: "arn:aws-us-gov:states:us-gov-west-1:111111111111:stateMachine:hello",
+		},
+		{
+			name:    "state machine",
+			arn:     "arn:aws:states:us-west-2:111111111111:stateMachine:hello",
+			wantErr: true,
+		},
+		{
+			name:    "alias",
+			arn:     "arn:aws:states:us-west-2:111111111111:stateMachine:hello:live",
+			wantErr: true,
+		},
+		{
+			name:    "version zero",
+			arn:     "arn:aws:states:us-west-2:111111111111:stateMachine:hello:0",
+			wantErr: true,
+		},
+		{
+			name:    "execution",
+			arn:     "arn:aws:states:us-west-2:111111111111:execution:hello:run",
+			wantErr: true,
+		},
+		{
+			name:    "other service",
+			arn:     "arn:aws:lambda:us-west-2:111111111111:stateMachine:hello:3",
+			wantErr: true,
+		},
+		{
+			name:    "too many parts",
+			arn:     "arn:aws:states:us-west-2:111111111111:stateMachine:hello:3:4",
+			wantErr: true,
+		},
+		{
+			name:    "not an ARN",
+			arn:     "hello:3",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := stateMachineARNFromVersionARN(tt.arn)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("stateMachineARNFromVersionARN() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("stateMachineARNFromVersionARN() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// reference returns a reference to the resource of the supplied name.
+func reference(name string) *ackv1alpha1.AWSResourceReferenceWrapper {
+	return &ackv1alpha1.AWSResourceReferenceWrapper{
+		From: &ackv1alpha1.AWSResourceReference{Name: aws.String(name)},
+	}
+}
+
+func TestValidateCreateRoutingReferences(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := svcapitypes.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	helloARN := ackv1alpha1.AWSResourceName("arn:aws:states:us-west-2:111111111111:stateMachine:hello")
+	hello := &svcapitypes.StateMachine{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "hello"},
+		Status: svcapitypes.StateMachineStatus{
+			ACKResourceMetadata: &ackv1alpha1.ResourceMetadata{ARN: &helloARN},
+		},
+	}
+	// A StateMachine that was not created in Step Functions yet.
+	world := &svcapitypes.StateMachine{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "world"},
+	}
+	helloV2 := &svcapitypes.StateMachineVersion{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "hello-v2"},
+		Spec:       svcapitypes.StateMachineVersionSpec{StateMachineRef: reference("hello")},
+	}
+	worldV1 := &svcapitypes.StateMachineVersion{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "world-v1"},
+		Spec:       svcapitypes.StateMachineVersionSpec{StateMachineRef: reference("world")},
+	}
+	v := &validator{apiReader: fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(hello, world, helloV2, worldV1).
+		Build()}
+
+	tests := []struct {
+		name    string
+		routing []*svcapitypes.RoutingConfigurationListItem
+		wantErr bool
+	}{
+		{
+			name: "state machine and version of the same state machine",
+			routing: []*svcapitypes.RoutingConfigurationListItem{
+				{StateMachineRef: reference("hello"), VersionNumber: aws.Int64(1), Weight: aws.Int64(90)},
+				{StateMachineVersionRef: reference("hello-v2"), Weight: aws.Int64(10)},
+			},
+		},
+		{
+			name: "version reference and ARN of the same state machine",
+			routing: []*svcapitypes.RoutingConfigurationListItem{
+				{StateMachineVersionRef: reference("hello-v2"), Weight: aws.Int64(90)},
+				routingTo(testVersion1ARN, 10),
+			},
+		},
+		{
+			name: "the same version twice",
+			routing: []*svcapitypes.RoutingConfigurationListItem{
+				{StateMachineRef: reference("hello"), VersionNumber: aws.Int64(1), Weight: aws.Int64(50)},
+				routingTo(testVersion1ARN, 50),
+			},
+			wantErr: true,
+		},
+		{
+			name: "versions of state machines not created yet",
+			routing: []*svcapitypes.RoutingConfigurationListItem{
+				{StateMachineVersionRef: reference("hello-v2"), Weight: aws.Int64(50)},
+				{StateMachineVersionRef: reference("world-v1"), Weight: aws.Int64(50)},
+			},
+			wantErr: true,
+		},
+		{
+			name: "references not created yet",
+			routing: []*svcapitypes.RoutingConfigurationListItem{
+				{StateMachineVersionRef: reference("hello-v3"), Weight: aws.Int64(50)},
+				routingTo(testVersion1ARN, 50),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ko := &svcapitypes.StateMachineAlias{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "live"},
+			}
+			ko.Spec.RoutingConfiguration = tt.routing
+			if _, err := v.ValidateCreate(context.TODO(), ko); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package state_machine_version
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/sfn"
+	smithy "github.com/aws/smithy-go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	errImmutableStateMachineVersion = errors.New(
+		"state machine versions are immutable, create a new StateMachineVersion instead",
+	)
+)
+
+// customFindStateMachineVersion describes the state machine version whose
+// ARN is stored in the resource status. DescribeStateMachine is shared with
+// the StateMachine resource, so the version specific fields are set here.
+func (rm *resourceManager) customFindStateMachineVersion(
+	ctx context.Context,
+	r *resource,
+) (latest *resource, err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.customFindStateMachineVersion")
+	defer func() {
+		exit(err)
+	}()
+	// The version ARN is only known once PublishStateMachineVersion
+	// succeeded, the version does not exist before that.
+	if r.ko.Status.ACKResourceMetadata == nil || r.ko.Status.ACKResourceMetadata.ARN == nil {
+		return nil, ackerr.NotFound
+	}
+	versionARN := string(*r.ko.Status.ACKResourceMetadata.ARN)
+
+	var resp *svcsdk.DescribeStateMachineOutput
+	resp, err = rm.sdkapi.DescribeStateMachine(
+		ctx,
+		&svcsdk.DescribeStateMachineInput{
+			StateMachineArn: &versionARN,
+		},
+	)
+	rm.metrics.RecordAPICall("READ_ONE", "DescribeStateMachine", err)
+	if err != nil {
+		var awsErr smithy.APIError
+		if errors.As(err, &awsErr) && awsErr.ErrorCode() == "StateMachineDoesNotExist" {
+			return nil, ackerr.NotFound
+		}
+		return nil, err
+	}
+
+	ko := r.ko.DeepCopy()
+
+	if resp.CreationDate != nil {
+		ko.Status.CreationDate = &metav1.Time{Time: *resp.CreationDate}
+	} else {
+		ko.Status.CreationDate = nil
+	}
+	if resp.Description != nil {
+		ko.Spec.Description = resp.Description
+	} else {
+		ko.Spec.Description = nil
+	}
+	ko.Status.PublishedRevisionID = resp.RevisionId
+	// Spec.RevisionID is only late initialized: the revision ID the version
+	// was published with, e.g. INITIAL, is kept when it is set.
+	if ko.Spec.RevisionID == nil {
+		ko.Spec.RevisionID = resp.RevisionId
+	}
+	// Only fill in the state machine ARN when it is neither set nor resolved
+	// from StateMachineRef, e.g. when adopting an existing version.
+	if ko.Spec.StateMachineARN == nil && ko.Spec.StateMachineRef == nil {
+		ko.Spec.StateMachineARN = stateMachineARNFromVersionARN(versionARN)
+	}
+	ko.Status.VersionNumber = versionNumberFromARN(&versionARN)
+
+	rm.setStatusDefaults(ko)
+	return &resource{ko}, nil
+}
+
+// customUpdateStateMachineVersion returns a terminal error: Step Functions
+// does not support modifying a published version.
+func (rm *resourceManager) customUpdateStateMachineVersion(
+	ctx context.Context,
+	desired *resource,
+	latest *resource,
+	delta *ackcompare.Delta,
+) (*resource, error) {
+	return nil, ackerr.NewTerminalError(errImmutableStateMachineVersion)
+}
+
+// versionNumberFromARN returns the version number found at the end of a
+// state machine version ARN, or nil if the ARN is not a version ARN.
+func versionNumberFromARN(arn *string) *int64 {
+	if arn == nil {
+		return nil
+	}
+	idx := strings.LastIndex(*arn, ":")
+	if idx < 0 {
+		return nil
+	}
+	version, err := strconv.ParseInt((*arn)[idx+1:], 10, 64)
+	if err != nil {
+		return nil
+	}
+	return &version
+}
+
+// stateMachineARNFromVersionARN strips the version number from a state
+// machine version ARN.
+func stateMachineARNFromVersionARN(arn string) *string {
+	if versionNumberFromARN(&arn) == nil {
+		return nil
+	}
+	stateMachineARN := arn[:strings.LastIndex(arn, ":")]
+	return &stateMachineARN
+}
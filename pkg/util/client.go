@@ -0,0 +1,49 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package util
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// kubeClient is used to write the Kubernetes objects the controller manages
+// on behalf of its resources. Resource managers only get an API reader from
+// the ACK runtime, so it is set from main once the controller manager is
+// created.
+var kubeClient client.Client
+
+// SetKubeClient sets the client returned by KubeClient.
+func SetKubeClient(c client.Client) {
+	kubeClient = c
+}
+
+// KubeClient returns the client set by SetKubeClient, or nil.
+func KubeClient() client.Client {
+	return kubeClient
+}
+
+// apiReader reads the Kubernetes objects the controller manages on behalf of
+// its resources without going through the cache of kubeClient, which would
+// start informers for kinds the controller is not allowed to watch.
+var apiReader client.Reader
+
+// SetAPIReader sets the reader returned by APIReader.
+func SetAPIReader(r client.Reader) {
+	apiReader = r
+}
+
+// APIReader returns the reader set by SetAPIReader, or nil.
+func APIReader() client.Reader {
+	return apiReader
+}
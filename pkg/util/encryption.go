@@ -0,0 +1,69 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package util
+
+import (
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+)
+
+const (
+	// defaultKMSDataKeyReusePeriodSeconds is the data key reuse period used by
+	// Step Functions when a customer managed key is configured without an
+	// explicit KmsDataKeyReusePeriodSeconds.
+	defaultKMSDataKeyReusePeriodSeconds = int64(300)
+)
+
+// EqualEncryptionConfiguration returns true if the supplied encryption
+// configurations are equivalent once the Step Functions defaults are applied.
+//
+// A nil configuration is equivalent to an AWS owned key, which is what
+// Step Functions reports for resources created without one. The KMSKeyRef
+// field is not compared: only its resolved KMSKeyID is.
+func EqualEncryptionConfiguration(
+	a *svcapitypes.EncryptionConfiguration,
+	b *svcapitypes.EncryptionConfiguration,
+) bool {
+	aType, aKeyID, aReusePeriod := encryptionConfigurationValues(a)
+	bType, bKeyID, bReusePeriod := encryptionConfigurationValues(b)
+	if aType != bType {
+		return false
+	}
+	if aType == string(svcapitypes.EncryptionType_AWS_OWNED_KEY) {
+		return true
+	}
+	return aKeyID == bKeyID && aReusePeriod == bReusePeriod
+}
+
+// encryptionConfigurationValues returns the encryption type, KMS key ID and
+// data key reuse period of the supplied configuration with Step Functions
+// defaults applied.
+func encryptionConfigurationValues(
+	c *svcapitypes.EncryptionConfiguration,
+) (encryptionType string, keyID string, reusePeriod int64) {
+	encryptionType = string(svcapitypes.EncryptionType_AWS_OWNED_KEY)
+	if c == nil {
+		return encryptionType, "", 0
+	}
+	if c.Type != nil && *c.Type != "" {
+		encryptionType = *c.Type
+	}
+	if c.KMSKeyID != nil {
+		keyID = *c.KMSKeyID
+	}
+	reusePeriod = defaultKMSDataKeyReusePeriodSeconds
+	if c.KMSDataKeyReusePeriodSeconds != nil {
+		reusePeriod = *c.KMSDataKeyReusePeriodSeconds
+	}
+	return encryptionType, keyID, reusePeriod
+}
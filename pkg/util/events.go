@@ -0,0 +1,47 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package util
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// eventRecorder is used by RecordEvent. The ACK runtime doesn't expose an
+// event recorder to resource managers, so it is set from main once the
+// controller manager is created.
+var eventRecorder record.EventRecorder
+
+// SetEventRecorder sets the recorder used to emit Kubernetes Events for the
+// resources managed by this controller.
+func SetEventRecorder(recorder record.EventRecorder) {
+	eventRecorder = recorder
+}
+
+// RecordEvent emits a Kubernetes Event for the supplied object. It is a no-op
+// until SetEventRecorder is called.
+func RecordEvent(
+	obj runtime.Object,
+	eventType string,
+	reason string,
+	messageFmt string,
+	args ...interface{},
+) {
+	if eventRecorder == nil {
+		return
+	}
+	eventRecorder.Eventf(obj, eventType, reason, messageFmt, args...)
+}
@@ -0,0 +1,87 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package util
+
+import (
+	"context"
+	"fmt"
+
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	svcapitypes "github.com/aws-controllers-k8s/sfn-controller/apis/v1alpha1"
+)
+
+// GetConfigMapKey returns the value of the ConfigMap key selected by the
+// supplied selector, looking in both the data and binaryData of the
+// ConfigMap.
+func GetConfigMapKey(
+	ctx context.Context,
+	apiReader client.Reader,
+	namespace string,
+	selector *svcapitypes.DefinitionKeySelector,
+) (string, error) {
+	if selector == nil || selector.Name == nil || selector.Key == nil {
+		return "", fmt.Errorf("provided ConfigMap key selector is nil or empty")
+	}
+	cm := &corev1.ConfigMap{}
+	namespacedName := types.NamespacedName{
+		Namespace: namespace,
+		Name:      *selector.Name,
+	}
+	if err := apiReader.Get(ctx, namespacedName, cm); err != nil {
+		return "", err
+	}
+	if value, ok := cm.Data[*selector.Key]; ok {
+		return value, nil
+	}
+	if value, ok := cm.BinaryData[*selector.Key]; ok {
+		return string(value), nil
+	}
+	return "", ackerr.ResourceReferenceMissingTargetFieldFor(
+		"ConfigMap",
+		namespace, *selector.Name,
+		"data."+*selector.Key)
+}
+
+// GetSecretKey returns the value of the Secret key selected by the supplied
+// selector.
+func GetSecretKey(
+	ctx context.Context,
+	apiReader client.Reader,
+	namespace string,
+	selector *svcapitypes.DefinitionKeySelector,
+) (string, error) {
+	if selector == nil || selector.Name == nil || selector.Key == nil {
+		return "", fmt.Errorf("provided Secret key selector is nil or empty")
+	}
+	secret := &corev1.Secret{}
+	namespacedName := types.NamespacedName{
+		Namespace: namespace,
+		Name:      *selector.Name,
+	}
+	if err := apiReader.Get(ctx, namespacedName, secret); err != nil {
+		return "", err
+	}
+	value, ok := secret.Data[*selector.Key]
+	if !ok {
+		return "", ackerr.ResourceReferenceMissingTargetFieldFor(
+			"Secret",
+			namespace, *selector.Name,
+			"data."+*selector.Key)
+	}
+	return string(value), nil
+}
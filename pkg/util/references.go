@@ -0,0 +1,145 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package util
+
+import (
+	"context"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	// CloudWatchLogsLogGroupGVK is the GroupVersionKind of the
+	// cloudwatchlogs-controller LogGroup resource.
+	CloudWatchLogsLogGroupGVK = schema.GroupVersionKind{
+		Group:   "cloudwatchlogs.services.k8s.aws",
+		Version: "v1alpha1",
+		Kind:    "LogGroup",
+	}
+	// DynamoDBTableGVK is the GroupVersionKind of the dynamodb-controller
+	// Table resource.
+	DynamoDBTableGVK = schema.GroupVersionKind{
+		Group:   "dynamodb.services.k8s.aws",
+		Version: "v1alpha1",
+		Kind:    "Table",
+	}
+	// KMSKeyGVK is the GroupVersionKind of the kms-controller Key resource.
+	KMSKeyGVK = schema.GroupVersionKind{
+		Group:   "kms.services.k8s.aws",
+		Version: "v1alpha1",
+		Kind:    "Key",
+	}
+	// LambdaFunctionGVK is the GroupVersionKind of the lambda-controller
+	// Function resource.
+	LambdaFunctionGVK = schema.GroupVersionKind{
+		Group:   "lambda.services.k8s.aws",
+		Version: "v1alpha1",
+		Kind:    "Function",
+	}
+	// SNSTopicGVK is the GroupVersionKind of the sns-controller Topic resource.
+	SNSTopicGVK = schema.GroupVersionKind{
+		Group:   "sns.services.k8s.aws",
+		Version: "v1alpha1",
+		Kind:    "Topic",
+	}
+	// SQSQueueGVK is the GroupVersionKind of the sqs-controller Queue resource.
+	SQSQueueGVK = schema.GroupVersionKind{
+		Group:   "sqs.services.k8s.aws",
+		Version: "v1alpha1",
+		Kind:    "Queue",
+	}
+)
+
+// GetReferencedResourceARN looks up a resource managed by another ACK
+// service controller and returns its `Status.ACKResourceMetadata.ARN`.
+//
+// The resource is read as an unstructured object so that this controller
+// does not need to depend on the API packages of every controller it can
+// reference. Like the generated getReferencedResourceState_* functions, it
+// returns `ackerr.ResourceReferenceTerminalFor` if the referenced resource
+// is in a Terminal state, `ackerr.ResourceReferenceNotSyncedFor` if it is
+// not yet synced and `ackerr.ResourceReferenceMissingTargetFieldFor` if it
+// does not have an ARN yet.
+func GetReferencedResourceARN(
+	ctx context.Context,
+	apiReader client.Reader,
+	gvk schema.GroupVersionKind,
+	name string, // the Kubernetes name of the referenced resource
+	namespace string, // the Kubernetes namespace of the referenced resource
+) (string, error) {
+	obj, err := GetReferencedResource(ctx, apiReader, gvk, name, namespace)
+	if err != nil {
+		return "", err
+	}
+	arn, found, err := unstructured.NestedString(obj.Object, "status", "ackResourceMetadata", "arn")
+	if err != nil || !found || arn == "" {
+		return "", ackerr.ResourceReferenceMissingTargetFieldFor(
+			gvk.Kind,
+			namespace, name,
+			"Status.ACKResourceMetadata.ARN")
+	}
+	return arn, nil
+}
+
+// GetReferencedResource looks up a resource managed by another ACK service
+// controller and makes sure it exists and is in a ACK.ResourceSynced=True
+// state.
+func GetReferencedResource(
+	ctx context.Context,
+	apiReader client.Reader,
+	gvk schema.GroupVersionKind,
+	name string, // the Kubernetes name of the referenced resource
+	namespace string, // the Kubernetes namespace of the referenced resource
+) (*unstructured.Unstructured, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	namespacedName := types.NamespacedName{
+		Namespace: namespace,
+		Name:      name,
+	}
+	if err := apiReader.Get(ctx, namespacedName, obj); err != nil {
+		return nil, err
+	}
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	var refResourceSynced bool
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if cond["status"] != string(corev1.ConditionTrue) {
+			continue
+		}
+		switch cond["type"] {
+		case string(ackv1alpha1.ConditionTypeTerminal):
+			return nil, ackerr.ResourceReferenceTerminalFor(
+				gvk.Kind,
+				namespace, name)
+		case string(ackv1alpha1.ConditionTypeResourceSynced):
+			refResourceSynced = true
+		}
+	}
+	if !refResourceSynced {
+		return nil, ackerr.ResourceReferenceNotSyncedFor(
+			gvk.Kind,
+			namespace, name)
+	}
+	return obj, nil
+}
@@ -0,0 +1 @@
+	ko.Status.ObservedRedriveGeneration = ko.Spec.RedriveGeneration
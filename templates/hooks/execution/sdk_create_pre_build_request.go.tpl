@@ -0,0 +1,6 @@
+	if desired.ko.Spec.Name == nil {
+		desired.ko.Spec.Name = defaultExecutionName(desired.ko)
+	}
+	if executionSyncMode(desired.ko) {
+		return rm.startSyncExecution(ctx, desired)
+	}
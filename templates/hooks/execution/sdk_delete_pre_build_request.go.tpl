@@ -0,0 +1,3 @@
+	if !executionRunning(r.ko) {
+		return nil, nil
+	}
@@ -0,0 +1,5 @@
+	input.Definition, err = resolveDefinition(desired.ko)
+	if err != nil {
+		return nil, err
+	}
+	input.RoleArn = executionRoleARN(desired.ko)
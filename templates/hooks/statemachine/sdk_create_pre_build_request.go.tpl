@@ -0,0 +1,6 @@
+	if err := validateLoggingConfiguration(desired.ko); err != nil {
+		return desired, err
+	}
+	if err := rm.validateDefinition(ctx, desired); err != nil {
+		return desired, err
+	}
@@ -1,3 +1,16 @@
 	if err := rm.setResourceAdditionalFields(ctx, ko); err != nil {
 		return nil, err
-	}
\ No newline at end of file
+	}
+	setLatestDefinition(r.ko, ko)
+	setLatestLogGroupRefs(r.ko, ko)
+	setLatestRoleARN(r.ko, ko)
+	// Versions expire without the state machine changing, the retention
+	// policy is applied whenever the state machine is read.
+	if !r.IsBeingDeleted() {
+		if err := rm.pruneStateMachineVersions(ctx, &resource{ko}); err != nil {
+			return nil, err
+		}
+	}
+	// The condition is only reported, a blocking diagnostic is returned
+	// by the update that validates the definition.
+	_ = setDefinitionValidCondition(ko)
@@ -0,0 +1,10 @@
+	// The objects referenced by a StateMachine that is being deleted may
+	// already be gone, they are only needed to create or update it.
+	if !r.IsBeingDeleted() {
+		if err := rm.resolveCustomReferences(ctx, r.ko); err != nil {
+			return r, err
+		}
+		if err := rm.writeGeneratedObjects(ctx, r.ko); err != nil {
+			return r, err
+		}
+	}
@@ -0,0 +1,3 @@
+	if err := validateRoutingConfiguration(desired.ko); err != nil {
+		return nil, ackerr.NewTerminalError(err)
+	}
@@ -65,6 +65,17 @@ class SFNHelper:
             logging.debug(e)
             return None
 
+    def list_state_machine_versions(self, state_machine_arn: str) -> list:
+        try:
+            paginator = self.sfn_client.get_paginator("list_state_machine_versions")
+            versions = []
+            for page in paginator.paginate(stateMachineArn=state_machine_arn):
+                versions.extend(page["stateMachineVersions"])
+            return versions
+        except Exception as e:
+            logging.debug(e)
+            return []
+
     def delete_state_machine_version(self, version_arn: str):
         try:
             self.sfn_client.delete_state_machine_version(
@@ -83,6 +94,26 @@ class SFNHelper:
             logging.debug(e)
             return None
 
+    def start_execution(self, state_machine_arn: str) -> dict:
+        try:
+            resp = self.sfn_client.start_execution(
+                stateMachineArn=state_machine_arn,
+            )
+            return resp
+        except Exception as e:
+            logging.debug(e)
+            return None
+
+    def describe_execution(self, execution_arn: str) -> dict:
+        try:
+            resp = self.sfn_client.describe_execution(
+                executionArn=execution_arn,
+            )
+            return resp
+        except Exception as e:
+            logging.debug(e)
+            return None
+
     def state_machine_alias_exists(self, alias_arn: str) -> bool:
         return self.describe_state_machine_alias(alias_arn) is not None
 
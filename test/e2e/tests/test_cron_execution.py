@@ -0,0 +1,144 @@
+# Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License"). You may
+# not use this file except in compliance with the License. A copy of the
+# License is located at
+#
+# 	 http://aws.amazon.com/apache2.0/
+#
+# or in the "license" file accompanying this file. This file is distributed
+# on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+# express or implied. See the License for the specific language governing
+# permissions and limitations under the License.
+
+"""Integration tests for the SFN CronExecution API.
+"""
+
+import pytest
+import time
+import logging
+
+from acktest.resources import random_suffix_name
+from acktest.k8s import resource as k8s
+from kubernetes import client as k8s_client
+from e2e import service_marker, CRD_GROUP, CRD_VERSION, load_sfn_resource
+from e2e.replacement_values import REPLACEMENT_VALUES
+from e2e.tests.test_execution import create_state_machine, delete_resource
+
+CRON_EXECUTION_RESOURCE_PLURAL = "cronexecutions"
+EXECUTION_RESOURCE_PLURAL = "executions"
+
+# The CronExecution runs every minute.
+SCHEDULE_WAIT_SECONDS = 75
+
+
+@pytest.fixture
+def pass_state_machine():
+    sm_name, sm_ref = create_state_machine("state_machine")
+    yield sm_name
+    delete_resource(sm_ref)
+
+
+@pytest.fixture
+def wait_state_machine():
+    sm_name, sm_ref = create_state_machine("state_machine_wait")
+    yield sm_name
+    delete_resource(sm_ref)
+
+
+def create_cron_execution(state_machine_name: str, concurrency_policy: str):
+    cron_execution_name = random_suffix_name("sfn-cron-execution", 32)
+
+    replacements = REPLACEMENT_VALUES.copy()
+    replacements["CRON_EXECUTION_NAME"] = cron_execution_name
+    replacements["STATE_MACHINE_NAME"] = state_machine_name
+    replacements["CONCURRENCY_POLICY"] = concurrency_policy
+
+    cron_execution_data = load_sfn_resource(
+        "cron_execution",
+        additional_replacements=replacements,
+    )
+    logging.debug(cron_execution_data)
+
+    cron_execution_ref = k8s.CustomResourceReference(
+        CRD_GROUP, CRD_VERSION, CRON_EXECUTION_RESOURCE_PLURAL,
+        cron_execution_name, namespace="default",
+    )
+    k8s.create_custom_resource(cron_execution_ref, cron_execution_data)
+    assert k8s.wait_on_condition(cron_execution_ref, "ACK.ResourceSynced", "True", wait_periods=5)
+    return cron_execution_name, cron_execution_ref
+
+
+def owned_executions(cron_execution_name: str):
+    executions = k8s_client.CustomObjectsApi().list_namespaced_custom_object(
+        CRD_GROUP, CRD_VERSION, "default", EXECUTION_RESOURCE_PLURAL,
+        label_selector=f"app={cron_execution_name}",
+    )
+    return executions["items"]
+
+
+@service_marker
+class TestCronExecution:
+    def test_scheduled_executions_succeed(self, pass_state_machine):
+        cron_execution_name, cron_execution_ref = create_cron_execution(
+            pass_state_machine, "Allow",
+        )
+        try:
+            cr = k8s.get_resource(cron_execution_ref)
+            assert "nextScheduleTime" in cr["status"]
+
+            time.sleep(SCHEDULE_WAIT_SECONDS * 2)
+
+            cr = k8s.get_resource(cron_execution_ref)
+            assert "lastScheduleTime" in cr["status"]
+            assert "lastSuccessfulTime" in cr["status"]
+
+            # Only the most recent succeeded execution is kept.
+            executions = owned_executions(cron_execution_name)
+            succeeded = [
+                e for e in executions
+                if e.get("status", {}).get("status") == "SUCCEEDED"
+            ]
+            assert len(succeeded) == 1
+            owner = succeeded[0]["metadata"]["ownerReferences"][0]
+            assert owner["kind"] == "CronExecution"
+            assert owner["name"] == cron_execution_name
+        finally:
+            delete_resource(cron_execution_ref)
+
+    def test_suspend(self, pass_state_machine):
+        cron_execution_name, cron_execution_ref = create_cron_execution(
+            pass_state_machine, "Allow",
+        )
+        try:
+            k8s.patch_custom_resource(cron_execution_ref, {"spec": {"suspend": True}})
+            time.sleep(5)
+
+            cr = k8s.get_resource(cron_execution_ref)
+            assert "nextScheduleTime" not in cr["status"]
+            executions = len(owned_executions(cron_execution_name))
+
+            time.sleep(SCHEDULE_WAIT_SECONDS)
+
+            assert len(owned_executions(cron_execution_name)) == executions
+        finally:
+            delete_resource(cron_execution_ref)
+
+    def test_replace_stops_running_execution(self, wait_state_machine):
+        cron_execution_name, cron_execution_ref = create_cron_execution(
+            wait_state_machine, "Replace",
+        )
+        try:
+            time.sleep(SCHEDULE_WAIT_SECONDS)
+            first = owned_executions(cron_execution_name)
+            assert len(first) == 1
+
+            time.sleep(SCHEDULE_WAIT_SECONDS)
+
+            # The running execution was deleted, and so stopped, when the
+            # next one was started.
+            cr = k8s.get_resource(cron_execution_ref)
+            assert len(cr["status"]["active"]) == 1
+            assert cr["status"]["active"][0] != first[0]["metadata"]["name"]
+        finally:
+            delete_resource(cron_execution_ref)
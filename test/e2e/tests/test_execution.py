@@ -0,0 +1,319 @@
+# Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License"). You may
+# not use this file except in compliance with the License. A copy of the
+# License is located at
+#
+# 	 http://aws.amazon.com/apache2.0/
+#
+# or in the "license" file accompanying this file. This file is distributed
+# on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+# express or implied. See the License for the specific language governing
+# permissions and limitations under the License.
+
+"""Integration tests for the SFN Execution API.
+"""
+
+import json
+import pytest
+import time
+import logging
+
+from acktest.resources import random_suffix_name
+from acktest.k8s import resource as k8s
+from kubernetes import client as k8s_client
+from e2e import service_marker, CRD_GROUP, CRD_VERSION, load_sfn_resource
+from e2e.replacement_values import REPLACEMENT_VALUES
+from e2e.tests.helper import SFNHelper
+from e2e.bootstrap_resources import get_bootstrap_resources
+
+SM_RESOURCE_PLURAL = "statemachines"
+EXECUTION_RESOURCE_PLURAL = "executions"
+
+CREATE_WAIT_AFTER_SECONDS = 20
+DELETE_WAIT_AFTER_SECONDS = 30
+
+
+def create_state_machine(resource_file: str):
+    sm_name = random_suffix_name("sfn-sm-execution", 24)
+
+    replacements = REPLACEMENT_VALUES.copy()
+    replacements["STATE_MACHINE_NAME"] = sm_name
+    replacements["SFN_EXECUTION_ROLE_ARN"] = get_bootstrap_resources().SfnExecutionRole.arn
+
+    sm_data = load_sfn_resource(
+        resource_file,
+        additional_replacements=replacements,
+    )
+    logging.debug(sm_data)
+
+    sm_ref = k8s.CustomResourceReference(
+        CRD_GROUP, CRD_VERSION, SM_RESOURCE_PLURAL,
+        sm_name, namespace="default",
+    )
+    k8s.create_custom_resource(sm_ref, sm_data)
+    time.sleep(CREATE_WAIT_AFTER_SECONDS)
+
+    assert k8s.wait_resource_consumed_by_controller(sm_ref) is not None
+    assert k8s.wait_on_condition(sm_ref, "ACK.ResourceSynced", "True", wait_periods=5)
+    return sm_name, sm_ref
+
+
+def delete_resource(ref):
+    try:
+        _, deleted = k8s.delete_custom_resource(ref, 3, 10)
+        assert deleted
+    except:
+        pass
+
+
+@pytest.fixture
+def pass_state_machine():
+    sm_name, sm_ref = create_state_machine("state_machine")
+    yield sm_name
+    delete_resource(sm_ref)
+
+
+@pytest.fixture
+def wait_state_machine():
+    sm_name, sm_ref = create_state_machine("state_machine_wait")
+    yield sm_name
+    delete_resource(sm_ref)
+
+
+@pytest.fixture
+def fail_state_machine():
+    sm_name, sm_ref = create_state_machine("state_machine_fail")
+    yield sm_name, sm_ref
+    delete_resource(sm_ref)
+
+
+@pytest.fixture
+def express_state_machine():
+    sm_name, sm_ref = create_state_machine("state_machine_express_log_group")
+    yield sm_name
+    delete_resource(sm_ref)
+
+
+def create_execution(resource_file: str, replacements: dict):
+    execution_name = random_suffix_name("sfn-execution", 24)
+
+    replacements = {**REPLACEMENT_VALUES, **replacements}
+    replacements["EXECUTION_NAME"] = execution_name
+
+    execution_data = load_sfn_resource(
+        resource_file,
+        additional_replacements=replacements,
+    )
+    logging.debug(execution_data)
+
+    execution_ref = k8s.CustomResourceReference(
+        CRD_GROUP, CRD_VERSION, EXECUTION_RESOURCE_PLURAL,
+        execution_name, namespace="default",
+    )
+    k8s.create_custom_resource(execution_ref, execution_data)
+    time.sleep(CREATE_WAIT_AFTER_SECONDS)
+
+    assert k8s.wait_resource_consumed_by_controller(execution_ref) is not None
+    return execution_ref
+
+
+@service_marker
+class TestExecution:
+    def test_execution_succeeds(self, sfn_client, pass_state_machine):
+        execution_ref = create_execution(
+            "execution", {"STATE_MACHINE_NAME": pass_state_machine},
+        )
+        try:
+            assert k8s.wait_on_condition(execution_ref, "ACK.ResourceSynced", "True", wait_periods=10)
+            cr = k8s.get_resource(execution_ref)
+
+            assert cr["status"]["status"] == "SUCCEEDED"
+            assert json.loads(cr["status"]["output"]) == "Hello World!"
+            assert "startDate" in cr["status"]
+            assert "stopDate" in cr["status"]
+            # Without a name, the execution is named after the UID of the
+            # Execution so that it is started only once.
+            assert cr["spec"]["name"] == cr["metadata"]["uid"]
+
+            execution_arn = cr["status"]["ackResourceMetadata"]["arn"]
+            execution = SFNHelper(sfn_client).describe_execution(execution_arn)
+            assert execution is not None
+            assert execution["name"] == cr["metadata"]["uid"]
+            assert json.loads(execution["input"]) == {"message": "Hello from ACK"}
+        finally:
+            delete_resource(execution_ref)
+
+    def test_execution_input_from_config_map(self, sfn_client, pass_state_machine):
+        config_map_name = random_suffix_name("sfn-execution-input", 32)
+        execution_input = {"message": "Hello from a ConfigMap"}
+
+        core_v1 = k8s_client.CoreV1Api()
+        core_v1.create_namespaced_config_map(
+            "default",
+            k8s_client.V1ConfigMap(
+                metadata=k8s_client.V1ObjectMeta(name=config_map_name),
+                data={"input.json": json.dumps(execution_input)},
+            ),
+        )
+        execution_ref = None
+        try:
+            execution_ref = create_execution(
+                "execution_input_from",
+                {
+                    "STATE_MACHINE_NAME": pass_state_machine,
+                    "CONFIG_MAP_NAME": config_map_name,
+                },
+            )
+            assert k8s.wait_on_condition(execution_ref, "ACK.ResourceSynced", "True", wait_periods=10)
+            cr = k8s.get_resource(execution_ref)
+            assert cr["status"]["status"] == "SUCCEEDED"
+            assert "input" not in cr["spec"]
+
+            execution_arn = cr["status"]["ackResourceMetadata"]["arn"]
+            execution = SFNHelper(sfn_client).describe_execution(execution_arn)
+            assert json.loads(execution["input"]) == execution_input
+
+            # The input is only read when the execution starts, deleting the
+            # ConfigMap afterwards leaves the Execution synced.
+            core_v1.delete_namespaced_config_map(config_map_name, "default")
+            config_map_name = None
+            assert k8s.wait_on_condition(execution_ref, "ACK.ResourceSynced", "True", wait_periods=5)
+        finally:
+            if execution_ref is not None:
+                delete_resource(execution_ref)
+            if config_map_name is not None:
+                core_v1.delete_namespaced_config_map(config_map_name, "default")
+
+    def test_delete_running_execution_stops_it(self, sfn_client, wait_state_machine):
+        execution_ref = create_execution(
+            "execution", {"STATE_MACHINE_NAME": wait_state_machine},
+        )
+        # The status is read from DescribeExecution when the Execution is
+        # requeued after StartExecution.
+        for _ in range(10):
+            cr = k8s.get_resource(execution_ref)
+            if "status" in cr["status"]:
+                break
+            time.sleep(10)
+        assert cr["status"]["status"] == "RUNNING"
+        assert k8s.wait_on_condition(execution_ref, "ACK.ResourceSynced", "False", wait_periods=5)
+        execution_arn = cr["status"]["ackResourceMetadata"]["arn"]
+
+        _, deleted = k8s.delete_custom_resource(execution_ref, 3, 10)
+        assert deleted
+
+        time.sleep(DELETE_WAIT_AFTER_SECONDS)
+
+        execution = SFNHelper(sfn_client).describe_execution(execution_arn)
+        assert execution["status"] == "ABORTED"
+
+    def test_sync_execution(self, express_state_machine):
+        execution_ref = create_execution(
+            "execution_sync", {"STATE_MACHINE_NAME": express_state_machine},
+        )
+        try:
+            assert k8s.wait_on_condition(execution_ref, "ACK.ResourceSynced", "True", wait_periods=5)
+            cr = k8s.get_resource(execution_ref)
+
+            # The outcome of the execution is recorded when it is started.
+            assert cr["status"]["status"] == "SUCCEEDED"
+            assert json.loads(cr["status"]["output"]) == "Hello World!"
+            assert cr["status"]["billingDetails"]["billedDurationInMilliseconds"] > 0
+            assert cr["status"]["billingDetails"]["billedMemoryUsedInMB"] > 0
+            assert ":express:" in cr["status"]["ackResourceMetadata"]["arn"]
+        finally:
+            delete_resource(execution_ref)
+
+    def test_sync_execution_of_standard_state_machine(self, pass_state_machine):
+        execution_ref = create_execution(
+            "execution_sync", {"STATE_MACHINE_NAME": pass_state_machine},
+        )
+        try:
+            assert k8s.wait_on_condition(execution_ref, "ACK.Terminal", "True", wait_periods=5)
+            cr = k8s.get_resource(execution_ref)
+            assert "arn" not in cr["status"].get("ackResourceMetadata", {})
+        finally:
+            delete_resource(execution_ref)
+
+    def test_redrive_generation(self, sfn_client, fail_state_machine):
+        sm_name, _ = fail_state_machine
+        execution_ref = create_execution(
+            "execution", {"STATE_MACHINE_NAME": sm_name},
+        )
+        try:
+            assert k8s.wait_on_condition(execution_ref, "ACK.ResourceSynced", "True", wait_periods=10)
+            cr = k8s.get_resource(execution_ref)
+            assert cr["status"]["status"] == "FAILED"
+            assert cr["status"]["error"] == "DownstreamUnavailable"
+            assert cr["status"]["redriveStatus"] == "REDRIVABLE"
+            assert cr["status"]["redriveCount"] == 0
+
+            k8s.patch_custom_resource(execution_ref, {"spec": {"redriveGeneration": 1}})
+            time.sleep(CREATE_WAIT_AFTER_SECONDS)
+
+            # The execution fails again once redriven.
+            assert k8s.wait_on_condition(execution_ref, "ACK.ResourceSynced", "True", wait_periods=10)
+            cr = k8s.get_resource(execution_ref)
+            assert cr["status"]["observedRedriveGeneration"] == 1
+            assert cr["status"]["redriveCount"] == 1
+            assert "redriveDate" in cr["status"]
+            assert cr["status"]["status"] == "FAILED"
+
+            execution_arn = cr["status"]["ackResourceMetadata"]["arn"]
+            execution = SFNHelper(sfn_client).describe_execution(execution_arn)
+            assert execution["redriveCount"] == 1
+        finally:
+            delete_resource(execution_ref)
+
+    def test_redrive_failed_executions_since_annotation(self, sfn_client, fail_state_machine):
+        sm_name, sm_ref = fail_state_machine
+        sm_arn = k8s.get_resource(sm_ref)["status"]["ackResourceMetadata"]["arn"]
+        since = time.strftime("%Y-%m-%dT%H:%M:%SZ", time.gmtime())
+
+        execution_arn = sfn_client.start_execution(stateMachineArn=sm_arn)["executionArn"]
+        for _ in range(10):
+            execution = SFNHelper(sfn_client).describe_execution(execution_arn)
+            if execution["status"] == "FAILED":
+                break
+            time.sleep(5)
+        assert execution["status"] == "FAILED"
+
+        k8s.patch_custom_resource(sm_ref, {
+            "metadata": {
+                "annotations": {
+                    "sfn.services.k8s.aws/redrive-failed-executions-since": since,
+                },
+            },
+        })
+        time.sleep(CREATE_WAIT_AFTER_SECONDS)
+
+        execution = SFNHelper(sfn_client).describe_execution(execution_arn)
+        assert execution["redriveCount"] == 1
+
+    def test_execution_history_events(self, fail_state_machine):
+        sm_name, _ = fail_state_machine
+        execution_ref = create_execution(
+            "execution", {"STATE_MACHINE_NAME": sm_name},
+        )
+        try:
+            assert k8s.wait_on_condition(execution_ref, "ACK.ResourceSynced", "True", wait_periods=10)
+            cr = k8s.get_resource(execution_ref)
+            assert cr["status"]["status"] == "FAILED"
+            assert cr["status"]["lastHistoryEventID"] > 0
+
+            events = k8s_client.CoreV1Api().list_namespaced_event(
+                "default",
+                field_selector=(
+                    "involvedObject.kind=Execution,"
+                    f"involvedObject.name={execution_ref.name}"
+                ),
+            ).items
+            reasons = {event.reason: event for event in events}
+            assert "StateEntered" in reasons
+            assert reasons["StateEntered"].message == "Entered state Fail"
+            assert "ExecutionFailed" in reasons
+            assert reasons["ExecutionFailed"].type == "Warning"
+            assert "DownstreamUnavailable" in reasons["ExecutionFailed"].message
+        finally:
+            delete_resource(execution_ref)
@@ -14,11 +14,15 @@
 """Integration tests for the SFN StateMachine API.
 """
 
+import json
 import pytest
 import time
 import logging
 
 from acktest import tags
+from acktest.aws.identity import get_account_id, get_region
+from kubernetes import client as k8s_client
+from kubernetes.client.rest import ApiException
 from acktest.resources import random_suffix_name
 from acktest.k8s import resource as k8s
 from e2e import service_marker, CRD_GROUP, CRD_VERSION, load_sfn_resource
@@ -80,6 +84,7 @@ class TestStateMachine:
         sfn_helper = SFNHelper(sfn_client)
         # verify that state machine exists
         assert sfn_helper.state_machine_exists(state_machine_arn)
+        assert k8s.wait_on_condition(ref, "DefinitionValid", "True", wait_periods=5)
 
         state_machine_tags = sfn_helper.get_resource_tags(state_machine_arn)
         tags.assert_ack_system_tags(
@@ -148,4 +153,577 @@ class TestStateMachine:
 
         # Check state machine is deleting
         status = sfn_helper.get_state_machine_status(state_machine_arn)
-        assert status is None or status == "DELETING"
\ No newline at end of file
+        assert status is None or status == "DELETING"
+    def test_publish_versions(self, sfn_client, basic_state_machine):
+        (ref, cr) = basic_state_machine
+
+        state_machine_arn = cr["status"]["ackResourceMetadata"]["arn"]
+        sfn_helper = SFNHelper(sfn_client)
+        assert sfn_helper.state_machine_exists(state_machine_arn)
+        assert "revisionID" in cr["status"]
+
+        # Updating the definition with publish enabled publishes a version
+        new_definition = '{"StartAt":"HelloWorld","States":{"HelloWorld":{"Type":"Pass","Result":"Published!","End":true}}}'
+        updates = {
+            "spec": {
+                "definition": new_definition,
+                "publish": True,
+                "versionDescription": "first published version",
+            },
+        }
+        k8s.patch_custom_resource(ref, updates)
+        time.sleep(UPDATE_WAIT_AFTER_SECONDS)
+        assert k8s.wait_on_condition(ref, "ACK.ResourceSynced", "True", wait_periods=5)
+
+        cr = k8s.get_resource(ref)
+        version_arn = cr["status"]["latestVersionARN"]
+        assert version_arn.startswith(state_machine_arn + ":")
+
+        versions = sfn_helper.list_state_machine_versions(state_machine_arn)
+        assert version_arn in [v["stateMachineVersionArn"] for v in versions]
+
+        version = sfn_helper.get_state_machine(version_arn)
+        assert version["description"] == "first published version"
+        assert version["revisionId"] == cr["status"]["revisionID"]
+
+        _, deleted = k8s.delete_custom_resource(ref)
+        assert deleted is True
+        time.sleep(DELETE_WAIT_AFTER_SECONDS)
+
+    def test_version_retention(self, sfn_client, basic_state_machine):
+        (ref, cr) = basic_state_machine
+
+        state_machine_arn = cr["status"]["ackResourceMetadata"]["arn"]
+        sfn_helper = SFNHelper(sfn_client)
+        assert sfn_helper.state_machine_exists(state_machine_arn)
+
+        # Publish three versions while only keeping the last two
+        for result in ["v1", "v2", "v3"]:
+            definition = '{"StartAt":"HelloWorld","States":{"HelloWorld":{"Type":"Pass","Result":"%s","End":true}}}' % result
+            updates = {
+                "spec": {
+                    "definition": definition,
+                    "publish": True,
+                    "versionRetention": {"keepLast": 2},
+                },
+            }
+            k8s.patch_custom_resource(ref, updates)
+            time.sleep(UPDATE_WAIT_AFTER_SECONDS)
+            assert k8s.wait_on_condition(ref, "ACK.ResourceSynced", "True", wait_periods=5)
+
+        cr = k8s.get_resource(ref)
+        versions = sfn_helper.list_state_machine_versions(state_machine_arn)
+        assert len(versions) == 2
+        assert cr["status"]["latestVersionARN"] in [v["stateMachineVersionArn"] for v in versions]
+
+        _, deleted = k8s.delete_custom_resource(ref)
+        assert deleted is True
+        time.sleep(DELETE_WAIT_AFTER_SECONDS)
+
+    def test_invalid_definition_is_terminal(self):
+        resource_name = random_suffix_name("sfn-statemachine", 24)
+
+        replacements = REPLACEMENT_VALUES.copy()
+        replacements["STATE_MACHINE_NAME"] = resource_name
+        replacements["SFN_EXECUTION_ROLE_ARN"] = get_bootstrap_resources().SfnExecutionRole.arn
+
+        resource_data = load_sfn_resource(
+            "state_machine",
+            additional_replacements=replacements,
+        )
+        resource_data["spec"]["definition"] = '{"StartAt":"Missing","States":{}}'
+
+        ref = k8s.CustomResourceReference(
+            CRD_GROUP, CRD_VERSION, RESOURCE_PLURAL,
+            resource_name, namespace="default",
+        )
+        k8s.create_custom_resource(ref, resource_data)
+        time.sleep(CREATE_WAIT_AFTER_SECONDS)
+
+        assert k8s.wait_on_condition(ref, "ACK.Terminal", "True", wait_periods=5)
+        assert k8s.wait_on_condition(ref, "DefinitionValid", "False", wait_periods=5)
+        cr = k8s.get_resource(ref)
+        terminal = [c for c in cr["status"]["conditions"] if c["type"] == "ACK.Terminal"][0]
+        assert "definition validation failed" in terminal["message"]
+
+        diagnostics = cr["status"]["definitionDiagnostics"]
+        assert len(diagnostics) > 0
+        assert any(d["severity"] == "ERROR" for d in diagnostics)
+        for d in diagnostics:
+            assert d["code"]
+            assert d["message"]
+
+        _, deleted = k8s.delete_custom_resource(ref, 3, 10)
+        assert deleted
+
+    def test_unreachable_state_is_rejected_offline(self):
+        resource_name = random_suffix_name("sfn-statemachine", 24)
+
+        replacements = REPLACEMENT_VALUES.copy()
+        replacements["STATE_MACHINE_NAME"] = resource_name
+        replacements["SFN_EXECUTION_ROLE_ARN"] = get_bootstrap_resources().SfnExecutionRole.arn
+
+        resource_data = load_sfn_resource(
+            "state_machine",
+            additional_replacements=replacements,
+        )
+        resource_data["spec"]["definition"] = (
+            '{"StartAt":"A","States":{'
+            '"A":{"Type":"Pass","End":true},'
+            '"Orphan":{"Type":"Pass","End":true}}}'
+        )
+
+        ref = k8s.CustomResourceReference(
+            CRD_GROUP, CRD_VERSION, RESOURCE_PLURAL,
+            resource_name, namespace="default",
+        )
+        k8s.create_custom_resource(ref, resource_data)
+        time.sleep(CREATE_WAIT_AFTER_SECONDS)
+
+        assert k8s.wait_on_condition(ref, "DefinitionValid", "False", wait_periods=5)
+        cr = k8s.get_resource(ref)
+        diagnostics = cr["status"]["definitionDiagnostics"]
+        assert {
+            "code": "UNREACHABLE_STATE",
+            "location": "/States/Orphan",
+            "message": 'state "Orphan" is not reachable from StartAt "A"',
+            "severity": "ERROR",
+        } in diagnostics
+
+        _, deleted = k8s.delete_custom_resource(ref, 3, 10)
+        assert deleted
+
+    def test_definition_object(self, sfn_client):
+        resource_name = random_suffix_name("sfn-statemachine", 24)
+
+        replacements = REPLACEMENT_VALUES.copy()
+        replacements["STATE_MACHINE_NAME"] = resource_name
+        replacements["SFN_EXECUTION_ROLE_ARN"] = get_bootstrap_resources().SfnExecutionRole.arn
+
+        resource_data = load_sfn_resource(
+            "state_machine_definition_object",
+            additional_replacements=replacements,
+        )
+
+        ref = k8s.CustomResourceReference(
+            CRD_GROUP, CRD_VERSION, RESOURCE_PLURAL,
+            resource_name, namespace="default",
+        )
+        k8s.create_custom_resource(ref, resource_data)
+        time.sleep(CREATE_WAIT_AFTER_SECONDS)
+
+        cr = k8s.wait_resource_consumed_by_controller(ref)
+        assert cr is not None
+        assert k8s.wait_on_condition(ref, "ACK.ResourceSynced", "True", wait_periods=5)
+        assert "definition" not in cr["spec"]
+
+        cr = k8s.get_resource(ref)
+        state_machine_arn = cr["status"]["ackResourceMetadata"]["arn"]
+        sfn_helper = SFNHelper(sfn_client)
+        state_machine = sfn_helper.get_state_machine(state_machine_arn)
+        assert json.loads(state_machine["definition"]) == resource_data["spec"]["definitionObject"]
+
+        # An equal definition does not publish a new revision
+        revision_id = state_machine.get("revisionId")
+        updates = {
+            "spec": {
+                "definitionObject": {
+                    "States": resource_data["spec"]["definitionObject"]["States"],
+                    "StartAt": "HelloWorld",
+                },
+            },
+        }
+        k8s.patch_custom_resource(ref, updates)
+        time.sleep(UPDATE_WAIT_AFTER_SECONDS)
+        assert k8s.wait_on_condition(ref, "ACK.ResourceSynced", "True", wait_periods=5)
+        assert sfn_helper.get_state_machine(state_machine_arn).get("revisionId") == revision_id
+
+        updates["spec"]["definitionObject"]["States"]["HelloWorld"]["Result"] = "Updated!"
+        k8s.patch_custom_resource(ref, updates)
+        time.sleep(UPDATE_WAIT_AFTER_SECONDS)
+        assert k8s.wait_on_condition(ref, "ACK.ResourceSynced", "True", wait_periods=5)
+        state_machine = sfn_helper.get_state_machine(state_machine_arn)
+        assert json.loads(state_machine["definition"])["States"]["HelloWorld"]["Result"] == "Updated!"
+
+        _, deleted = k8s.delete_custom_resource(ref, 3, 10)
+        assert deleted
+
+    def test_definition_and_definition_object_are_exclusive(self):
+        resource_name = random_suffix_name("sfn-statemachine", 24)
+
+        replacements = REPLACEMENT_VALUES.copy()
+        replacements["STATE_MACHINE_NAME"] = resource_name
+        replacements["SFN_EXECUTION_ROLE_ARN"] = get_bootstrap_resources().SfnExecutionRole.arn
+
+        resource_data = load_sfn_resource(
+            "state_machine_definition_object",
+            additional_replacements=replacements,
+        )
+        resource_data["spec"]["definition"] = json.dumps(resource_data["spec"]["definitionObject"])
+
+        ref = k8s.CustomResourceReference(
+            CRD_GROUP, CRD_VERSION, RESOURCE_PLURAL,
+            resource_name, namespace="default",
+        )
+        with pytest.raises(ApiException) as e:
+            k8s.create_custom_resource(ref, resource_data)
+        assert e.value.status == 422
+        assert "exactly one of definition, definitionFrom or definitionObject must be set" in e.value.body
+
+    def test_definition_from_config_map(self, sfn_client):
+        resource_name = random_suffix_name("sfn-statemachine", 24)
+        config_map_name = random_suffix_name("sfn-definition", 24)
+
+        definition = {
+            "StartAt": "HelloWorld",
+            "States": {
+                "HelloWorld": {"Type": "Pass", "Result": "Hello World!", "End": True},
+            },
+        }
+        core_v1 = k8s_client.CoreV1Api()
+        core_v1.create_namespaced_config_map(
+            "default",
+            k8s_client.V1ConfigMap(
+                metadata=k8s_client.V1ObjectMeta(name=config_map_name),
+                data={"definition.json": json.dumps(definition)},
+            ),
+        )
+
+        replacements = REPLACEMENT_VALUES.copy()
+        replacements["STATE_MACHINE_NAME"] = resource_name
+        replacements["SFN_EXECUTION_ROLE_ARN"] = get_bootstrap_resources().SfnExecutionRole.arn
+        replacements["CONFIG_MAP_NAME"] = config_map_name
+
+        resource_data = load_sfn_resource(
+            "state_machine_definition_from",
+            additional_replacements=replacements,
+        )
+
+        ref = k8s.CustomResourceReference(
+            CRD_GROUP, CRD_VERSION, RESOURCE_PLURAL,
+            resource_name, namespace="default",
+        )
+        try:
+            k8s.create_custom_resource(ref, resource_data)
+            time.sleep(CREATE_WAIT_AFTER_SECONDS)
+
+            cr = k8s.wait_resource_consumed_by_controller(ref)
+            assert cr is not None
+            assert k8s.wait_on_condition(ref, "ACK.ResourceSynced", "True", wait_periods=5)
+            cr = k8s.get_resource(ref)
+            assert "definition" not in cr["spec"]
+
+            state_machine_arn = cr["status"]["ackResourceMetadata"]["arn"]
+            sfn_helper = SFNHelper(sfn_client)
+            state_machine = sfn_helper.get_state_machine(state_machine_arn)
+            assert json.loads(state_machine["definition"]) == definition
+
+            # Editing the ConfigMap updates the state machine without touching
+            # the StateMachine resource.
+            generation = cr["metadata"]["generation"]
+            definition["States"]["HelloWorld"]["Result"] = "Updated!"
+            core_v1.patch_namespaced_config_map(
+                config_map_name, "default",
+                {"data": {"definition.json": json.dumps(definition)}},
+            )
+            time.sleep(UPDATE_WAIT_AFTER_SECONDS)
+            assert k8s.wait_on_condition(ref, "ACK.ResourceSynced", "True", wait_periods=5)
+
+            state_machine = sfn_helper.get_state_machine(state_machine_arn)
+            assert json.loads(state_machine["definition"]) == definition
+            assert k8s.get_resource(ref)["metadata"]["generation"] == generation
+        finally:
+            _, deleted = k8s.delete_custom_resource(ref, 3, 10)
+            assert deleted
+            core_v1.delete_namespaced_config_map(config_map_name, "default")
+
+    def test_definition_substitutions(self, sfn_client):
+        resource_name = random_suffix_name("sfn-statemachine", 24)
+
+        replacements = REPLACEMENT_VALUES.copy()
+        replacements["STATE_MACHINE_NAME"] = resource_name
+        replacements["SFN_EXECUTION_ROLE_ARN"] = get_bootstrap_resources().SfnExecutionRole.arn
+
+        resource_data = load_sfn_resource(
+            "state_machine",
+            additional_replacements=replacements,
+        )
+        resource_data["spec"]["definition"] = (
+            '{"StartAt":"HelloWorld","States":{"HelloWorld":'
+            '{"Type":"Pass","Result":"${Greeting} from ${Namespace}","End":true}}}'
+        )
+        resource_data["spec"]["definitionSubstitutions"] = {
+            "Greeting": {"value": 'Say "Hello"'},
+            "Namespace": {
+                "fieldRef": {
+                    "apiVersion": "sfn.services.k8s.aws/v1alpha1",
+                    "kind": "StateMachine",
+                    "name": resource_name,
+                    "fieldPath": "metadata.namespace",
+                },
+            },
+        }
+
+        ref = k8s.CustomResourceReference(
+            CRD_GROUP, CRD_VERSION, RESOURCE_PLURAL,
+            resource_name, namespace="default",
+        )
+        k8s.create_custom_resource(ref, resource_data)
+        time.sleep(CREATE_WAIT_AFTER_SECONDS)
+
+        cr = k8s.wait_resource_consumed_by_controller(ref)
+        assert cr is not None
+        assert k8s.wait_on_condition(ref, "ACK.ResourceSynced", "True", wait_periods=5)
+
+        cr = k8s.get_resource(ref)
+        assert "${Greeting}" in cr["spec"]["definition"]
+        assert cr["status"]["definitionSubstitutionValues"] == {
+            "Greeting": 'Say "Hello"',
+            "Namespace": "default",
+        }
+        state_machine_arn = cr["status"]["ackResourceMetadata"]["arn"]
+        sfn_helper = SFNHelper(sfn_client)
+        definition = json.loads(sfn_helper.get_state_machine(state_machine_arn)["definition"])
+        assert definition["States"]["HelloWorld"]["Result"] == 'Say "Hello" from default'
+
+        # Changing a substitution updates the rendered definition
+        updates = {
+            "spec": {"definitionSubstitutions": {"Greeting": {"value": "Goodbye"}}},
+        }
+        k8s.patch_custom_resource(ref, updates)
+        time.sleep(UPDATE_WAIT_AFTER_SECONDS)
+        assert k8s.wait_on_condition(ref, "ACK.ResourceSynced", "True", wait_periods=5)
+        definition = json.loads(sfn_helper.get_state_machine(state_machine_arn)["definition"])
+        assert definition["States"]["HelloWorld"]["Result"] == "Goodbye from default"
+
+        # A placeholder without substitution is terminal
+        updates = {
+            "spec": {"definitionSubstitutions": {"Greeting": None}},
+        }
+        k8s.patch_custom_resource(ref, updates)
+        time.sleep(UPDATE_WAIT_AFTER_SECONDS)
+        assert k8s.wait_on_condition(ref, "ACK.Terminal", "True", wait_periods=5)
+        cr = k8s.get_resource(ref)
+        terminal = [c for c in cr["status"]["conditions"] if c["type"] == "ACK.Terminal"][0]
+        assert "unresolved definition substitutions: ${Greeting}" in terminal["message"]
+
+        _, deleted = k8s.delete_custom_resource(ref, 3, 10)
+        assert deleted
+
+    def test_resource_refs(self, sfn_client):
+        resource_name = random_suffix_name("sfn-statemachine", 24)
+        activity_name = random_suffix_name("sfn-activity", 24)
+
+        replacements = REPLACEMENT_VALUES.copy()
+        replacements["STATE_MACHINE_NAME"] = resource_name
+        replacements["SFN_EXECUTION_ROLE_ARN"] = get_bootstrap_resources().SfnExecutionRole.arn
+        replacements["ACTIVITY_NAME"] = activity_name
+
+        resource_data = load_sfn_resource(
+            "state_machine_resource_refs",
+            additional_replacements=replacements,
+        )
+        ref = k8s.CustomResourceReference(
+            CRD_GROUP, CRD_VERSION, RESOURCE_PLURAL,
+            resource_name, namespace="default",
+        )
+        activity_ref = k8s.CustomResourceReference(
+            CRD_GROUP, CRD_VERSION, "activities",
+            activity_name, namespace="default",
+        )
+
+        # The state machine is not synced while the activity does not exist
+        k8s.create_custom_resource(ref, resource_data)
+        time.sleep(CREATE_WAIT_AFTER_SECONDS)
+        assert k8s.wait_on_condition(ref, "ACK.ReferencesResolved", "False", wait_periods=5)
+        cr = k8s.get_resource(ref)
+        assert "arn" not in cr["status"].get("ackResourceMetadata", {})
+
+        activity_data = load_sfn_resource(
+            "activity",
+            additional_replacements=replacements,
+        )
+        k8s.create_custom_resource(activity_ref, activity_data)
+        activity_cr = k8s.wait_resource_consumed_by_controller(activity_ref)
+        assert k8s.wait_on_condition(activity_ref, "ACK.ResourceSynced", "True", wait_periods=5)
+        activity_arn = k8s.get_resource(activity_ref)["status"]["ackResourceMetadata"]["arn"]
+
+        assert k8s.wait_on_condition(ref, "ACK.ResourceSynced", "True", wait_periods=10)
+        cr = k8s.get_resource(ref)
+        assert cr["spec"]["definitionObject"]["States"]["Work"]["Resource"] == "${WorkActivity}"
+        assert cr["status"]["definitionSubstitutionValues"] == {"WorkActivity": activity_arn}
+
+        sfn_helper = SFNHelper(sfn_client)
+        state_machine = sfn_helper.get_state_machine(cr["status"]["ackResourceMetadata"]["arn"])
+        definition = json.loads(state_machine["definition"])
+        assert definition["States"]["Work"]["Resource"] == activity_arn
+
+        _, deleted = k8s.delete_custom_resource(ref, 3, 10)
+        assert deleted
+        _, deleted = k8s.delete_custom_resource(activity_ref, 3, 10)
+        assert deleted
+
+    def test_execution_role_generation(self):
+        resource_name = random_suffix_name("sfn-statemachine", 24)
+        region = get_region()
+        account_id = get_account_id()
+
+        replacements = REPLACEMENT_VALUES.copy()
+        replacements["STATE_MACHINE_NAME"] = resource_name
+        replacements["AWS_REGION"] = region
+        replacements["AWS_ACCOUNT_ID"] = account_id
+
+        resource_data = load_sfn_resource(
+            "state_machine_execution_role_generation",
+            additional_replacements=replacements,
+        )
+        ref = k8s.CustomResourceReference(
+            CRD_GROUP, CRD_VERSION, RESOURCE_PLURAL,
+            resource_name, namespace="default",
+        )
+        k8s.create_custom_resource(ref, resource_data)
+        cr = k8s.wait_resource_consumed_by_controller(ref)
+        assert cr is not None
+        time.sleep(CREATE_WAIT_AFTER_SECONDS)
+
+        generated_name = f"{resource_name}-execution-role"
+        role_ref = k8s.CustomResourceReference(
+            "iam.services.k8s.aws", "v1alpha1", "roles",
+            generated_name, namespace="default",
+        )
+        policy_ref = k8s.CustomResourceReference(
+            "iam.services.k8s.aws", "v1alpha1", "policies",
+            generated_name, namespace="default",
+        )
+        role = k8s.get_resource(role_ref)
+        policy = k8s.get_resource(policy_ref)
+        assert role is not None
+        assert policy is not None
+
+        for obj in (role, policy):
+            owner = obj["metadata"]["ownerReferences"][0]
+            assert owner["kind"] == "StateMachine"
+            assert owner["name"] == resource_name
+
+        trust = json.loads(role["spec"]["assumeRolePolicyDocument"])
+        assert trust["Statement"][0]["Principal"] == {"Service": "states.amazonaws.com"}
+        assert role["spec"]["policyRefs"] == [{"from": {"name": generated_name}}]
+
+        document = json.loads(policy["spec"]["policyDocument"])
+        assert document["Statement"] == [{
+            "Effect": "Allow",
+            "Action": ["sqs:SendMessage"],
+            "Resource": [f"arn:aws:sqs:{region}:{account_id}:{resource_name}"],
+        }]
+
+        _, deleted = k8s.delete_custom_resource(ref, 3, 10)
+        assert deleted
+
+    def test_role_service_account_ref(self, sfn_client):
+        resource_name = random_suffix_name("sfn-statemachine", 24)
+        service_account_name = random_suffix_name("sfn-workload", 24)
+        role_arn = get_bootstrap_resources().SfnExecutionRole.arn
+
+        core_v1 = k8s_client.CoreV1Api()
+        core_v1.create_namespaced_service_account(
+            "default",
+            k8s_client.V1ServiceAccount(
+                metadata=k8s_client.V1ObjectMeta(name=service_account_name),
+            ),
+        )
+
+        replacements = REPLACEMENT_VALUES.copy()
+        replacements["STATE_MACHINE_NAME"] = resource_name
+        replacements["SERVICE_ACCOUNT_NAME"] = service_account_name
+
+        resource_data = load_sfn_resource(
+            "state_machine_role_service_account_ref",
+            additional_replacements=replacements,
+        )
+        ref = k8s.CustomResourceReference(
+            CRD_GROUP, CRD_VERSION, RESOURCE_PLURAL,
+            resource_name, namespace="default",
+        )
+        try:
+            # The state machine is not created while the ServiceAccount has
+            # no IAM role annotation.
+            k8s.create_custom_resource(ref, resource_data)
+            time.sleep(CREATE_WAIT_AFTER_SECONDS)
+            assert k8s.wait_on_condition(ref, "ACK.Terminal", "True", wait_periods=5)
+            cr = k8s.get_resource(ref)
+            terminal = next(c for c in cr["status"]["conditions"] if c["type"] == "ACK.Terminal")
+            assert "eks.amazonaws.com/role-arn" in terminal["message"]
+            assert "arn" not in cr["status"].get("ackResourceMetadata", {})
+
+            core_v1.patch_namespaced_service_account(
+                service_account_name, "default",
+                {"metadata": {"annotations": {"eks.amazonaws.com/role-arn": role_arn}}},
+            )
+            assert k8s.wait_on_condition(ref, "ACK.ResourceSynced", "True", wait_periods=10)
+            cr = k8s.get_resource(ref)
+            assert "roleARN" not in cr["spec"]
+            assert cr["status"]["resolvedRoleARN"] == role_arn
+
+            sfn_helper = SFNHelper(sfn_client)
+            state_machine = sfn_helper.get_state_machine(cr["status"]["ackResourceMetadata"]["arn"])
+            assert state_machine["roleArn"] == role_arn
+        finally:
+            _, deleted = k8s.delete_custom_resource(ref, 3, 10)
+            assert deleted
+            core_v1.delete_namespaced_service_account(service_account_name, "default")
+
+    def test_express_requires_log_destination(self):
+        resource_name = random_suffix_name("sfn-statemachine", 24)
+
+        replacements = REPLACEMENT_VALUES.copy()
+        replacements["STATE_MACHINE_NAME"] = resource_name
+        replacements["SFN_EXECUTION_ROLE_ARN"] = get_bootstrap_resources().SfnExecutionRole.arn
+
+        resource_data = load_sfn_resource(
+            "state_machine_express",
+            additional_replacements=replacements,
+        )
+        ref = k8s.CustomResourceReference(
+            CRD_GROUP, CRD_VERSION, RESOURCE_PLURAL,
+            resource_name, namespace="default",
+        )
+        k8s.create_custom_resource(ref, resource_data)
+        time.sleep(CREATE_WAIT_AFTER_SECONDS)
+
+        assert k8s.wait_on_condition(ref, "ACK.Terminal", "True", wait_periods=5)
+        cr = k8s.get_resource(ref)
+        terminal = [c for c in cr["status"]["conditions"] if c["type"] == "ACK.Terminal"][0]
+        assert "EXPRESS state machines must have a logging destination" in terminal["message"]
+        assert "arn" not in cr["status"].get("ackResourceMetadata", {})
+
+        _, deleted = k8s.delete_custom_resource(ref, 3, 10)
+        assert deleted
+
+    def test_log_group_generation_and_destinations_are_exclusive(self):
+        resource_name = random_suffix_name("sfn-statemachine", 24)
+
+        replacements = REPLACEMENT_VALUES.copy()
+        replacements["STATE_MACHINE_NAME"] = resource_name
+        replacements["SFN_EXECUTION_ROLE_ARN"] = get_bootstrap_resources().SfnExecutionRole.arn
+
+        resource_data = load_sfn_resource(
+            "state_machine_express",
+            additional_replacements=replacements,
+        )
+        resource_data["spec"]["logGroupGeneration"] = {"retentionDays": 7}
+        resource_data["spec"]["loggingConfiguration"] = {
+            "level": "ALL",
+            "destinations": [{
+                "cloudWatchLogsLogGroup": {
+                    "logGroupARN": "arn:aws:logs:us-west-2:123456789012:log-group:example:*",
+                },
+            }],
+        }
+        ref = k8s.CustomResourceReference(
+            CRD_GROUP, CRD_VERSION, RESOURCE_PLURAL,
+            resource_name, namespace="default",
+        )
+        with pytest.raises(ApiException) as e:
+            k8s.create_custom_resource(ref, resource_data)
+        assert e.value.status == 422
+        assert "logGroupGeneration cannot be set with loggingConfiguration.destinations" in e.value.body
@@ -18,6 +18,7 @@ import pytest
 import time
 import logging
 
+from kubernetes.client.rest import ApiException
 from acktest.resources import random_suffix_name
 from acktest.k8s import resource as k8s
 from e2e import service_marker, CRD_GROUP, CRD_VERSION, load_sfn_resource
@@ -27,6 +28,7 @@ from e2e.bootstrap_resources import get_bootstrap_resources
 
 SM_RESOURCE_PLURAL = "statemachines"
 ALIAS_RESOURCE_PLURAL = "statemachinealiases"
+VERSION_RESOURCE_PLURAL = "statemachineversions"
 
 CREATE_WAIT_AFTER_SECONDS = 20
 UPDATE_WAIT_AFTER_SECONDS = 10
@@ -166,3 +168,215 @@ class TestStateMachineAlias:
         alias_details = sfn_helper.describe_state_machine_alias(alias_arn)
         assert alias_details is not None
         assert alias_details["description"] == "Updated production traffic"
+
+    def test_routing_references(self, sfn_client, state_machine_with_alias):
+        """Test an alias routing to versions through StateMachineVersion and StateMachine references."""
+        (sm_ref, sm_cr, alias_ref, alias_cr, version_arn) = state_machine_with_alias
+
+        sfn_helper = SFNHelper(sfn_client)
+        sm_name = sm_ref.name
+        first_version_number = version_arn.rsplit(":", 1)[1]
+
+        # Publish a second version through a StateMachineVersion CR
+        new_definition = '{"StartAt":"HelloWorld","States":{"HelloWorld":{"Type":"Pass","Result":"v2","End":true}}}'
+        k8s.patch_custom_resource(sm_ref, {"spec": {"definition": new_definition}})
+        time.sleep(UPDATE_WAIT_AFTER_SECONDS)
+        assert k8s.wait_on_condition(sm_ref, "ACK.ResourceSynced", "True", wait_periods=5)
+
+        version_name = random_suffix_name("sfn-version", 24)
+        version_replacements = REPLACEMENT_VALUES.copy()
+        version_replacements["STATE_MACHINE_VERSION_NAME"] = version_name
+        version_replacements["STATE_MACHINE_NAME"] = sm_name
+        version_replacements["VERSION_DESCRIPTION"] = "Canary version"
+        version_data = load_sfn_resource(
+            "state_machine_version",
+            additional_replacements=version_replacements,
+        )
+        version_ref = k8s.CustomResourceReference(
+            CRD_GROUP, CRD_VERSION, VERSION_RESOURCE_PLURAL,
+            version_name, namespace="default",
+        )
+        k8s.create_custom_resource(version_ref, version_data)
+        time.sleep(CREATE_WAIT_AFTER_SECONDS)
+        assert k8s.wait_on_condition(version_ref, "ACK.ResourceSynced", "True", wait_periods=5)
+        new_version_arn = k8s.get_resource(version_ref)["status"]["ackResourceMetadata"]["arn"]
+
+        alias_name = random_suffix_name("sfn-alias-ref", 24)
+        alias_replacements = REPLACEMENT_VALUES.copy()
+        alias_replacements["STATE_MACHINE_ALIAS_NAME"] = alias_name
+        alias_replacements["ALIAS_NAME"] = "canary"
+        alias_replacements["STATE_MACHINE_VERSION_NAME"] = version_name
+        alias_replacements["STATE_MACHINE_NAME"] = sm_name
+        alias_replacements["VERSION_NUMBER"] = first_version_number
+        alias_data = load_sfn_resource(
+            "state_machine_alias_version_ref",
+            additional_replacements=alias_replacements,
+        )
+        ref_alias_ref = k8s.CustomResourceReference(
+            CRD_GROUP, CRD_VERSION, ALIAS_RESOURCE_PLURAL,
+            alias_name, namespace="default",
+        )
+        k8s.create_custom_resource(ref_alias_ref, alias_data)
+        time.sleep(CREATE_WAIT_AFTER_SECONDS)
+        assert k8s.wait_on_condition(ref_alias_ref, "ACK.ResourceSynced", "True", wait_periods=5)
+
+        ref_alias_arn = k8s.get_resource(ref_alias_ref)["status"]["ackResourceMetadata"]["arn"]
+        alias_details = sfn_helper.describe_state_machine_alias(ref_alias_arn)
+        routes = {
+            r["stateMachineVersionArn"]: r["weight"]
+            for r in alias_details["routingConfiguration"]
+        }
+        assert routes == {new_version_arn: 90, version_arn: 10}
+
+        _, deleted = k8s.delete_custom_resource(ref_alias_ref)
+        assert deleted is True
+        time.sleep(DELETE_WAIT_AFTER_SECONDS)
+        _, deleted = k8s.delete_custom_resource(version_ref)
+        assert deleted is True
+
+    def test_canary_rollout(self, sfn_client, state_machine_with_alias):
+        """Test shifting alias traffic to a new version with a canary rollout."""
+        (sm_ref, sm_cr, alias_ref, alias_cr, version_arn) = state_machine_with_alias
+
+        sfn_helper = SFNHelper(sfn_client)
+        sm_arn = sm_cr["status"]["ackResourceMetadata"]["arn"]
+        alias_arn = alias_cr["status"]["ackResourceMetadata"]["arn"]
+
+        new_definition = '{"StartAt":"HelloWorld","States":{"HelloWorld":{"Type":"Pass","Result":"canary","End":true}}}'
+        k8s.patch_custom_resource(sm_ref, {"spec": {"definition": new_definition}})
+        time.sleep(UPDATE_WAIT_AFTER_SECONDS)
+        assert k8s.wait_on_condition(sm_ref, "ACK.ResourceSynced", "True", wait_periods=5)
+        new_version_arn = sfn_helper.publish_state_machine_version(sm_arn, "Canary")["stateMachineVersionArn"]
+
+        updates = {
+            "spec": {
+                "rollout": {"type": "Canary", "stepPercentage": 10, "interval": "60s"},
+                "routingConfiguration": [
+                    {"stateMachineVersionARN": new_version_arn, "weight": 100},
+                ],
+            },
+        }
+        k8s.patch_custom_resource(alias_ref, updates)
+        time.sleep(UPDATE_WAIT_AFTER_SECONDS)
+
+        # First step routes 10% of the traffic to the new version
+        alias_cr = k8s.get_resource(alias_ref)
+        assert alias_cr["status"]["rollout"]["currentStep"] == 1
+        assert alias_cr["status"]["rollout"]["targetWeight"] == 10
+        assert "nextTransitionTime" in alias_cr["status"]["rollout"]
+        routes = {
+            r["stateMachineVersionArn"]: r["weight"]
+            for r in sfn_helper.describe_state_machine_alias(alias_arn)["routingConfiguration"]
+        }
+        assert routes == {new_version_arn: 10, version_arn: 90}
+
+        # After the interval all of the traffic is routed to the new version
+        assert k8s.wait_on_condition(alias_ref, "ACK.ResourceSynced", "True", wait_periods=12)
+        alias_cr = k8s.get_resource(alias_ref)
+        assert alias_cr["status"]["rollout"]["targetWeight"] == 100
+        routes = {
+            r["stateMachineVersionArn"]: r["weight"]
+            for r in sfn_helper.describe_state_machine_alias(alias_arn)["routingConfiguration"]
+        }
+        assert routes == {new_version_arn: 100}
+
+    def test_rollout_rollback(self, sfn_client, state_machine_with_alias):
+        """Test rolling back a rollout when executions of the new version fail."""
+        (sm_ref, sm_cr, alias_ref, alias_cr, version_arn) = state_machine_with_alias
+
+        sfn_helper = SFNHelper(sfn_client)
+        sm_arn = sm_cr["status"]["ackResourceMetadata"]["arn"]
+        alias_arn = alias_cr["status"]["ackResourceMetadata"]["arn"]
+
+        failing_definition = '{"StartAt":"Fail","States":{"Fail":{"Type":"Fail","Error":"Broken"}}}'
+        k8s.patch_custom_resource(sm_ref, {"spec": {"definition": failing_definition}})
+        time.sleep(UPDATE_WAIT_AFTER_SECONDS)
+        assert k8s.wait_on_condition(sm_ref, "ACK.ResourceSynced", "True", wait_periods=5)
+        bad_version_arn = sfn_helper.publish_state_machine_version(sm_arn, "Broken")["stateMachineVersionArn"]
+
+        updates = {
+            "spec": {
+                "rollout": {
+                    "type": "Linear",
+                    "stepPercentage": 50,
+                    "interval": "10m",
+                    "rollback": {"failureThresholdPercentage": 20, "minimumExecutions": 2},
+                },
+                "routingConfiguration": [
+                    {"stateMachineVersionARN": bad_version_arn, "weight": 100},
+                ],
+            },
+        }
+        k8s.patch_custom_resource(alias_ref, updates)
+        time.sleep(UPDATE_WAIT_AFTER_SECONDS)
+        assert k8s.get_resource(alias_ref)["status"]["rollout"]["targetWeight"] == 50
+
+        for _ in range(10):
+            sfn_helper.start_execution(alias_arn)
+
+        # The controller samples executions every 30 seconds during a rollout
+        assert k8s.wait_on_condition(alias_ref, "ACK.Terminal", "True", wait_periods=12)
+        alias_cr = k8s.get_resource(alias_ref)
+        assert alias_cr["status"]["rollout"]["rolledBack"]
+        routes = {
+            r["stateMachineVersionArn"]: r["weight"]
+            for r in sfn_helper.describe_state_machine_alias(alias_arn)["routingConfiguration"]
+        }
+        assert routes == {version_arn: 100}
+
+    @pytest.mark.parametrize("routing_configuration,alias_name,message", [
+        (
+            [{"stateMachineVersionARN": "arn:aws:states:us-west-2:111122223333:stateMachine:sm:1", "weight": 60}],
+            "production",
+            "the weight of a single routing configuration entry must be 100",
+        ),
+        (
+            [
+                {"stateMachineVersionARN": "arn:aws:states:us-west-2:111122223333:stateMachine:sm:1", "weight": 60},
+                {"stateMachineVersionARN": "arn:aws:states:us-west-2:111122223333:stateMachine:sm:2", "weight": 60},
+            ],
+            "production",
+            "routing configuration weights must sum to 100",
+        ),
+        (
+            [
+                {"stateMachineVersionARN": "arn:aws:states:us-west-2:111122223333:stateMachine:sm:1", "weight": 50},
+                {"stateMachineVersionARN": "arn:aws:states:us-west-2:111122223333:stateMachine:sm:1", "weight": 50},
+            ],
+            "production",
+            "routing configuration entries must route to different versions",
+        ),
+        (
+            [
+                {"stateMachineVersionARN": "arn:aws:states:us-west-2:111122223333:stateMachine:sm-a:1", "weight": 50},
+                {"stateMachineVersionARN": "arn:aws:states:us-west-2:111122223333:stateMachine:sm-b:1", "weight": 50},
+            ],
+            "production",
+            "routing configuration entries must route to versions of the same state machine",
+        ),
+        (
+            [{"stateMachineVersionARN": "arn:aws:states:us-west-2:111122223333:stateMachine:sm:1", "weight": 100}],
+            "123",
+            "alias name must not be an integer",
+        ),
+    ])
+    def test_invalid_routing_configuration(self, routing_configuration, alias_name, message):
+        """Test that invalid routing configurations are rejected at admission."""
+        resource_name = random_suffix_name("sfn-alias-invalid", 32)
+        alias_data = {
+            "apiVersion": f"{CRD_GROUP}/{CRD_VERSION}",
+            "kind": "StateMachineAlias",
+            "metadata": {"name": resource_name},
+            "spec": {
+                "name": alias_name,
+                "routingConfiguration": routing_configuration,
+            },
+        }
+        ref = k8s.CustomResourceReference(
+            CRD_GROUP, CRD_VERSION, ALIAS_RESOURCE_PLURAL,
+            resource_name, namespace="default",
+        )
+        with pytest.raises(ApiException) as e:
+            k8s.create_custom_resource(ref, alias_data)
+        assert e.value.status == 422
+        assert message in e.value.body
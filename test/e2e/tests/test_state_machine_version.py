@@ -0,0 +1,129 @@
+# Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License"). You may
+# not use this file except in compliance with the License. A copy of the
+# License is located at
+#
+# 	 http://aws.amazon.com/apache2.0/
+#
+# or in the "license" file accompanying this file. This file is distributed
+# on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+# express or implied. See the License for the specific language governing
+# permissions and limitations under the License.
+
+"""Integration tests for the SFN StateMachineVersion API.
+"""
+
+import pytest
+import time
+import logging
+
+from acktest.resources import random_suffix_name
+from acktest.k8s import resource as k8s
+from e2e import service_marker, CRD_GROUP, CRD_VERSION, load_sfn_resource
+from e2e.replacement_values import REPLACEMENT_VALUES
+from e2e.tests.helper import SFNHelper
+from e2e.bootstrap_resources import get_bootstrap_resources
+
+SM_RESOURCE_PLURAL = "statemachines"
+VERSION_RESOURCE_PLURAL = "statemachineversions"
+
+CREATE_WAIT_AFTER_SECONDS = 20
+DELETE_WAIT_AFTER_SECONDS = 60
+
+
+@pytest.fixture
+def state_machine_with_version():
+    """Create a state machine and a StateMachineVersion referencing it."""
+    sm_name = random_suffix_name("sfn-sm-version", 24)
+
+    sm_replacements = REPLACEMENT_VALUES.copy()
+    sm_replacements["STATE_MACHINE_NAME"] = sm_name
+    sm_replacements["SFN_EXECUTION_ROLE_ARN"] = get_bootstrap_resources().SfnExecutionRole.arn
+
+    sm_data = load_sfn_resource(
+        "state_machine",
+        additional_replacements=sm_replacements,
+    )
+    logging.debug(sm_data)
+
+    sm_ref = k8s.CustomResourceReference(
+        CRD_GROUP, CRD_VERSION, SM_RESOURCE_PLURAL,
+        sm_name, namespace="default",
+    )
+    k8s.create_custom_resource(sm_ref, sm_data)
+    time.sleep(CREATE_WAIT_AFTER_SECONDS)
+
+    sm_cr = k8s.wait_resource_consumed_by_controller(sm_ref)
+    assert sm_cr is not None
+
+    version_name = random_suffix_name("sfn-version", 24)
+
+    version_replacements = REPLACEMENT_VALUES.copy()
+    version_replacements["STATE_MACHINE_VERSION_NAME"] = version_name
+    version_replacements["STATE_MACHINE_NAME"] = sm_name
+    version_replacements["VERSION_DESCRIPTION"] = "Version published by ACK"
+
+    version_data = load_sfn_resource(
+        "state_machine_version",
+        additional_replacements=version_replacements,
+    )
+    logging.debug(version_data)
+
+    version_ref = k8s.CustomResourceReference(
+        CRD_GROUP, CRD_VERSION, VERSION_RESOURCE_PLURAL,
+        version_name, namespace="default",
+    )
+    k8s.create_custom_resource(version_ref, version_data)
+    time.sleep(CREATE_WAIT_AFTER_SECONDS)
+
+    version_cr = k8s.wait_resource_consumed_by_controller(version_ref)
+    assert version_cr is not None
+    assert k8s.get_resource_exists(version_ref)
+
+    yield (sm_ref, sm_cr, version_ref, version_cr)
+
+    # Cleanup: delete the version first, then the state machine
+    try:
+        _, deleted = k8s.delete_custom_resource(version_ref, 3, 10)
+        assert deleted
+    except:
+        pass
+
+    try:
+        _, deleted = k8s.delete_custom_resource(sm_ref, 3, 10)
+        assert deleted
+    except:
+        pass
+
+
+@service_marker
+class TestStateMachineVersion:
+    def test_create_and_delete(self, sfn_client, state_machine_with_version):
+        (sm_ref, sm_cr, version_ref, version_cr) = state_machine_with_version
+
+        sfn_helper = SFNHelper(sfn_client)
+        sm_arn = sm_cr["status"]["ackResourceMetadata"]["arn"]
+
+        assert k8s.wait_on_condition(version_ref, "ACK.ResourceSynced", "True", wait_periods=5)
+        version_cr = k8s.get_resource(version_ref)
+
+        version_arn = version_cr["status"]["ackResourceMetadata"]["arn"]
+        assert version_arn == f"{sm_arn}:{version_cr['status']['versionNumber']}"
+        assert "revisionID" in version_cr["spec"]
+        assert "creationDate" in version_cr["status"]
+
+        versions = sfn_helper.list_state_machine_versions(sm_arn)
+        assert version_arn in [v["stateMachineVersionArn"] for v in versions]
+
+        version = sfn_helper.get_state_machine(version_arn)
+        assert version["description"] == "Version published by ACK"
+
+        # Delete the version CR
+        _, deleted = k8s.delete_custom_resource(version_ref)
+        assert deleted is True
+
+        time.sleep(DELETE_WAIT_AFTER_SECONDS)
+
+        versions = sfn_helper.list_state_machine_versions(sm_arn)
+        assert version_arn not in [v["stateMachineVersionArn"] for v in versions]